@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	calendarHours        float64
+	calendarOutput       string
+	calendarMinElevation string
+	calendarStep         time.Duration
+)
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Write the watchlist's pass schedule as an iCalendar file",
+	Long: `Predict the passes of every watchlisted satellite over the configured observer
+in the coming hours and write them as one iCalendar (.ics) file, with an event
+per pass named after the satellite, for import into calendar applications.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCalendar()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(calendarCmd)
+	calendarCmd.Flags().Float64Var(&calendarHours, "hours", 72, "How many hours ahead to schedule")
+	calendarCmd.Flags().StringVarP(&calendarOutput, "output", "o", "passes.ics", "File to write, or - for stdout")
+	calendarCmd.Flags().StringVar(&calendarMinElevation, "min-elevation", "", "Minimum elevation angle in degrees, or 'horizon' for 0 (default from config)")
+	calendarCmd.Flags().DurationVar(&calendarStep, "step", 30*time.Second, "Sampling interval for the pass search")
+}
+
+func runCalendar() {
+	if calendarHours <= 0 {
+		log.Fatalf("Hours must be positive: %v", calendarHours)
+	}
+	if calendarStep <= 0 {
+		log.Fatalf("Step must be positive: %v", calendarStep)
+	}
+	minElevation, err := resolveMinElevation(calendarMinElevation)
+	if err != nil {
+		log.Fatalf("Invalid --min-elevation: %v", err)
+	}
+
+	observer := currentObserver()
+	if observer == nil {
+		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+		return
+	}
+
+	if len(config.Watchlist) == 0 {
+		fmt.Println("Watchlist is empty. Add satellites with 'icu watchlist add NORAD_ID'.")
+		return
+	}
+
+	catalog, err := newCatalogStore().Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	watched := satellite.SearchSatellites(catalog.Satellites, satellite.SearchCriteria{NoradIDs: config.Watchlist})
+	if len(watched) < len(config.Watchlist) {
+		fmt.Fprintf(os.Stderr, "Warning: %d watchlisted satellites are not in the catalog\n", len(config.Watchlist)-len(watched))
+	}
+
+	start := time.Now()
+	end := start.Add(time.Duration(calendarHours * float64(time.Hour)))
+	schedule, errs := satellite.PredictSchedule(watched, observer, start, end, calendarStep, minElevation, newProgressBar("Predicting"))
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if calendarOutput == "-" {
+		if err := satellite.WritePassCalendar(os.Stdout, schedule); err != nil {
+			log.Fatalf("Error writing calendar: %v", err)
+		}
+		return
+	}
+
+	f, err := os.Create(calendarOutput)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", calendarOutput, err)
+	}
+	if err := satellite.WritePassCalendar(f, schedule); err != nil {
+		f.Close()
+		log.Fatalf("Error writing calendar: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Failed to write %s: %v", calendarOutput, err)
+	}
+	fmt.Printf("%d passes of %d satellites over the next %v hours written to %s\n",
+		len(schedule), len(watched), calendarHours, calendarOutput)
+}
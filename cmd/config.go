@@ -11,7 +11,8 @@ import (
 
 // InitConfig initializes the configuration using Viper and returns a satellite.Config.
 // This function handles CLI-specific configuration loading from files.
-func InitConfig() (*satellite.Config, error) {
+// If configFile is non-empty it is read instead of ~/.icu/config.yaml and must exist.
+func InitConfig(configFile string) (*satellite.Config, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
@@ -23,32 +24,58 @@ func InitConfig() (*satellite.Config, error) {
 	}
 
 	// Set config file details
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(configDir)
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(configDir)
+	}
 
 	// Get defaults from library
 	defaults := satellite.DefaultConfig()
 
 	// Set Viper defaults
 	viper.SetDefault("data_dir", configDir)
+	viper.SetDefault("catalog_format", defaults.CatalogFormat)
+	viper.SetDefault("compress_catalog", defaults.CompressCatalog)
+	viper.SetDefault("catalog_backend", defaults.CatalogBackend)
 	viper.SetDefault("auto_fetch", defaults.AutoFetch)
 	viper.SetDefault("api_timeout", defaults.APITimeout)
+	viper.SetDefault("api_retries", defaults.APIRetries)
+	viper.SetDefault("api_retry_delay", defaults.APIRetryDelay)
 	viper.SetDefault("max_catalog_age", defaults.MaxCatalogAge)
 	viper.SetDefault("tle_endpoint", defaults.TLEEndpoint)
 	viper.SetDefault("satcat_endpoint", defaults.SATCATEndpoint)
+	viper.SetDefault("tle_fallback_endpoints", defaults.TLEFallbacks)
+	viper.SetDefault("satcat_fallback_endpoints", defaults.SATCATFallbacks)
+	viper.SetDefault("tle_object_endpoint", defaults.TLEObjectEndpoint)
 	viper.SetDefault("observer_latitude", defaults.ObserverLatitude)
 	viper.SetDefault("observer_longitude", defaults.ObserverLongitude)
 	viper.SetDefault("observer_altitude", defaults.ObserverAltitude)
+	viper.SetDefault("default_min_elevation", defaults.DefaultMinElevation)
+	viper.SetDefault("watchlist", []int{})
+	viper.SetDefault("json_decimals", defaults.JSONDecimals)
+	viper.SetDefault("json_naming", defaults.JSONNaming)
+	viper.SetDefault("propagation_workers", defaults.PropagationWorkers)
+	viper.SetDefault("tle_history", defaults.TLEHistory)
+	viper.SetDefault("max_tle_age", defaults.MaxTLEAge)
+	viper.SetDefault("include_satcat_only", defaults.IncludeSATCATOnly)
+	viper.SetDefault("catalog_snapshots", defaults.CatalogSnapshots)
+	viper.SetDefault("max_snapshots", defaults.MaxSnapshots)
 
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
+		if configFile != "" {
+			return nil, fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// Config file not found; create it with defaults
 			configPath := filepath.Join(configDir, "config.yaml")
 			if err := viper.SafeWriteConfigAs(configPath); err != nil {
 				return nil, fmt.Errorf("failed to create config file: %w", err)
 			}
+			viper.SetConfigFile(configPath)
 		} else {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
@@ -59,5 +86,16 @@ func InitConfig() (*satellite.Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Accept observer longitudes in either the 0-360 or -180-180 convention
+	observer := satellite.ObserverPosition{
+		Latitude:  cfg.ObserverLatitude,
+		Longitude: cfg.ObserverLongitude,
+		Altitude:  cfg.ObserverAltitude,
+	}
+	if err := observer.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid observer location in config: %w", err)
+	}
+	cfg.ObserverLongitude = observer.Longitude
+
 	return &cfg, nil
 }
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/internal/storage"
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/viper"
+)
+
+// loadTestConfig loads configuration from a file with the given YAML content,
+// in a fresh home directory, and makes it the current config.
+func loadTestConfig(t *testing.T, yaml string) *satellite.Config {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, "custom.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := InitConfig(path)
+	if err != nil {
+		t.Fatalf("InitConfig(%s): %v", path, err)
+	}
+	previous := config
+	config = cfg
+	t.Cleanup(func() { config = previous })
+	return cfg
+}
+
+func TestDefaultMinElevationConfig(t *testing.T) {
+	if cfg := loadTestConfig(t, "observer_latitude: 40\n"); cfg.DefaultMinElevation != 10 {
+		t.Errorf("default_min_elevation defaults to %v, want 10", cfg.DefaultMinElevation)
+	}
+	if cfg := loadTestConfig(t, "default_min_elevation: 25.5\n"); cfg.DefaultMinElevation != 25.5 {
+		t.Errorf("default_min_elevation = %v, want 25.5 from config", cfg.DefaultMinElevation)
+	}
+}
+
+func TestResolveMinElevation(t *testing.T) {
+	loadTestConfig(t, "default_min_elevation: 25.5\n")
+
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"", 25.5},
+		{"  ", 25.5},
+		{"horizon", 0},
+		{"HORIZON", 0},
+		{"15", 15},
+		{"-5", -5},
+	}
+	for _, tt := range tests {
+		got, err := resolveMinElevation(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveMinElevation(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"high", "95", "-91"} {
+		if got, err := resolveMinElevation(in); err == nil {
+			t.Errorf("resolveMinElevation(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestInitConfigExplicitPath(t *testing.T) {
+	cfg := loadTestConfig(t, "observer_latitude: 51.5\nobserver_longitude: 359.9\napi_timeout: 7\nauto_fetch: false\n")
+
+	if cfg.ObserverLatitude != 51.5 || cfg.APITimeout != 7 || cfg.AutoFetch {
+		t.Errorf("config = lat %v, timeout %v, auto_fetch %v; want values from the custom file",
+			cfg.ObserverLatitude, cfg.APITimeout, cfg.AutoFetch)
+	}
+	if cfg.ObserverLongitude > -0.09 || cfg.ObserverLongitude < -0.11 {
+		t.Errorf("observer_longitude = %v, want 359.9 normalized to -0.1", cfg.ObserverLongitude)
+	}
+	if cfg.MaxCatalogAge != satellite.DefaultConfig().MaxCatalogAge {
+		t.Errorf("max_catalog_age = %v, want the default for keys the file omits", cfg.MaxCatalogAge)
+	}
+
+	// The default config file is neither read nor created
+	home, _ := os.UserHomeDir()
+	if _, err := os.Stat(filepath.Join(home, ".icu", "config.yaml")); !os.IsNotExist(err) {
+		t.Errorf("default config file touched when --config was given: %v", err)
+	}
+}
+
+func TestInitConfigMissingExplicitPath(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := InitConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("InitConfig succeeded with a missing --config file")
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, "alt.yaml")
+	if err := os.WriteFile(path, []byte("observer_latitude: -33.9\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous, previousFile := config, cfgFile
+	t.Cleanup(func() { config, cfgFile = previous, previousFile })
+	if err := rootCmd.PersistentFlags().Set("config", path); err != nil {
+		t.Fatal(err)
+	}
+	initConfig()
+
+	if config.ObserverLatitude != -33.9 {
+		t.Errorf("observer_latitude = %v after --config %s, want -33.9", config.ObserverLatitude, path)
+	}
+}
+
+func TestNamedSites(t *testing.T) {
+	cfg := loadTestConfig(t, `observer_latitude: 40
+observer_longitude: -105
+sites:
+  Home:
+    latitude: 52.2
+    longitude: 359.5
+    altitude: 30
+  greenwich:
+    latitude: 51.5
+    longitude: 0
+  broken:
+    latitude: 95
+`)
+
+	tests := []struct {
+		name          string
+		latitude, lon float64
+	}{
+		{"home", 52.2, -0.5},
+		{" HOME ", 52.2, -0.5},
+		{"Paranal", -24.6275, -70.4044},
+		{"greenwich", 51.5, 0}, // configured sites override built-ins
+	}
+	for _, tt := range tests {
+		observer, err := cfg.SiteObserver(tt.name)
+		if err != nil {
+			t.Errorf("SiteObserver(%q): %v", tt.name, err)
+			continue
+		}
+		if observer.Latitude != tt.latitude || observer.Longitude != tt.lon {
+			t.Errorf("SiteObserver(%q) = %v, %v; want %v, %v", tt.name, observer.Latitude, observer.Longitude, tt.latitude, tt.lon)
+		}
+	}
+
+	_, err := cfg.SiteObserver("club")
+	if err == nil || !strings.Contains(err.Error(), "home") || !strings.Contains(err.Error(), "maunakea") {
+		t.Errorf("unknown site error = %v, want one listing the known sites", err)
+	}
+	if _, err := cfg.SiteObserver("broken"); err == nil {
+		t.Error("site with latitude 95 resolved")
+	}
+
+	// --site selects the site in place of the configured observer
+	previous := siteName
+	t.Cleanup(func() { siteName = previous })
+	siteName = ""
+	if o := currentObserver(); o.Latitude != 40 {
+		t.Errorf("observer without --site at latitude %v, want 40", o.Latitude)
+	}
+	siteName = "maunakea"
+	if o := currentObserver(); o.Latitude != 19.8206 || o.Altitude != 4205 {
+		t.Errorf("observer with --site maunakea = %+v", o)
+	}
+}
+
+func TestCatalogBackendConfig(t *testing.T) {
+	loadTestConfig(t, "observer_latitude: 40\n")
+	if store, err := openCatalogStore(); err != nil {
+		t.Fatal(err)
+	} else if _, ok := store.(*satellite.Storage); !ok {
+		t.Errorf("default catalog backend is %T, want the catalog file", store)
+	}
+
+	cfg := loadTestConfig(t, "catalog_backend: sqlite\n")
+	store, err := openCatalogStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, ok := store.(*storage.SQLite)
+	if !ok {
+		t.Fatalf("sqlite catalog backend is %T", store)
+	}
+	defer db.Close()
+	if want := filepath.Join(cfg.DataDir, storage.SQLiteFile); db.CatalogPath() != want {
+		t.Errorf("catalog database at %s, want %s", db.CatalogPath(), want)
+	}
+
+	// Commands read the catalog from the configured backend
+	if err := db.Save(&satellite.Catalog{Satellites: []*satellite.Satellite{testISS()}, FetchedAt: issEpoch}); err != nil {
+		t.Fatal(err)
+	}
+	config.Watchlist = []int{25544}
+	if out := captureStdout(t, runWatchlistList); !strings.Contains(out, "ISS (ZARYA)") {
+		t.Errorf("watchlist list with the sqlite backend printed %q", out)
+	}
+
+	loadTestConfig(t, "catalog_backend: postgres\n")
+	if _, err := openCatalogStore(); err == nil || !strings.Contains(err.Error(), "catalog_backend") {
+		t.Errorf("unknown catalog backend: %v, want a configuration error", err)
+	}
+}
+
+// countingStore is a catalog store that counts whole-catalog loads.
+type countingStore struct {
+	storage.CatalogStore
+	loads int
+}
+
+func (s *countingStore) Load() (*satellite.Catalog, error) {
+	s.loads++
+	return s.CatalogStore.Load()
+}
+
+func TestSQLiteBackendGetAndSearchQueryTheStore(t *testing.T) {
+	cfg := loadTestConfig(t, "catalog_backend: sqlite\n")
+	db, err := storage.OpenSQLite(filepath.Join(cfg.DataDir, storage.SQLiteFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := &countingStore{CatalogStore: db}
+	catalogStoreOpener = func() (storage.CatalogStore, error) { return store, nil }
+	t.Cleanup(func() {
+		catalogStoreOpener = openCatalogStore
+		noradID, satName = 0, ""
+		searchOwner, searchIDFile = "", ""
+	})
+
+	iss := testISS()
+	iss.Owner = "ISS"
+	leo := leoTLE(30000, issEpoch)
+	other := &satellite.Satellite{NoradID: 30000, Name: "OTHER", Owner: "PRC", TLE: &leo, TLEEpoch: issEpoch}
+	if err := db.Save(&satellite.Catalog{Satellites: []*satellite.Satellite{iss, other}, FetchedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	idFile := filepath.Join(t.TempDir(), "ids.txt")
+	if err := os.WriteFile(idFile, []byte("ISS (ZARYA)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		run   func()
+		loads int // names can only be resolved against the whole catalog
+	}{
+		{"get by NORAD ID", func() { runGet([]string{"25544"}) }, 0},
+		{"search", func() { searchOwner = "iss"; runSearch(searchCmd) }, 0},
+		{"get by name", func() { satName = "iss (zarya)"; runGet(nil) }, 1},
+		{"search with an id file", func() { searchIDFile = idFile; runSearch(searchCmd) }, 1},
+	}
+	for _, tt := range tests {
+		noradID, satName = 0, ""
+		searchOwner, searchIDFile = "", ""
+		store.loads = 0
+
+		out := captureStdout(t, tt.run)
+		if !strings.Contains(out, "ISS (ZARYA)") || strings.Contains(out, "OTHER") {
+			t.Errorf("%s printed %q, want only the ISS", tt.name, out)
+		}
+		if store.loads != tt.loads {
+			t.Errorf("%s loaded the whole catalog %d times, want %d", tt.name, store.loads, tt.loads)
+		}
+	}
+}
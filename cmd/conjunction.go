@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conjunctionHours float64
+	conjunctionStep  time.Duration
+)
+
+var conjunctionCmd = &cobra.Command{
+	Use:   "conjunction NAME_OR_ID NAME_OR_ID",
+	Short: "Find the closest approach between two satellites",
+	Long: `Find when two satellites come closest together in the coming hours and how
+far apart they are. This is a screening aid based on TLE accuracy (a few km
+for LEO), not a collision probability.
+Each satellite can be given by NORAD ID, exact name, or a unique partial name.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runConjunction(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(conjunctionCmd)
+	conjunctionCmd.Flags().Float64Var(&conjunctionHours, "hours", 24, "How many hours ahead to search")
+	conjunctionCmd.Flags().DurationVar(&conjunctionStep, "step", 10*time.Second, "Coarse sampling interval before refinement")
+}
+
+func runConjunction(args []string) {
+	if conjunctionHours <= 0 {
+		log.Fatalf("Hours must be positive: %v", conjunctionHours)
+	}
+	if conjunctionStep <= 0 {
+		log.Fatalf("Step must be positive: %v", conjunctionStep)
+	}
+
+	satA := loadSatellite(args[0])
+	if satA == nil {
+		return
+	}
+	satB := loadSatellite(args[1])
+	if satB == nil {
+		return
+	}
+	if satA.NoradID == satB.NoradID {
+		log.Fatalf("Both arguments resolve to %s (%d)", satA.Name, satA.NoradID)
+	}
+
+	start := time.Now()
+	end := start.Add(time.Duration(conjunctionHours * float64(time.Hour)))
+	tca, distance, err := satellite.ClosestApproach(satA.TLE, satB.TLE, start, end, conjunctionStep)
+	if err != nil {
+		log.Fatalf("Error finding closest approach: %v", err)
+	}
+
+	fmt.Printf("Closest approach of %s (%d) and %s (%d) in the next %v hours\n\n",
+		satA.Name, satA.NoradID, satB.Name, satB.NoradID, conjunctionHours)
+	fmt.Printf("  Time:      %s  (in %s)\n", tca.Local().Format("2006-01-02 15:04:05.000 MST"),
+		satellite.FormatRelativeDuration(tca.Sub(start)))
+	fmt.Printf("  Distance:  %.3f km\n", distance)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffLimit    int
+	diffSnapshot string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what a fresh fetch would change in the catalog",
+	Long: `Fetch the latest TLE and SATCAT data and compare it against the catalog on disk,
+without saving it. Reports satellites added and removed, and those with a new
+TLE or changed SATCAT orbital parameters (period, inclination, apogee, perigee).
+
+With --snapshot, compare the snapshot saved on or before a date (see
+catalog_snapshots in config) against the catalog on disk instead, without fetching.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runDiff()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&diffSnapshot, "snapshot", "", "Compare the catalog snapshot taken on or before this date (YYYY-MM-DD, or RFC 3339 for a time of day) against the catalog on disk")
+	diffCmd.Flags().IntVarP(&diffLimit, "limit", "l", 20, "Maximum number of satellites to list per category (0 = no limit)")
+}
+
+func runDiff() {
+	catalog, err := newCatalogStore().Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	var older, newer *satellite.Catalog
+	if diffSnapshot != "" {
+		if older = loadDiffSnapshot(newStorage(), diffSnapshot); older == nil {
+			return
+		}
+		newer = catalog
+	} else {
+		fmt.Println("Fetching latest data...")
+		older = catalog
+		if newer, err = satellite.FetchAndMergeCatalogWith(newAPIClient(), config.MergeOptions()); err != nil {
+			log.Fatalf("Error fetching catalog: %v", err)
+		}
+	}
+
+	diff := satellite.DiffCatalogs(older, newer)
+
+	tleChanges := 0
+	for _, change := range diff.Changed {
+		if change.TLEChanged() {
+			tleChanges++
+		}
+	}
+
+	if diffSnapshot != "" {
+		fmt.Printf("\nSnapshot fetched %s vs. catalog on disk (fetched %s):\n",
+			older.FetchedAt.Format("2006-01-02 15:04 MST"), newer.FetchedAt.Format("2006-01-02 15:04 MST"))
+	} else {
+		fmt.Printf("\nCatalog on disk (fetched %s) vs. now:\n", older.FetchedAt.Format("2006-01-02 15:04 MST"))
+	}
+	fmt.Printf("  Added:    %d\n", len(diff.Added))
+	fmt.Printf("  Removed:  %d\n", len(diff.Removed))
+	fmt.Printf("  Changed:  %d (%d with a new TLE)\n", len(diff.Changed), tleChanges)
+
+	names := make(map[int]string, len(older.Satellites)+len(newer.Satellites))
+	for _, sat := range older.Satellites {
+		names[sat.NoradID] = sat.Name
+	}
+	for _, sat := range newer.Satellites {
+		names[sat.NoradID] = sat.Name
+	}
+
+	printDiffIDs("Added", diff.Added, names)
+	printDiffIDs("Removed", diff.Removed, names)
+
+	if len(diff.Changed) > 0 {
+		fmt.Println("\nChanged:")
+		for i, change := range diff.Changed {
+			if diffLimit > 0 && i == diffLimit {
+				fmt.Printf("  ... and %d more\n", len(diff.Changed)-diffLimit)
+				break
+			}
+			fmt.Printf("  %-8d  %-24s  %s\n", change.NoradID, change.Name, strings.Join(change.Fields, ", "))
+		}
+	}
+}
+
+// loadDiffSnapshot loads the catalog snapshot at or before the date given to
+// --snapshot, taken as the end of that day if it has no time of day. Prints
+// the snapshots there are and returns nil if none is that old; exits if the
+// date is invalid or the snapshot can't be read.
+func loadDiffSnapshot(store *satellite.Storage, date string) *satellite.Catalog {
+	t, ok := snapshotCutoff(date)
+	if !ok {
+		log.Fatalf("Invalid --snapshot date: %s", date)
+	}
+
+	snapshot, err := store.LoadSnapshot(t)
+	if err != nil {
+		log.Fatalf("Error loading snapshot: %v", err)
+	}
+	if snapshot != nil {
+		return snapshot
+	}
+
+	times, err := store.ListSnapshots()
+	if err != nil {
+		log.Fatalf("Error listing snapshots: %v", err)
+	}
+	if len(times) == 0 {
+		fmt.Println("No catalog snapshots saved yet.")
+		if !config.CatalogSnapshots {
+			fmt.Println("Set catalog_snapshots: true in config to keep one per fetch.")
+		}
+		return nil
+	}
+	fmt.Printf("No snapshot at or before %s. The oldest is from %s.\n",
+		t.Format("2006-01-02 15:04 MST"), times[0].Format("2006-01-02 15:04 MST"))
+	return nil
+}
+
+// printDiffIDs lists satellites by NORAD ID and name under a heading, up to
+// --limit of them.
+func printDiffIDs(heading string, ids []int, names map[int]string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", heading)
+	for i, id := range ids {
+		if diffLimit > 0 && i == diffLimit {
+			fmt.Printf("  ... and %d more\n", len(ids)-diffLimit)
+			break
+		}
+		fmt.Printf("  %-8d  %s\n", id, names[id])
+	}
+}
+
+// snapshotCutoff parses a --snapshot value into the latest time a snapshot
+// may have been taken to match it. A date without a time of day, or a month
+// or year, takes in all of it, so that snapshots from that day count.
+func snapshotCutoff(date string) (time.Time, bool) {
+	t, ok := satellite.ParseSATCATDate(date)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch len(strings.TrimSpace(date)) {
+	case len("2006"):
+		t = t.AddDate(1, 0, 0)
+	case len("2006-01"):
+		t = t.AddDate(0, 1, 0)
+	case len("2006-01-02"):
+		t = t.AddDate(0, 0, 1)
+	default:
+		return t, true
+	}
+	return t.Add(-time.Nanosecond), true
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestLoadDiffSnapshotDate(t *testing.T) {
+	cfg := loadTestConfig(t, "catalog_snapshots: true\n")
+	store, err := satellite.NewStorage(cfg.DataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dayBefore := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	midDay := time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC)
+	nextMonth := time.Date(2026, 11, 2, 8, 0, 0, 0, time.UTC)
+	for _, fetchedAt := range []time.Time{dayBefore, midDay, nextMonth} {
+		if err := store.SaveSnapshot(&satellite.Catalog{Satellites: []*satellite.Satellite{testISS()}, FetchedAt: fetchedAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		date string
+		want time.Time // zero if no snapshot is that old
+	}{
+		{"2026-10-16", midDay}, // taken during the day
+		{"2026-10-15", dayBefore},
+		{"2026-10-16T12:00:00Z", dayBefore},
+		{"2026-10-16T14:30:00Z", midDay},
+		{"2026-10", midDay},
+		{"2026", nextMonth},
+		{"2026-10-14", time.Time{}},
+	}
+	for _, tt := range tests {
+		var snapshot *satellite.Catalog
+		captureStdout(t, func() { snapshot = loadDiffSnapshot(store, tt.date) })
+		switch {
+		case tt.want.IsZero() && snapshot != nil:
+			t.Errorf("--snapshot %s loaded the snapshot from %v, want none", tt.date, snapshot.FetchedAt)
+		case !tt.want.IsZero() && (snapshot == nil || !snapshot.FetchedAt.Equal(tt.want)):
+			t.Errorf("--snapshot %s loaded %v, want the snapshot from %v", tt.date, snapshot, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dopplerFrequency float64
+	dopplerStep      time.Duration
+)
+
+var dopplerCmd = &cobra.Command{
+	Use:   "doppler NAME_OR_ID",
+	Short: "Tabulate the Doppler-shifted frequency over the next pass",
+	Long: `Print the received frequency of a satellite downlink across its next pass
+over the configured observer, for tuning a radio by hand or checking rig
+control. The satellite can be given by NORAD ID, exact name, or a unique
+partial name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runDoppler(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dopplerCmd)
+	dopplerCmd.Flags().Float64VarP(&dopplerFrequency, "frequency", "f", 0, "Downlink frequency in MHz, e.g. 437.5")
+	dopplerCmd.Flags().DurationVar(&dopplerStep, "step", 10*time.Second, "Time between rows")
+}
+
+func runDoppler(args []string) {
+	if dopplerFrequency <= 0 {
+		log.Fatalf("--frequency must be a positive frequency in MHz: %v", dopplerFrequency)
+	}
+	if dopplerStep <= 0 {
+		log.Fatalf("Step must be positive: %v", dopplerStep)
+	}
+
+	observer := currentObserver()
+	if observer == nil {
+		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+		return
+	}
+
+	sat := loadSatellite(args[0])
+	if sat == nil {
+		return
+	}
+
+	pass, err := satellite.NextPass(sat.TLE, observer, time.Now(), config.DefaultMinElevation)
+	if errors.Is(err, satellite.ErrNoPassFound) {
+		fmt.Printf("%s (%d) has no pass above %.1f° in the next 48 hours.\n",
+			sat.Name, sat.NoradID, config.DefaultMinElevation)
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error predicting pass: %v", err)
+	}
+
+	samples, err := dopplerSamples(pass, sat.TLE, observer, dopplerFrequency, dopplerStep)
+	if err != nil {
+		log.Fatalf("Error computing Doppler: %v", err)
+	}
+
+	fmt.Printf("Doppler for %s (%d) at %.6f MHz, pass %s to %s\n\n", sat.Name, sat.NoradID, dopplerFrequency,
+		pass.AOS.Local().Format("2006-01-02 15:04:05"), pass.LOS.Local().Format("15:04:05 MST"))
+	fmt.Printf("%-8s  %6s  %6s  %10s  %16s  %9s\n", "Time", "Az", "El", "Rate km/s", "Frequency (Hz)", "Shift Hz")
+	for _, s := range samples {
+		fmt.Printf("%-8s  %5.1f°  %5.1f°  %10.3f  %16.0f  %+9.0f\n",
+			s.Time.Local().Format("15:04:05"), s.Azimuth, s.Elevation, s.RangeRate, s.Frequency, s.Shift)
+	}
+}
+
+// dopplerSamples tabulates the received frequency over the pass of a downlink
+// at frequencyMHz, the unit every --frequency flag takes.
+func dopplerSamples(pass *satellite.Pass, tle *satellite.TLE, observer *satellite.ObserverPosition, frequencyMHz float64, step time.Duration) ([]satellite.DopplerSample, error) {
+	return satellite.DopplerShiftRange(pass, tle, observer, frequencyMHz*1e6, step)
+}
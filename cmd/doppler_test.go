@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestDopplerSamplesMHz(t *testing.T) {
+	const frequencyMHz, c = 145.8, 299792.458
+	sat := testISS()
+	observer := &satellite.ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	pass, err := satellite.NextPass(sat.TLE, observer, issEpoch, 10)
+	if err != nil {
+		t.Fatalf("NextPass: %v", err)
+	}
+
+	samples, err := dopplerSamples(pass, sat.TLE, observer, frequencyMHz, 30*time.Second)
+	if err != nil || len(samples) < 2 {
+		t.Fatalf("dopplerSamples = %d samples, %v", len(samples), err)
+	}
+	for _, s := range samples {
+		// Received frequency by hand from the range rate: f = f0 (1 - rr/c)
+		pos, err := satellite.PropagateSatellite(sat.TLE, s.Time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rangeRate := satellite.CalculateObservationAngles(pos, observer).RangeRate
+		want := frequencyMHz * 1e6 * (1 - rangeRate/c)
+		if math.Abs(s.Frequency-want) > 1 || math.Abs(s.Shift-(want-frequencyMHz*1e6)) > 1 {
+			t.Errorf("at %v: %.0f Hz (shift %+.0f), want %.0f Hz from range rate %.3f km/s",
+				s.Time, s.Frequency, s.Shift, want, rangeRate)
+		}
+	}
+
+	// An approaching satellite is heard high, a receding one low, by at most
+	// the ISS's ~7.5 km/s range rate
+	first, last := samples[0], samples[len(samples)-1]
+	limit := frequencyMHz * 1e6 * 7.5 / c
+	if first.Shift <= 0 || first.Shift > limit || last.Shift >= 0 || last.Shift < -limit {
+		t.Errorf("shift from %+.0f to %+.0f Hz, want from up to +%.0f to down to -%.0f", first.Shift, last.Shift, limit, limit)
+	}
+}
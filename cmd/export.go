@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutput   string
+	exportDuration time.Duration
+	exportStep     time.Duration
+	exportRestart  bool
+	exportCatalog  bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export positions for the whole catalog to a CSV file",
+	Long: `Propagate every satellite in the catalog and write its ECEF position and
+velocity to a CSV file, either at a single time or as a track over --duration.
+
+Progress is recorded in a sidecar file next to the output (<output>.progress).
+If the export is interrupted or fails, running the same command again resumes
+where it stopped, using the original time window; the sidecar is removed once
+the export completes. Use --restart to discard a partial export.
+
+With --catalog, write the catalog data itself instead: one row per satellite
+with its SATCAT fields, orbit regime, and TLE.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExport()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "catalog.csv", "CSV file to write")
+	exportCmd.Flags().DurationVar(&exportDuration, "duration", 0, "Track length from now (0 = positions at a single time)")
+	exportCmd.Flags().DurationVar(&exportStep, "step", time.Minute, "Time between track points")
+	exportCmd.Flags().BoolVar(&exportRestart, "restart", false, "Discard any partial export and start over")
+	exportCmd.Flags().BoolVar(&exportCatalog, "catalog", false, "Write each satellite's catalog data instead of positions")
+}
+
+func runExport() {
+	if exportStep <= 0 {
+		log.Fatalf("Step must be positive: %v", exportStep)
+	}
+	if exportDuration < 0 {
+		log.Fatalf("Duration must not be negative: %v", exportDuration)
+	}
+
+	store := newCatalogStore()
+
+	catalog, err := store.Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	if exportCatalog {
+		exportCatalogCSV(catalog)
+		return
+	}
+
+	// Stop cleanly on Ctrl+C; completed satellites stay recorded
+	ctx, stop := interruptContext()
+	defer stop()
+
+	bar := newProgressBar("Exporting")
+	start := time.Now().UTC().Truncate(time.Second)
+	result, err := satellite.ExportTracks(ctx, exportOutput, catalog.Satellites, satellite.TrackExportOptions{
+		Start:    start,
+		End:      start.Add(exportDuration),
+		Step:     exportStep,
+		Restart:  exportRestart,
+		Progress: bar,
+	})
+	if result != nil && result.Resumed {
+		fmt.Printf("Resuming export from %s (%d satellites already done)\n",
+			result.Start.Format(time.RFC3339), result.Skipped)
+	}
+	if errors.Is(err, context.Canceled) {
+		if bar != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		fmt.Printf("Interrupted after %d satellites. Run the same command again to resume.\n", result.Exported)
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error exporting to %s: %v (run again to resume)", exportOutput, err)
+	}
+
+	fmt.Printf("Exported %d satellites to %s\n", result.Exported, exportOutput)
+	if result.Failed > 0 {
+		fmt.Printf("Skipped %d satellites that failed to propagate (see 'icu stats --check')\n", result.Failed)
+	}
+}
+
+// exportCatalogCSV writes the catalog data of every satellite to the output file.
+func exportCatalogCSV(catalog *satellite.Catalog) {
+	output, err := os.Create(exportOutput)
+	if err != nil {
+		log.Fatalf("Failed to create output file: %v", err)
+	}
+	if err := satellite.ExportCSV(output, catalog.Satellites); err != nil {
+		output.Close()
+		log.Fatalf("Error writing %s: %v", exportOutput, err)
+	}
+	if err := output.Close(); err != nil {
+		log.Fatalf("Error writing %s: %v", exportOutput, err)
+	}
+	fmt.Printf("Exported %d satellites to %s\n", len(catalog.Satellites), exportOutput)
+}
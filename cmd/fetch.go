@@ -1,20 +1,36 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"github.com/dzeleniak/icu/internal/storage"
 	"github.com/dzeleniak/icu/pkg/satellite"
 	"github.com/spf13/cobra"
 )
 
+var (
+	fetchTLEOnly   bool
+	fetchIfChanged bool
+)
+
 var fetchCmd = &cobra.Command{
 	Use:   "fetch",
 	Short: "Fetch TLE and SATCAT data from spacebook.com",
 	Long: `Fetch retrieves the latest TLE (Two-Line Element) and SATCAT
 (Satellite Catalog) data from spacebook.com and stores it locally
-in ~/.icu/catalog.json for later use.`,
+in ~/.icu/catalog.json (catalog.gob with catalog_format: gob) for later use.
+
+With --tle-only, only the TLE feed is downloaded and the TLEs of the stored
+catalog are refreshed, keeping its SATCAT metadata.
+
+With --if-changed, the feeds are requested conditionally on the ETag and
+Last-Modified of the last fetch, and the stored catalog is left as is if the
+server reports that neither has changed, apart from its fetch time.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runFetch()
 	},
@@ -22,17 +38,34 @@ in ~/.icu/catalog.json for later use.`,
 
 func init() {
 	rootCmd.AddCommand(fetchCmd)
+	fetchCmd.Flags().BoolVar(&fetchTLEOnly, "tle-only", false, "Only refresh the TLEs of the stored catalog, keeping its SATCAT data")
+	fetchCmd.Flags().BoolVar(&fetchIfChanged, "if-changed", false, "Skip rebuilding the catalog if the feeds have not changed since the last fetch")
 }
 
 func runFetch() {
 	// Create client with config values
-	timeout := time.Duration(config.APITimeout) * time.Second
-	apiClient := satellite.NewClient(config.TLEEndpoint, config.SATCATEndpoint, timeout)
+	apiClient := newAPIClient()
 
-	// Create storage
-	store, err := satellite.NewStorage(config.DataDir)
-	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+	// Create storage; feed validators and snapshots are kept in files
+	// alongside the catalog, whatever its backend
+	store := newCatalogStore()
+	files := newStorage()
+
+	// Abort the download cleanly on Ctrl+C, leaving the stored catalog as is
+	ctx, stop := interruptContext()
+	defer stop()
+
+	if fetchIfChanged && store.Exists() {
+		validators, err := files.LoadValidators()
+		if err != nil {
+			log.Fatalf("Error loading feed validators: %v", err)
+		}
+		apiClient.SetConditional(validators)
+	}
+
+	if fetchTLEOnly {
+		runFetchTLEs(ctx, apiClient, store, files)
+		return
 	}
 
 	fmt.Println("Fetching TLE data...")
@@ -40,16 +73,163 @@ func runFetch() {
 	fmt.Println("Merging satellite data...")
 
 	// Use library function to fetch and merge catalog
-	catalog, err := satellite.FetchAndMergeCatalog(apiClient)
+	catalog, err := satellite.FetchAndMergeCatalogContext(ctx, apiClient, config.MergeOptions())
+	if ctx.Err() != nil {
+		fmt.Println("Fetch interrupted; the stored catalog was not changed.")
+		return
+	}
+	if errors.Is(err, satellite.ErrNotModified) {
+		stored, err := store.Load()
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+		if err := markCatalogCurrent(store, stored); err != nil {
+			log.Fatalf("Error saving catalog: %v", err)
+		}
+		fmt.Println("\nFeeds unchanged since the last fetch; the stored catalog is up to date.")
+		return
+	}
 	if err != nil {
 		log.Fatalf("Error fetching catalog: %v", err)
 	}
 
-	if err := store.Save(catalog); err != nil {
+	if err := saveCatalog(store, files, catalog); err != nil {
 		log.Fatalf("Error saving catalog: %v", err)
 	}
+	saveValidators(files, apiClient)
 
 	fmt.Println("\n✓ Data fetched successfully")
 	fmt.Printf("  Merged satellites: %d\n", len(catalog.Satellites))
-	fmt.Printf("\nCatalog saved to %s/catalog.json\n", config.DataDir)
+	if catalog.DroppedTLEs > 0 {
+		fmt.Printf("  Dropped TLEs:      %d (unparseable NORAD ID)\n", catalog.DroppedTLEs)
+	}
+	fmt.Printf("\nCatalog saved to %s\n", store.CatalogPath())
+}
+
+// runFetchTLEs refreshes the TLEs of the stored catalog from the TLE feed
+// alone and saves it.
+func runFetchTLEs(ctx context.Context, apiClient *satellite.Client, store storage.CatalogStore, files *satellite.Storage) {
+	catalog, err := store.Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' without --tle-only first.")
+		return
+	}
+
+	fmt.Println("Fetching TLE data...")
+	tles, err := apiClient.FetchTLEsContext(ctx)
+	if ctx.Err() != nil {
+		fmt.Println("Fetch interrupted; the stored catalog was not changed.")
+		return
+	}
+	if errors.Is(err, satellite.ErrNotModified) {
+		if err := markCatalogCurrent(store, catalog); err != nil {
+			log.Fatalf("Error saving catalog: %v", err)
+		}
+		fmt.Println("\nTLE feed unchanged since the last fetch; the stored catalog is up to date.")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error fetching TLEs: %v", err)
+	}
+
+	updated := satellite.UpdateTLEs(catalog, tles)
+	if err := saveCatalog(store, files, updated); err != nil {
+		log.Fatalf("Error saving catalog: %v", err)
+	}
+	saveValidators(files, apiClient)
+
+	// Only satellites whose TLE actually changed count as updated
+	diff := satellite.DiffCatalogs(catalog, updated)
+	changed := 0
+	for _, change := range diff.Changed {
+		if change.TLEChanged() {
+			changed++
+		}
+	}
+	missing := 0
+	for _, sat := range updated.Satellites {
+		if sat.MissingFromFeed {
+			missing++
+		}
+	}
+
+	fmt.Println("\n✓ TLEs updated successfully")
+	fmt.Printf("  Updated satellites: %d\n", changed)
+	fmt.Printf("  New satellites:     %d\n", len(diff.Added))
+	if missing > 0 {
+		fmt.Printf("  Not in TLE feed:    %d (kept, flagged missing)\n", missing)
+	}
+	if updated.DroppedTLEs > 0 {
+		fmt.Printf("  Dropped TLEs:       %d (unparseable NORAD ID)\n", updated.DroppedTLEs)
+	}
+	fmt.Printf("\nCatalog saved to %s\n", store.CatalogPath())
+}
+
+// saveValidators saves the cache validators of the feeds just fetched for the
+// next fetch --if-changed. Failure only costs a full download then, so it is
+// reported on stderr rather than fatal.
+func saveValidators(store *satellite.Storage, apiClient *satellite.Client) {
+	if err := store.SaveValidators(apiClient.Validators()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// markCatalogCurrent saves the stored catalog with a FetchedAt of now after a
+// conditional fetch found the feeds unchanged, so that it is not reported as
+// stale. Its data is the same, so no snapshot is taken.
+func markCatalogCurrent(store storage.CatalogStore, catalog *satellite.Catalog) error {
+	if catalog == nil {
+		return nil
+	}
+	current := *catalog
+	current.FetchedAt = time.Now()
+	return store.Save(&current)
+}
+
+// saveCatalog saves a freshly fetched catalog, and a snapshot of it if
+// catalog_snapshots is set.
+func saveCatalog(store storage.CatalogStore, files *satellite.Storage, catalog *satellite.Catalog) error {
+	if err := store.Save(catalog); err != nil {
+		return err
+	}
+	if config.CatalogSnapshots {
+		if err := files.SaveSnapshot(catalog); err != nil {
+			return fmt.Errorf("snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// newAPIClient creates an API client from the configured endpoints, timeout,
+// and retries.
+func newAPIClient() *satellite.Client {
+	timeout := time.Duration(config.APITimeout) * time.Second
+	client := satellite.NewClientWithEndpoints(config.TLEEndpoints(), config.SATCATEndpoints(), timeout)
+	client.SetTLEObjectEndpoint(config.TLEObjectEndpoint)
+	client.SetRetry(config.APIRetries+1, time.Duration(config.APIRetryDelay*float64(time.Second)))
+	return client
+}
+
+// refreshSatelliteTLE replaces a satellite's TLE with a fresh one from the
+// per-object endpoint, for when a single satellite is needed from a stale
+// catalog. The stored catalog is not modified. Failures are reported on
+// stderr and leave the satellite unchanged.
+func refreshSatelliteTLE(sat *satellite.Satellite) {
+	if config.TLEObjectEndpoint == "" {
+		return
+	}
+
+	tle, err := newAPIClient().FetchTLEByID(context.Background(), sat.NoradID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: catalog is stale and refreshing the TLE failed: %v\n", err)
+		return
+	}
+
+	sat.TLE = tle
+	if epoch, err := tle.GetEpoch(); err == nil {
+		sat.TLEEpoch = epoch
+	}
 }
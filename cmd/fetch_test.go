@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestFetchIfChangedNotModified(t *testing.T) {
+	var conditional, hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		http.Error(w, "unconditional request", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { fetchIfChanged, fetchTLEOnly = false, false })
+
+	for _, tleOnly := range []bool{false, true} {
+		cfg := loadTestConfig(t, "tle_endpoint: "+server.URL+"/tle\nsatcat_endpoint: "+server.URL+"/satcat\n")
+		store, err := satellite.NewStorage(cfg.DataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fetchedAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+		if err := store.Save(&satellite.Catalog{Satellites: []*satellite.Satellite{testISS()}, FetchedAt: fetchedAt}); err != nil {
+			t.Fatal(err)
+		}
+		validators := map[string]satellite.CacheValidators{
+			server.URL + "/tle":    {ETag: `"v1"`},
+			server.URL + "/satcat": {ETag: `"v1"`},
+		}
+		if err := store.SaveValidators(validators); err != nil {
+			t.Fatal(err)
+		}
+
+		conditional, hits = 0, 0
+		fetchIfChanged, fetchTLEOnly = true, tleOnly
+		before := time.Now()
+		out := captureStdout(t, runFetch)
+		if !strings.Contains(out, "up to date") {
+			t.Errorf("tle-only %v: output %q does not report the catalog up to date", tleOnly, out)
+		}
+		if hits == 0 || conditional != hits {
+			t.Errorf("tle-only %v: %d of %d requests were conditional", tleOnly, conditional, hits)
+		}
+
+		catalog, err := store.Load()
+		if err != nil || catalog == nil {
+			t.Fatalf("tle-only %v: Load = %v, %v", tleOnly, catalog, err)
+		}
+		if catalog.FetchedAt.Before(before) {
+			t.Errorf("tle-only %v: FetchedAt %v was not bumped", tleOnly, catalog.FetchedAt)
+		}
+		if len(catalog.Satellites) != 1 || catalog.Satellites[0].TLE.Line1 != issTLE.Line1 {
+			t.Errorf("tle-only %v: catalog changed: %+v", tleOnly, catalog.Satellites)
+		}
+	}
+}
@@ -1,13 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/dzeleniak/icu/pkg/satellite"
@@ -22,6 +20,10 @@ var (
 	showData bool
 	verbose  bool
 	follow   bool
+	showNext bool
+
+	followInterval  time.Duration
+	followFrequency float64
 )
 
 var getCmd = &cobra.Command{
@@ -44,7 +46,10 @@ func init() {
 	getCmd.Flags().BoolVarP(&showPos, "position", "p", false, "Display current position")
 	getCmd.Flags().BoolVarP(&showData, "data", "d", false, "Display satellite metadata")
 	getCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Display all information (TLE + position + metadata)")
-	getCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Continuously update position every second")
+	getCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Continuously update position")
+	getCmd.Flags().BoolVar(&showNext, "next-pass", false, "Display the next pass above default_min_elevation")
+	getCmd.Flags().DurationVarP(&followInterval, "interval", "i", time.Second, "Update interval in follow mode")
+	getCmd.Flags().Float64Var(&followFrequency, "frequency", 0, "Downlink frequency in MHz; follow mode shows the Doppler-shifted frequency")
 }
 
 func runGet(args []string) {
@@ -57,59 +62,159 @@ func runGet(args []string) {
 		noradID = id
 	}
 
-	// Load catalog
-	store, err := satellite.NewStorage(config.DataDir)
-	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
-	}
-
-	catalog, err := store.Load()
-	if err != nil {
-		log.Fatalf("Error loading catalog: %v", err)
-	}
-
-	if catalog == nil {
+	store := newCatalogStore()
+	if !store.Exists() {
 		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
 		return
 	}
 
-	// Filter satellites using library function
-	filtered := satellite.FilterSatellites(catalog.Satellites, noradID, satName)
+	// A satellite asked for by NORAD ID is looked up directly, which a
+	// database backend answers without reading the whole catalog; a name
+	// has to be matched against every satellite
+	var catalog *satellite.Catalog
+	var filtered []*satellite.Satellite
+	if noradID > 0 {
+		sat, err := store.GetByNorad(noradID)
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+		if sat != nil {
+			filtered = satellite.FilterSatellites([]*satellite.Satellite{sat}, 0, satName)
+		}
+	} else {
+		var err error
+		catalog, err = store.Load()
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+		if catalog == nil {
+			fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+			return
+		}
+		filtered = satellite.FilterSatellites(catalog.Satellites, noradID, satName)
+	}
 
 	if len(filtered) == 0 {
 		fmt.Println("No satellites found matching the criteria.")
 		return
 	}
 
+	// A single satellite can be refreshed far more cheaply than the whole
+	// catalog. Its age is only read when a refresh is possible, since the
+	// catalog file store loads the whole catalog for it
+	if len(filtered) == 1 && config.TLEObjectEndpoint != "" {
+		if catalog == nil {
+			fetchedAt, err := store.FetchedAt()
+			if err != nil {
+				log.Fatalf("Error loading catalog: %v", err)
+			}
+			catalog = &satellite.Catalog{FetchedAt: fetchedAt}
+		}
+		if config.IsCatalogStale(catalog) {
+			refreshSatelliteTLE(filtered[0])
+		}
+	}
+
 	// Display results
 	if follow {
+		if jsonOutput {
+			log.Fatal("--json is not supported in follow mode")
+		}
 		// Follow mode: continuously update position (shows TLE + position)
 		displaySatellitesFollow(filtered)
-	} else if verbose {
-		// Verbose is shorthand for --tle --position --data
-		displaySatellitesVerbose(filtered)
-	} else {
-		// Composable flags: show only what's requested
-		// If no flags set, default to TLE
-		if !showTLE && !showPos && !showData {
-			showTLE = true
+		return
+	}
+	render(&getResult{satellites: filtered})
+}
+
+// getResult is the outcome of a get, rendered as the components selected by
+// flags or as JSON with each satellite's current position and, with
+// --next-pass or --verbose, its next pass.
+type getResult struct {
+	satellites []*satellite.Satellite
+}
+
+// satelliteReport is the JSON form of one satellite in a get, and
+// satelliteReportSnake the same with snake_case keys. Satellite, Angles and
+// NextPass hold the JSONValue of theirs, nil if there is none.
+type satelliteReport struct {
+	Satellite any                 `json:"satellite"`
+	OrbitType satellite.OrbitType `json:"orbitType"`
+	Subpoint  *subpoint           `json:"subpoint,omitempty"`
+	Angles    any                 `json:"angles,omitempty"`
+	NextPass  any                 `json:"nextPass,omitempty"`
+}
+
+type satelliteReportSnake struct {
+	Satellite any                 `json:"satellite"`
+	OrbitType satellite.OrbitType `json:"orbit_type"`
+	Subpoint  *subpoint           `json:"subpoint,omitempty"`
+	Angles    any                 `json:"angles,omitempty"`
+	NextPass  any                 `json:"next_pass,omitempty"`
+}
+
+// subpoint is a sub-satellite point for JSON output.
+type subpoint struct {
+	Latitude  float64 `json:"latitude"`  // degrees
+	Longitude float64 `json:"longitude"` // degrees
+	Altitude  float64 `json:"altitude"`  // km
+}
+
+func (r *getResult) jsonValue(decimals int, naming satellite.JSONNaming) any {
+	observer := currentObserver()
+	now := time.Now()
+
+	reports := make([]any, 0, len(r.satellites))
+	for _, sat := range r.satellites {
+		report := satelliteReport{Satellite: sat.JSONValue(naming), OrbitType: satellite.ClassifyOrbitType(sat)}
+		if sat.TLE != nil {
+			if pos, err := satellite.PropagateSatellite(sat.TLE, now); err == nil {
+				lat, lon, alt := satellite.SubSatellitePoint(pos)
+				report.Subpoint = &subpoint{
+					Latitude:  satellite.RoundTo(lat, decimals),
+					Longitude: satellite.RoundTo(lon, decimals),
+					Altitude:  satellite.RoundTo(alt, decimals),
+				}
+				if observer != nil {
+					report.Angles = satellite.CalculateObservationAngles(pos, observer).Rounded(decimals).JSONValue(naming)
+				}
+			}
+			if observer != nil && (showNext || verbose) {
+				if pass, err := satellite.NextPass(sat.TLE, observer, now, config.DefaultMinElevation); err == nil {
+					rounded := pass.Rounded(decimals)
+					report.NextPass = rounded.JSONValue(naming)
+				}
+			}
+		}
+		if naming == satellite.SnakeCase {
+			reports = append(reports, satelliteReportSnake(report))
+		} else {
+			reports = append(reports, report)
 		}
-		displaySatellitesComposed(filtered, showTLE, showPos, showData)
 	}
+	return reports
+}
+
+func (r *getResult) renderText() {
+	if verbose {
+		// Verbose is shorthand for --tle --position --data
+		displaySatellitesVerbose(r.satellites)
+		return
+	}
+
+	// Composable flags: show only what's requested
+	// If no flags set, default to TLE
+	if !showTLE && !showPos && !showData && !showNext {
+		showTLE = true
+	}
+	displaySatellitesComposed(r.satellites, showTLE, showPos, showData, showNext)
 }
 
 // displaySatellitesComposed shows only the requested components based on flags
-func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showPos, showData bool) {
+func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showPos, showData, showNext bool) {
 	// Check if observer is configured for position display
-	observerConfigured := config.ObserverLatitude != 0.0 || config.ObserverLongitude != 0.0
-	var observer *satellite.ObserverPosition
-	if showPos && observerConfigured {
-		observer = &satellite.ObserverPosition{
-			Latitude:  config.ObserverLatitude,
-			Longitude: config.ObserverLongitude,
-			Altitude:  config.ObserverAltitude,
-		}
-	}
+	observer := currentObserver()
+	observerConfigured := observer != nil
 
 	now := time.Now()
 
@@ -127,6 +232,13 @@ func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showP
 				fmt.Println()
 			}
 		}
+		if sat.TLE == nil && (showTLE || showPos || showNext) {
+			// A SATCAT-only entry, e.g. a decayed object; metadata is all there is
+			fmt.Printf("No TLE data for %s (%d); it cannot be propagated.\n", sat.Name, sat.NoradID)
+			if showData {
+				fmt.Println()
+			}
+		}
 
 		// Display current position if requested
 		if showPos {
@@ -136,7 +248,10 @@ func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showP
 				pos, err := satellite.PropagateSatellite(sat.TLE, now)
 				if err == nil {
 					angles := satellite.CalculateObservationAngles(pos, observer)
+					lat, lon, alt := satellite.SubSatellitePoint(pos)
 					fmt.Printf("Current Position (as of %s):\n", now.Format("2006-01-02 15:04:05 MST"))
+					fmt.Printf("  Sub-point:    %7.2f°, %7.2f°\n", lat, lon)
+					fmt.Printf("  Altitude:     %10.0f km\n", alt)
 					fmt.Printf("  Elevation:    %7.2f°\n", angles.Elevation)
 					fmt.Printf("  Azimuth:      %7.2f°\n", angles.Azimuth)
 					fmt.Printf("  Range:        %10.0f km\n", angles.Range)
@@ -164,6 +279,18 @@ func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showP
 			if sat.OrbitRegime != "" {
 				fmt.Printf("Orbit Regime:   %s\n", sat.OrbitRegime)
 			}
+			if orbitType := satellite.ClassifyOrbitType(sat); orbitType.Special() {
+				fmt.Printf("Orbit Type:     %s\n", orbitType)
+			}
+			if sat.TLE != nil {
+				if epoch, err := sat.TLE.GetEpoch(); err == nil {
+					fmt.Printf("TLE Epoch:      %s (%s old)\n", epoch.Format("2006-01-02 15:04:05 MST"),
+						satellite.FormatRelativeDuration(satellite.TLEAge(epoch)))
+				}
+			}
+			if sat.MissingFromFeed {
+				fmt.Println("TLE Status:     not in the last TLE feed")
+			}
 			if sat.LaunchDate != "" {
 				fmt.Printf("Launch Date:    %s\n", sat.LaunchDate)
 			}
@@ -193,12 +320,47 @@ func displaySatellitesComposed(satellites []*satellite.Satellite, showTLE, showP
 					fmt.Printf("  RCS Size:     %s\n", sat.RCSSize)
 				}
 			}
+			if showNext {
+				fmt.Println()
+			}
+		}
+
+		// Display the next pass if requested
+		if showNext {
+			if !observerConfigured {
+				fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+			} else if sat.TLE != nil {
+				displayNextPass(sat, observer, now)
+			}
 		}
 	}
 }
 
-// displaySatellitesFollow continuously updates position every second
+// displayNextPass prints the AOS, culmination, and LOS of the satellite's next
+// pass above default_min_elevation, or that there is none within 48 hours.
+func displayNextPass(sat *satellite.Satellite, observer *satellite.ObserverPosition, now time.Time) {
+	pass, err := satellite.NextPass(sat.TLE, observer, now, config.DefaultMinElevation)
+	fmt.Printf("Next Pass (above %.1f°):\n", config.DefaultMinElevation)
+	if errors.Is(err, satellite.ErrNoPassFound) {
+		fmt.Println("  None in the next 48 hours")
+		return
+	}
+	if err != nil {
+		fmt.Printf("  Error predicting pass: %v\n", err)
+		return
+	}
+
+	fmt.Printf("  AOS:          %s  (az %5.1f°)\n", pass.AOS.Local().Format("2006-01-02 15:04:05 MST"), pass.AOSAzimuth)
+	fmt.Printf("  Max El:       %s  (%5.1f°)\n", pass.MaxElevationTime.Local().Format("2006-01-02 15:04:05 MST"), pass.MaxElevation)
+	fmt.Printf("  LOS:          %s  (az %5.1f°)\n", pass.LOS.Local().Format("2006-01-02 15:04:05 MST"), pass.LOSAzimuth)
+}
+
+// displaySatellitesFollow continuously updates position every followInterval
 func displaySatellitesFollow(satellites []*satellite.Satellite) {
+	if err := checkFollowInterval(followInterval); err != nil {
+		log.Fatal(err)
+	}
+
 	// Only support single satellite for follow mode
 	if len(satellites) > 1 {
 		fmt.Println("Follow mode only supports a single satellite. Please specify one satellite.")
@@ -215,24 +377,18 @@ func displaySatellitesFollow(satellites []*satellite.Satellite) {
 	}
 
 	// Check if observer is configured
-	observerConfigured := config.ObserverLatitude != 0.0 || config.ObserverLongitude != 0.0
-	if !observerConfigured {
+	observer := currentObserver()
+	if observer == nil {
 		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
 		return
 	}
 
-	observer := &satellite.ObserverPosition{
-		Latitude:  config.ObserverLatitude,
-		Longitude: config.ObserverLongitude,
-		Altitude:  config.ObserverAltitude,
-	}
-
-	// Set up signal handler for Ctrl+C
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// Stop cleanly on Ctrl+C
+	ctx, stop := interruptContext()
+	defer stop()
 
-	// Create ticker for 1-second updates
-	ticker := time.NewTicker(1 * time.Second)
+	// Create ticker for periodic updates
+	ticker := time.NewTicker(followInterval)
 	defer ticker.Stop()
 
 	// Display TLE once at the top
@@ -245,52 +401,81 @@ func displaySatellitesFollow(satellites []*satellite.Satellite) {
 	fmt.Println()
 
 	// Initial display
-	displayCurrentPosition(sat, observer)
+	lines := displayCurrentPosition(sat, observer)
 
 	for {
 		select {
 		case <-ticker.C:
-			// Move cursor up to overwrite previous position (6 lines)
-			fmt.Print("\033[6A")
-			displayCurrentPosition(sat, observer)
+			// Move cursor up to overwrite previous position
+			fmt.Printf("\033[%dA", lines)
+			lines = displayCurrentPosition(sat, observer)
 
-		case <-sigChan:
+		case <-ctx.Done():
 			fmt.Println("\nExiting follow mode...")
 			return
 		}
 	}
 }
 
+// checkFollowInterval rejects follow-mode update intervals that are not positive
+func checkFollowInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive: %v", interval)
+	}
+	return nil
+}
+
 // displayCurrentPosition shows the current position for a single satellite
-func displayCurrentPosition(sat *satellite.Satellite, observer *satellite.ObserverPosition) {
-	now := time.Now()
-	pos, err := satellite.PropagateSatellite(sat.TLE, now)
+// and returns the number of lines written
+func displayCurrentPosition(sat *satellite.Satellite, observer *satellite.ObserverPosition) int {
+	lines := renderPosition(sat, observer, time.Now(), followFrequency*1e6)
+	for _, line := range lines {
+		// Pad to clear leftovers from a previous, longer line
+		fmt.Printf("%-70s\r\n", line)
+	}
+	return len(lines)
+}
+
+// renderPosition formats the live position panel for a satellite at t.
+// The Doppler line is included when frequencyHz is positive.
+func renderPosition(sat *satellite.Satellite, observer *satellite.ObserverPosition, t time.Time, frequencyHz float64) []string {
+	pos, err := satellite.PropagateSatellite(sat.TLE, t)
 	if err != nil {
-		fmt.Printf("Error propagating satellite: %v\n", err)
-		return
+		return []string{fmt.Sprintf("Error propagating satellite: %v", err)}
 	}
 
 	angles := satellite.CalculateObservationAngles(pos, observer)
-	fmt.Printf("Current Position (as of %s):\r\n", now.Format("2006-01-02 15:04:05 MST"))
-	fmt.Printf("  Elevation:    %7.2f°%s\r\n", angles.Elevation, strings.Repeat(" ", 20))
-	fmt.Printf("  Azimuth:      %7.2f°%s\r\n", angles.Azimuth, strings.Repeat(" ", 20))
-	fmt.Printf("  Range:        %10.0f km%s\r\n", angles.Range, strings.Repeat(" ", 20))
-	fmt.Printf("  Range Rate:   %8.2f km/s%s\r\n", angles.RangeRate, strings.Repeat(" ", 20))
-	fmt.Printf("%s\r\n", strings.Repeat(" ", 70))
+	lat, lon, alt := satellite.SubSatellitePoint(pos)
+	lines := []string{
+		fmt.Sprintf("Current Position (as of %s):", t.Format("2006-01-02 15:04:05 MST")),
+		fmt.Sprintf("  Sub-point:    %7.2f°, %7.2f°", lat, lon),
+		fmt.Sprintf("  Altitude:     %10.0f km", alt),
+		fmt.Sprintf("  Elevation:    %7.2f°", angles.Elevation),
+		fmt.Sprintf("  Azimuth:      %7.2f°", angles.Azimuth),
+		fmt.Sprintf("  Range:        %10.0f km", angles.Range),
+		fmt.Sprintf("  Range Rate:   %8.2f km/s", angles.RangeRate),
+	}
+
+	if azRate, elRate, err := satellite.AngleRates(sat.TLE, observer, t); err == nil {
+		lines = append(lines,
+			fmt.Sprintf("  Az Rate:      %8.3f°/s", azRate),
+			fmt.Sprintf("  El Rate:      %8.3f°/s", elRate),
+		)
+	}
+
+	if frequencyHz > 0 {
+		shifted := satellite.DopplerShift(angles, frequencyHz)
+		lines = append(lines, fmt.Sprintf("  Doppler:      %.6f MHz (%+.0f Hz)", shifted/1e6, shifted-frequencyHz))
+	}
+
+	return append(lines, "")
 }
 
 // displaySatellitesVerbose shows TLE, current position, and all metadata
 func displaySatellitesVerbose(satellites []*satellite.Satellite) {
 	// Check if observer is configured
-	observerConfigured := config.ObserverLatitude != 0.0 || config.ObserverLongitude != 0.0
-	var observer *satellite.ObserverPosition
-	if observerConfigured {
-		observer = &satellite.ObserverPosition{
-			Latitude:  config.ObserverLatitude,
-			Longitude: config.ObserverLongitude,
-			Altitude:  config.ObserverAltitude,
-		}
-	}
+	observer := currentObserver()
+	observerConfigured := observer != nil
 
 	now := time.Now()
 
@@ -306,6 +491,8 @@ func displaySatellitesVerbose(satellites []*satellite.Satellite) {
 			fmt.Println(sat.TLE.Line1)
 			fmt.Println(sat.TLE.Line2)
 			fmt.Println()
+		} else {
+			fmt.Printf("No TLE data for %s (%d); it cannot be propagated.\n\n", sat.Name, sat.NoradID)
 		}
 
 		// Current position if observer is configured
@@ -313,7 +500,10 @@ func displaySatellitesVerbose(satellites []*satellite.Satellite) {
 			pos, err := satellite.PropagateSatellite(sat.TLE, now)
 			if err == nil {
 				angles := satellite.CalculateObservationAngles(pos, observer)
+				lat, lon, alt := satellite.SubSatellitePoint(pos)
 				fmt.Printf("Current Position (as of %s):\n", now.Format("2006-01-02 15:04:05 MST"))
+				fmt.Printf("  Sub-point:    %7.2f°, %7.2f°\n", lat, lon)
+				fmt.Printf("  Altitude:     %10.0f km\n", alt)
 				fmt.Printf("  Elevation:    %7.2f°\n", angles.Elevation)
 				fmt.Printf("  Azimuth:      %7.2f°\n", angles.Azimuth)
 				fmt.Printf("  Range:        %10.0f km\n", angles.Range)
@@ -337,6 +527,18 @@ func displaySatellitesVerbose(satellites []*satellite.Satellite) {
 		if sat.OrbitRegime != "" {
 			fmt.Printf("Orbit Regime:   %s\n", sat.OrbitRegime)
 		}
+		if orbitType := satellite.ClassifyOrbitType(sat); orbitType.Special() {
+			fmt.Printf("Orbit Type:     %s\n", orbitType)
+		}
+		if sat.TLE != nil {
+			if epoch, err := sat.TLE.GetEpoch(); err == nil {
+				fmt.Printf("TLE Epoch:      %s (%s old)\n", epoch.Format("2006-01-02 15:04:05 MST"),
+					satellite.FormatRelativeDuration(satellite.TLEAge(epoch)))
+			}
+		}
+		if sat.MissingFromFeed {
+			fmt.Println("TLE Status:     not in the last TLE feed")
+		}
 		if sat.LaunchDate != "" {
 			fmt.Printf("Launch Date:    %s\n", sat.LaunchDate)
 		}
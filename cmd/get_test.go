@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestFollowIntervalFlag(t *testing.T) {
+	previous := followInterval
+	t.Cleanup(func() { followInterval = previous })
+	flag := getCmd.Flags().Lookup("interval")
+	t.Cleanup(func() { flag.Value.Set(flag.DefValue) })
+
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"250ms", 250 * time.Millisecond},
+		{"5s", 5 * time.Second},
+		{"1m30s", 90 * time.Second},
+	}
+	for _, tt := range tests {
+		if err := getCmd.Flags().Parse([]string{"--interval", tt.in}); err != nil || followInterval != tt.want {
+			t.Errorf("--interval %s = %v, %v; want %v", tt.in, followInterval, err, tt.want)
+		}
+		if err := checkFollowInterval(followInterval); err != nil {
+			t.Errorf("checkFollowInterval(%v): %v", followInterval, err)
+		}
+	}
+
+	for _, in := range []string{"fast", "2", ""} {
+		if err := getCmd.Flags().Parse([]string{"--interval", in}); err == nil {
+			t.Errorf("--interval %q parsed as %v, want an error", in, followInterval)
+		}
+	}
+	for _, d := range []time.Duration{0, -time.Second} {
+		if err := checkFollowInterval(d); err == nil {
+			t.Errorf("checkFollowInterval(%v) accepted a non-positive interval", d)
+		}
+	}
+}
+
+func TestRenderPosition(t *testing.T) {
+	sat := testISS()
+	observer := &satellite.ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	at := issEpoch.Add(10 * time.Minute)
+
+	pos, err := satellite.PropagateSatellite(sat.TLE, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	angles := satellite.CalculateObservationAngles(pos, observer)
+	azRate, elRate, err := satellite.AngleRates(sat.TLE, observer, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const frequency = 437.8e6
+	shifted := satellite.DopplerShift(angles, frequency)
+	want := []string{
+		fmt.Sprintf("  Elevation:    %7.2f°", angles.Elevation),
+		fmt.Sprintf("  Azimuth:      %7.2f°", angles.Azimuth),
+		fmt.Sprintf("  Range Rate:   %8.2f km/s", angles.RangeRate),
+		fmt.Sprintf("  Az Rate:      %8.3f°/s", azRate),
+		fmt.Sprintf("  El Rate:      %8.3f°/s", elRate),
+		fmt.Sprintf("  Doppler:      %.6f MHz (%+.0f Hz)", shifted/1e6, shifted-frequency),
+	}
+
+	lines := renderPosition(sat, observer, at, frequency)
+	panel := strings.Join(lines, "\n")
+	for _, line := range want {
+		if !strings.Contains(panel, line) {
+			t.Errorf("panel is missing %q:\n%s", line, panel)
+		}
+	}
+	if shifted == frequency {
+		t.Error("Doppler shift is zero for a moving satellite")
+	}
+
+	// Without a frequency the panel has no Doppler line
+	withoutDoppler := renderPosition(sat, observer, at, 0)
+	if len(withoutDoppler) != len(lines)-1 || strings.Contains(strings.Join(withoutDoppler, "\n"), "Doppler") {
+		t.Errorf("panel without a frequency:\n%s", strings.Join(withoutDoppler, "\n"))
+	}
+}
+
+func TestDisplaySatellitesWithoutTLE(t *testing.T) {
+	loadTestConfig(t, "observer_latitude: 40\nobserver_longitude: -105\n")
+
+	decayed := &satellite.Satellite{NoradID: 12345, Name: "DECAYED", Owner: "CIS", DecayDate: "1990-01-01",
+		Period: 90, Inclination: 65, Apogee: 300, Perigee: 250, OrbitRegime: "LEO"}
+	tleOnly := testISS()
+	tleOnly.Name = "ISS FROM 3LE"
+	merged := testISS()
+	merged.Owner = "ISS"
+	satellites := []*satellite.Satellite{decayed, tleOnly, merged}
+
+	out := captureStdout(t, func() { displaySatellitesComposed(satellites, true, true, true, true) })
+	if !strings.Contains(out, "No TLE data for DECAYED (12345); it cannot be propagated.") ||
+		!strings.Contains(out, "Decay Date:     1990-01-01") || !strings.Contains(out, "Owner:          CIS") {
+		t.Errorf("composed display of a SATCAT-only satellite lacks its note or metadata:\n%s", out)
+	}
+	for _, want := range []string{"0 ISS FROM 3LE\n" + issTLE.Line1, "0 ISS (ZARYA)\n" + issTLE.Line1, "Owner:          ISS"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("composed display lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "No TLE data"); n != 1 {
+		t.Errorf("%d satellites reported without a TLE, want 1", n)
+	}
+
+	out = captureStdout(t, func() { displaySatellitesVerbose(satellites) })
+	if n := strings.Count(out, "No TLE data"); n != 1 || !strings.Contains(out, "No TLE data for DECAYED (12345)") {
+		t.Errorf("verbose display reported %d satellites without a TLE, want DECAYED only:\n%s", n, out)
+	}
+	if n := strings.Count(out, issTLE.Line2); n != 2 {
+		t.Errorf("verbose display shows %d ISS TLEs, want 2", n)
+	}
+}
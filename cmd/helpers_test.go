@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// issTLE is a published ISS element set, with its epoch at issEpoch.
+var issTLE = satellite.TLE{
+	Line1: "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+	Line2: "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+}
+
+// issEpoch is the epoch of issTLE, 2008 day 264.51782528.
+var issEpoch = time.Date(2008, time.September, 20, 12, 25, 40, 104192000, time.UTC)
+
+// testISS returns the ISS as a catalog satellite with issTLE.
+func testISS() *satellite.Satellite {
+	tle := issTLE
+	return &satellite.Satellite{NoradID: 25544, Name: "ISS (ZARYA)", TLE: &tle, TLEEpoch: issEpoch}
+}
+
+// captureStdout returns what fn writes to standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+// captureStderr returns what fn writes to standard error.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+// captureFile returns what fn writes to *file, which it replaces with a pipe
+// while fn runs.
+func captureFile(t *testing.T, file **os.File, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := *file
+	*file = w
+	defer func() { *file = saved }()
+
+	out := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- data
+	}()
+	fn()
+	w.Close()
+	return string(<-out)
+}
+
+// tleChecksum returns the modulo-10 checksum of a TLE line.
+func tleChecksum(line string) int {
+	sum := 0
+	for _, c := range line {
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// leoTLE returns a drag-free ISS-like TLE with its epoch at epoch, so that
+// it propagates cleanly for days around it.
+func leoTLE(noradID int, epoch time.Time) satellite.TLE {
+	day := float64(epoch.YearDay()) + float64(epoch.Sub(epoch.Truncate(24*time.Hour)))/float64(24*time.Hour)
+	line1 := fmt.Sprintf("1 %05dU 24001A   %02d%012.8f  .00000000  00000-0  00000-0 0  999", noradID, epoch.Year()%100, day)
+	line2 := fmt.Sprintf("2 %05d  51.6400 200.0000 0005000  90.0000 270.0000 15.50000000    1", noradID)
+	return satellite.TLE{
+		Line1: fmt.Sprintf("%s%d", line1, tleChecksum(line1)),
+		Line2: fmt.Sprintf("%s%d", line2, tleChecksum(line2)),
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// loadIDFile reads a file of NORAD IDs or names, one per line with '#'
+// comments, and returns the catalog satellites it names. Entries that match
+// no satellite, or several, are reported on stderr. Exits if the file can't
+// be read.
+func loadIDFile(path string, satellites []*satellite.Satellite) []*satellite.Satellite {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Error opening id file: %v", err)
+	}
+	defer file.Close()
+
+	queries, err := satellite.ReadIDList(file)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", path, err)
+	}
+
+	selected, errs := satellite.SelectSatellites(satellites, queries)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", path, err)
+	}
+	return selected
+}
+
+// loadIDFileIDs is loadIDFile returning NORAD IDs, restricted to those in
+// restrict unless it is nil.
+func loadIDFileIDs(path string, satellites []*satellite.Satellite, restrict []int) []int {
+	var allowed map[int]bool
+	if restrict != nil {
+		allowed = make(map[int]bool, len(restrict))
+		for _, id := range restrict {
+			allowed[id] = true
+		}
+	}
+
+	var ids []int
+	for _, sat := range loadIDFile(path, satellites) {
+		if allowed == nil || allowed[sat.NoradID] {
+			ids = append(ids, sat.NoradID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestLoadIDFileIDs(t *testing.T) {
+	satellites := []*satellite.Satellite{
+		testISS(),
+		{NoradID: 20580, Name: "HST"},
+		{NoradID: 43013, Name: "NOAA 20"},
+		{NoradID: 28654, Name: "NOAA 18"},
+	}
+	path := filepath.Join(t.TempDir(), "sats.txt")
+	content := `# Satellites for tonight
+25544
+  hst   # by exact name, case-insensitive
+noaa 20
+99999     # not in the catalog
+NOAA      # matches two satellites
+25544     # repeated
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	stderr := captureStderr(t, func() { ids = loadIDFileIDs(path, satellites, nil) })
+	if want := []int{25544, 20580, 43013}; !slices.Equal(ids, want) {
+		t.Errorf("loadIDFileIDs = %v, want %v in file order without repeats", ids, want)
+	}
+	for _, want := range []string{"no satellite with NORAD ID 99999", `"NOAA" matches 2 satellites`} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("warnings %q do not report %s", stderr, want)
+		}
+	}
+	if lines := strings.Count(stderr, "\n"); lines != 2 {
+		t.Errorf("%d warnings, want 2:\n%s", lines, stderr)
+	}
+
+	// Restricted to a tag or watchlist, only the listed satellites in it remain
+	captureStderr(t, func() { ids = loadIDFileIDs(path, satellites, []int{43013, 25544, 12345}) })
+	if want := []int{25544, 43013}; !slices.Equal(ids, want) {
+		t.Errorf("restricted loadIDFileIDs = %v, want %v", ids, want)
+	}
+	captureStderr(t, func() { ids = loadIDFileIDs(path, satellites, []int{}) })
+	if len(ids) != 0 {
+		t.Errorf("loadIDFileIDs restricted to nothing = %v", ids)
+	}
+}
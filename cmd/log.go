@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logInterval   time.Duration
+	logOutput     string
+	logFlushEvery int
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log NORAD_ID",
+	Short: "Continuously log a satellite's position to a CSV file",
+	Long: `Propagate a satellite at a fixed interval and append time, position, and
+observation angles (if an observer is configured) to a CSV file until interrupted.
+Rows are flushed periodically and on Ctrl+C.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runLog(args)
+	},
+}
+
+// logHeader is the CSV header written to new log files
+var logHeader = []string{
+	"time", "x_km", "y_km", "z_km", "vx_km_s", "vy_km_s", "vz_km_s",
+	"azimuth_deg", "elevation_deg", "range_km", "range_rate_km_s",
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().DurationVarP(&logInterval, "interval", "i", 10*time.Second, "Time between logged positions")
+	logCmd.Flags().StringVarP(&logOutput, "output", "o", "track.csv", "CSV file to append rows to")
+	logCmd.Flags().IntVar(&logFlushEvery, "flush-every", 10, "Flush to disk after this many rows")
+}
+
+func runLog(args []string) {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		log.Fatalf("Invalid NORAD ID: %s", args[0])
+	}
+	if logInterval <= 0 {
+		log.Fatalf("Interval must be positive: %v", logInterval)
+	}
+
+	store := newCatalogStore()
+
+	catalog, err := store.Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	matches := satellite.FilterSatellites(catalog.Satellites, id, "")
+	if len(matches) == 0 || matches[0].TLE == nil {
+		fmt.Printf("No TLE data found for NORAD ID %d.\n", id)
+		return
+	}
+	sat := matches[0]
+
+	// Observation angles are only logged when an observer is configured
+	observer := currentObserver()
+
+	file, err := os.OpenFile(logOutput, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatalf("Failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+
+	// Only write the header when starting a new file
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		if err := w.Write(logHeader); err != nil {
+			log.Fatalf("Error writing header: %v", err)
+		}
+	}
+
+	// Stop cleanly on Ctrl+C
+	ctx, stop := interruptContext()
+	defer stop()
+
+	ticker := time.NewTicker(logInterval)
+	defer ticker.Stop()
+
+	fmt.Printf("Logging %s (%d) every %v to %s\n", sat.Name, sat.NoradID, logInterval, logOutput)
+	fmt.Println("Press Ctrl+C to stop")
+
+	rows, err := logPositions(ctx, w, sat, observer, time.Now(), ticker.C, logFlushEvery)
+	if err != nil {
+		log.Fatalf("Error writing log: %v", err)
+	}
+
+	fmt.Printf("\nLogged %d rows to %s\n", rows, logOutput)
+}
+
+// logPositions writes a row at start and one for each tick until ctx is
+// canceled, flushing every flushEvery rows and once more before returning,
+// also on error, so rows already written are not lost.
+// Returns the number of rows written.
+func logPositions(ctx context.Context, w *csv.Writer, sat *satellite.Satellite, observer *satellite.ObserverPosition,
+	start time.Time, ticks <-chan time.Time, flushEvery int) (int, error) {
+	rows := 0
+
+	write := func(t time.Time) error {
+		if err := writeLogRow(w, sat, observer, t); err != nil {
+			return err
+		}
+		rows++
+		if flushEvery > 0 && rows%flushEvery == 0 {
+			w.Flush()
+			return w.Error()
+		}
+		return nil
+	}
+	fail := func(err error) (int, error) {
+		w.Flush()
+		return rows, err
+	}
+
+	if err := write(start); err != nil {
+		return fail(err)
+	}
+
+	for {
+		select {
+		case t := <-ticks:
+			if err := write(t); err != nil {
+				return fail(err)
+			}
+
+		case <-ctx.Done():
+			w.Flush()
+			return rows, w.Error()
+		}
+	}
+}
+
+// writeLogRow propagates the satellite to t and writes a single CSV row.
+// Angle columns are left empty when observer is nil.
+func writeLogRow(w *csv.Writer, sat *satellite.Satellite, observer *satellite.ObserverPosition, t time.Time) error {
+	pos, err := satellite.PropagateSatellite(sat.TLE, t)
+	if err != nil {
+		return fmt.Errorf("propagating at %v: %w", t, err)
+	}
+
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+
+	row := []string{
+		t.UTC().Format(time.RFC3339),
+		f(pos.X, 3), f(pos.Y, 3), f(pos.Z, 3),
+		f(pos.Vx, 6), f(pos.Vy, 6), f(pos.Vz, 6),
+		"", "", "", "",
+	}
+
+	if observer != nil {
+		angles := satellite.CalculateObservationAngles(pos, observer)
+		row[7] = f(angles.Azimuth, 3)
+		row[8] = f(angles.Elevation, 3)
+		row[9] = f(angles.Range, 3)
+		row[10] = f(angles.RangeRate, 6)
+	}
+
+	return w.Write(row)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// runLogPositions runs logPositions in the background and returns a channel
+// for its ticks and one for its result.
+func runLogPositions(ctx context.Context, buf *bytes.Buffer, sat *satellite.Satellite, observer *satellite.ObserverPosition,
+	start time.Time, flushEvery int) (chan<- time.Time, <-chan error) {
+	ticks := make(chan time.Time)
+	done := make(chan error, 1)
+	go func() {
+		_, err := logPositions(ctx, csv.NewWriter(buf), sat, observer, start, ticks, flushEvery)
+		done <- err
+	}()
+	return ticks, done
+}
+
+func TestLogPositionsInjectedClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf bytes.Buffer
+	observer := &satellite.ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	ticks, done := runLogPositions(ctx, &buf, testISS(), observer, issEpoch, 0)
+	for i := 1; i <= 3; i++ {
+		ticks <- issEpoch.Add(time.Duration(i) * 10 * time.Second)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("logPositions: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4 (start and three ticks)", len(rows))
+	}
+	for i, row := range rows {
+		want := issEpoch.Add(time.Duration(i) * 10 * time.Second).Format(time.RFC3339)
+		if row[0] != want {
+			t.Errorf("row %d time = %s, want %s", i, row[0], want)
+		}
+		if len(row) != len(logHeader) {
+			t.Errorf("row %d has %d columns, want %d", i, len(row), len(logHeader))
+		}
+		if row[8] == "" {
+			t.Errorf("row %d has no elevation with an observer", i)
+		}
+	}
+}
+
+func TestLogPositionsFlushesOnError(t *testing.T) {
+	// A very low orbit with heavy drag, which SGP4 reports decayed within an hour
+	epoch := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sat := &satellite.Satellite{NoradID: 99998, Name: "REENTRY", TLE: &satellite.TLE{
+		Line1: "1 99998U 24001A   24061.00000000  .00000000  00000-0  50000-0 0  9990",
+		Line2: "2 99998  51.6000 200.0000 0001000  90.0000 270.0000 16.30000000    10",
+	}}
+
+	var buf bytes.Buffer
+	ticks, done := runLogPositions(context.Background(), &buf, sat, nil, epoch, 100)
+	ticks <- epoch.Add(time.Second)
+	ticks <- epoch.Add(24 * time.Hour)
+	if err := <-done; err == nil {
+		t.Fatal("logPositions returned no error after decay")
+	}
+
+	// The two rows before the failure were buffered, not yet flushed by count
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows after the error, want the 2 written before it", len(rows))
+	}
+	if rows[0][7] != "" {
+		t.Errorf("azimuth = %q without an observer, want empty", rows[0][7])
+	}
+}
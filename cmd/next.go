@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var nextCmd = &cobra.Command{
+	Use:   "next NAME_OR_ID",
+	Short: "Predict the next pass of a satellite",
+	Long: `Predict the next pass of a satellite over the configured observer.
+The catalog is fetched first if it is missing or stale (unless auto_fetch is
+disabled in config); for a NORAD ID in a stale catalog only that satellite's
+TLE is fetched, from tle_object_endpoint. The satellite can be given by NORAD
+ID, exact name, or a unique partial name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runNext(args)
+	},
+}
+
+var (
+	nextExport     string
+	nextExportStep time.Duration
+	nextFrequency  float64
+)
+
+func init() {
+	rootCmd.AddCommand(nextCmd)
+	nextCmd.Flags().StringVarP(&nextExport, "export", "e", "", "Write a Gpredict-style az/el listing of the pass to this file")
+	nextCmd.Flags().Float64VarP(&nextFrequency, "frequency", "f", 0, "Downlink frequency in MHz; shows the Doppler shift over the pass")
+	nextCmd.Flags().DurationVar(&nextExportStep, "export-step", time.Second, "Time between rows of the az/el listing")
+}
+
+func runNext(args []string) {
+	observer := currentObserver()
+	if observer == nil {
+		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+		return
+	}
+
+	sat := loadSatellite(args[0])
+	if sat == nil {
+		return
+	}
+
+	now := time.Now()
+	pass, err := satellite.NextPass(sat.TLE, observer, now, config.DefaultMinElevation)
+	if errors.Is(err, satellite.ErrNoPassFound) {
+		fmt.Printf("%s (%d) has no pass above %.1f° in the next 48 hours.\n",
+			sat.Name, sat.NoradID, config.DefaultMinElevation)
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error predicting pass: %v", err)
+	}
+
+	fmt.Printf("Next pass of %s (%d), %s\n", sat.Name, sat.NoradID, satellite.FormatPassRelative(pass, now, 0))
+	fmt.Println()
+	fmt.Printf("  AOS:            %s  (az %5.1f°)\n", pass.AOS.Local().Format("2006-01-02 15:04:05 MST"), pass.AOSAzimuth)
+	fmt.Printf("  Max elevation:  %s  (%5.1f°)", pass.MaxElevationTime.Local().Format("2006-01-02 15:04:05 MST"), pass.MaxElevation)
+	if pass.IsZenithPass() {
+		fmt.Printf("  zenith pass, %.1f° from zenith", pass.MinZenithAngle())
+	}
+	fmt.Println()
+	fmt.Printf("  LOS:            %s  (az %5.1f°)\n", pass.LOS.Local().Format("2006-01-02 15:04:05 MST"), pass.LOSAzimuth)
+	fmt.Printf("  Duration:       %v\n", pass.Duration.Round(time.Second))
+	fmt.Printf("  Direction:      %s", satellite.PassDirection(pass))
+	if inclination, err := sat.TLE.GetInclination(); err == nil {
+		fmt.Printf("  (%s orbit)", satellite.OrbitSense(inclination))
+	}
+	fmt.Println()
+	fmt.Printf("  Closest range:  %.1f km\n", pass.MinRange)
+	sun := satellite.SunObservationAngles(observer, pass.MaxElevationTime)
+	fmt.Printf("  Sun at max el:  az %5.1f°, el %5.1f°\n", sun.Azimuth, sun.Elevation)
+
+	if nextFrequency > 0 {
+		doppler, err := satellite.PassDoppler(pass, sat.TLE, observer, nextFrequency*1e6)
+		if err != nil {
+			log.Fatalf("Error computing Doppler: %v", err)
+		}
+		fmt.Println()
+		fmt.Printf("Doppler at %.4f MHz:\n", nextFrequency)
+		fmt.Printf("  Max upshift:    %+.0f Hz\n", doppler.MaxUpshift)
+		fmt.Printf("  Max downshift:  %+.0f Hz\n", doppler.MaxDownshift)
+		fmt.Printf("  Peak rate:      %+.1f Hz/s at %s\n", doppler.PeakRate,
+			doppler.PeakRateTime.Local().Format("15:04:05 MST"))
+	}
+
+	if nextExport != "" {
+		file, err := os.Create(nextExport)
+		if err != nil {
+			log.Fatalf("Failed to create export file: %v", err)
+		}
+		defer file.Close()
+
+		if err := satellite.WritePassAzEl(file, sat, observer, pass, nextExportStep); err != nil {
+			log.Fatalf("Error writing az/el listing: %v", err)
+		}
+		fmt.Printf("\nAz/el listing written to %s\n", nextExport)
+	}
+}
+
+// loadSatellite loads the catalog, fetching it if missing or stale, and
+// resolves query to a satellite with a TLE. A NORAD ID in a stale catalog is
+// refreshed on its own from tle_object_endpoint instead, which is far cheaper
+// than fetching the whole catalog. Prints why and returns nil if there is no
+// catalog or the satellite has no TLE; exits if query does not resolve.
+func loadSatellite(query string) *satellite.Satellite {
+	sat, _ := loadSatelliteAndCatalog(query)
+	return sat
+}
+
+// loadSatelliteAndCatalog is loadSatellite for commands that also need the
+// rest of the catalog, which is returned with the satellite so that it is
+// loaded only once.
+func loadSatelliteAndCatalog(query string) (*satellite.Satellite, *satellite.Catalog) {
+	store := newSnapshotStore()
+
+	catalog, err := store.Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+
+	_, idErr := strconv.Atoi(query)
+	stale := config.IsCatalogStale(catalog)
+	singleRefresh := catalog != nil && stale && idErr == nil && config.TLEObjectEndpoint != ""
+
+	fetched := false
+	if catalog == nil || (stale && !singleRefresh) {
+		catalog, fetched, err = satellite.LoadOrFetchCatalog(store, newAPIClient(), config)
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return nil, nil
+	}
+	if fetched {
+		fmt.Print("Fetched a fresh catalog.\n\n")
+	}
+
+	sat, err := satellite.ResolveSatellite(catalog.Satellites, query)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if singleRefresh {
+		refreshSatelliteTLE(sat)
+	}
+	if sat.TLE == nil {
+		fmt.Printf("No TLE data found for %s (%d).\n", sat.Name, sat.NoradID)
+		return nil, nil
+	}
+	return sat, catalog
+}
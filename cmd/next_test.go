@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextFetchesAndPredicts(t *testing.T) {
+	tle := leoTLE(25544, time.Now().UTC())
+	var tleRequests int
+	feeds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tle":
+			tleRequests++
+			fmt.Fprintf(w, "ISS (ZARYA)\n%s\n%s\n", tle.Line1, tle.Line2)
+		case "/satcat":
+			fmt.Fprint(w, `[{"noradId": 25544, "name": "ISS (ZARYA)", "intlDesignator": "1998-067A"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer feeds.Close()
+
+	loadTestConfig(t, fmt.Sprintf(`observer_latitude: 40
+observer_longitude: -105
+auto_fetch: true
+tle_endpoint: %s/tle
+satcat_endpoint: %s/satcat
+tle_fallback_endpoints: []
+satcat_fallback_endpoints: []
+`, feeds.URL, feeds.URL))
+
+	out := captureStdout(t, func() { runNext([]string{"ISS"}) })
+	for _, want := range []string{"Fetched a fresh catalog.", "Next pass of ISS (ZARYA) (25544)", "AOS:", "Max elevation:", "LOS:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("next output lacks %q:\n%s", want, out)
+		}
+	}
+
+	// The saved catalog is fresh, so a second run does not fetch again
+	out = captureStdout(t, func() { runNext([]string{"25544"}) })
+	if tleRequests != 1 || strings.Contains(out, "Fetched") || !strings.Contains(out, "Next pass of ISS (ZARYA)") {
+		t.Errorf("second run made %d TLE requests and printed:\n%s", tleRequests, out)
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overheadCone  float64
+	overheadLimit int
+)
+
+var overheadCmd = &cobra.Command{
+	Use:   "overhead",
+	Short: "List satellites closest to straight overhead",
+	Long: `List the satellites currently within a cone around the observer's zenith,
+closest to straight overhead first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runOverhead()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(overheadCmd)
+	overheadCmd.Flags().Float64Var(&overheadCone, "cone", 15, "Maximum angle from the zenith in degrees")
+	overheadCmd.Flags().IntVarP(&overheadLimit, "limit", "l", 20, "Maximum number of results to display (0 = no limit)")
+}
+
+func runOverhead() {
+	observer := currentObserver()
+	if observer == nil {
+		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+		return
+	}
+
+	store := newCatalogStore()
+	catalog, err := store.Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	now := time.Now()
+	overhead, err := satellite.SatellitesNearZenith(catalog.Satellites, observer, now, overheadCone)
+	if err != nil {
+		log.Fatalf("Error finding overhead satellites: %v", err)
+	}
+
+	if len(overhead) == 0 {
+		fmt.Printf("No satellites within %.1f° of the zenith.\n", overheadCone)
+		return
+	}
+
+	fmt.Printf("%d satellites within %.1f° of the zenith at %s\n\n", len(overhead), overheadCone, now.Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("%-8s  %-40s  %-7s  %-7s  %-11s\n", "NORAD", "Name", "Zen (°)", "Az (°)", "Range (km)")
+	fmt.Println(strings.Repeat("-", 80))
+	for i, v := range overhead {
+		if overheadLimit > 0 && i == overheadLimit {
+			fmt.Printf("\n... %d more. Use --limit to show more.\n", len(overhead)-overheadLimit)
+			break
+		}
+		fmt.Printf("%-8d  %-40s  %7.2f  %7.2f  %11.0f\n",
+			v.Satellite.NoradID, v.Satellite.Name, 90-v.Angles.Elevation, v.Angles.Azimuth, v.Angles.Range)
+	}
+}
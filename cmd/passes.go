@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var passesCmd = &cobra.Command{
+	Use:   "passes [NAME_OR_ID]",
+	Short: "List upcoming passes of a satellite",
+	Long: `List the passes of a satellite over the configured observer in the coming
+hours, with rise and set times and azimuths and the maximum elevation.
+The satellite can be given by NORAD ID, exact name, or a unique partial name.
+With --id-file, list the passes of every satellite in the file together, in
+order of rise time.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runPasses(args)
+	},
+}
+
+var (
+	passesHours        float64
+	passesMinElevation string
+	passesStep         time.Duration
+	passesIDFile       string
+)
+
+func init() {
+	rootCmd.AddCommand(passesCmd)
+	passesCmd.Flags().Float64Var(&passesHours, "hours", 24, "How many hours ahead to search")
+	passesCmd.Flags().StringVar(&passesMinElevation, "min-elevation", "", "Minimum elevation angle in degrees, or 'horizon' for 0 (default from config)")
+	passesCmd.Flags().DurationVar(&passesStep, "step", 30*time.Second, "Sampling interval for the pass search")
+	passesCmd.Flags().StringVar(&passesIDFile, "id-file", "", "List passes of the satellites in this file (NORAD IDs or names, one per line)")
+}
+
+func runPasses(args []string) {
+	if (len(args) == 0) == (passesIDFile == "") {
+		log.Fatal("Give either a satellite or --id-file")
+	}
+	if passesHours <= 0 {
+		log.Fatalf("Hours must be positive: %v", passesHours)
+	}
+	if passesStep <= 0 {
+		log.Fatalf("Step must be positive: %v", passesStep)
+	}
+	minElevation, err := resolveMinElevation(passesMinElevation)
+	if err != nil {
+		log.Fatalf("Invalid --min-elevation: %v", err)
+	}
+
+	observer := currentObserver()
+	if observer == nil {
+		fmt.Println("Observer location not configured. Set observer_latitude, observer_longitude, and observer_altitude in config.")
+		return
+	}
+
+	start := time.Now()
+	end := start.Add(time.Duration(passesHours * float64(time.Hour)))
+
+	if passesIDFile != "" {
+		runPassesIDFile(observer, start, end, minElevation)
+		return
+	}
+
+	sat := loadSatellite(args[0])
+	if sat == nil {
+		return
+	}
+
+	passes, err := satellite.PredictPasses(sat.TLE, observer, start, end, passesStep, minElevation)
+	if err != nil {
+		log.Fatalf("Error predicting passes: %v", err)
+	}
+
+	if len(passes) == 0 {
+		fmt.Printf("%s (%d) has no pass above %.1f° in the next %v hours.\n",
+			sat.Name, sat.NoradID, minElevation, passesHours)
+		return
+	}
+
+	fmt.Printf("Passes of %s (%d) above %.1f° in the next %v hours\n\n", sat.Name, sat.NoradID, minElevation, passesHours)
+	fmt.Printf("%-23s %6s  %-8s %6s  %-8s %6s  %8s  %-13s  %s\n", "AOS", "Az", "Max El", "El", "LOS", "Az", "Duration", "Sun at max el", "Direction")
+	for _, pass := range passes {
+		fmt.Printf("%-23s %5.1f°  %-8s %5.1f°  %-8s %5.1f°  %8v  %-13s  %s\n",
+			pass.AOS.Local().Format("2006-01-02 15:04:05 MST"), pass.AOSAzimuth,
+			pass.MaxElevationTime.Local().Format("15:04:05"), pass.MaxElevation,
+			pass.LOS.Local().Format("15:04:05"), pass.LOSAzimuth,
+			pass.Duration.Round(time.Second), formatSunAngles(observer, &pass), satellite.PassDirection(&pass))
+	}
+}
+
+// runPassesIDFile lists the passes of every satellite in --id-file together.
+func runPassesIDFile(observer *satellite.ObserverPosition, start, end time.Time, minElevation float64) {
+	catalog, err := newCatalogStore().Load()
+	if err != nil {
+		log.Fatalf("Error loading catalog: %v", err)
+	}
+	if catalog == nil {
+		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+		return
+	}
+
+	var sats []*satellite.Satellite
+	for _, sat := range loadIDFile(passesIDFile, catalog.Satellites) {
+		if sat.TLE != nil {
+			sats = append(sats, sat)
+		}
+	}
+	if len(sats) == 0 {
+		fmt.Printf("None of the satellites in %s are in the catalog.\n", passesIDFile)
+		return
+	}
+
+	schedule, errs := satellite.PredictSchedule(sats, observer, start, end, passesStep, minElevation, newProgressBar("Predicting"))
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if len(schedule) == 0 {
+		fmt.Printf("None of the %d satellites has a pass above %.1f° in the next %v hours.\n",
+			len(sats), minElevation, passesHours)
+		return
+	}
+
+	fmt.Printf("%d passes of %d satellites above %.1f° in the next %v hours\n\n",
+		len(schedule), len(sats), minElevation, passesHours)
+	fmt.Printf("%-24s  %-23s %6s  %-8s %6s  %-8s %6s  %8s  %s\n", "Satellite", "AOS", "Az", "Max El", "El", "LOS", "Az", "Duration", "Sun at max el")
+	for _, sp := range schedule {
+		pass := sp.Pass
+		fmt.Printf("%-24.24s  %-23s %5.1f°  %-8s %5.1f°  %-8s %5.1f°  %8v  %s\n",
+			sp.Satellite.Name,
+			pass.AOS.Local().Format("2006-01-02 15:04:05 MST"), pass.AOSAzimuth,
+			pass.MaxElevationTime.Local().Format("15:04:05"), pass.MaxElevation,
+			pass.LOS.Local().Format("15:04:05"), pass.LOSAzimuth,
+			pass.Duration.Round(time.Second), formatSunAngles(observer, &pass))
+	}
+}
+
+// formatSunAngles formats the Sun's azimuth and elevation at the observer at
+// the pass's culmination as "az/el".
+func formatSunAngles(observer *satellite.ObserverPosition, pass *satellite.Pass) string {
+	sun := satellite.SunObservationAngles(observer, pass.MaxElevationTime)
+	return fmt.Sprintf("%5.1f°/%5.1f°", sun.Azimuth, sun.Elevation)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+const progressBarWidth = 30
+
+// newProgressBar returns a ProgressFunc that draws a progress bar on stderr,
+// or nil when stderr is not a terminal so redirected output stays clean.
+func newProgressBar(label string) satellite.ProgressFunc {
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	lastPercent := -1
+	return func(fraction float64) {
+		percent := int(fraction * 100)
+		if percent == lastPercent {
+			return
+		}
+		lastPercent = percent
+
+		filled := int(fraction * progressBarWidth)
+		fmt.Fprintf(os.Stderr, "\r%s [%s%s] %3d%%", label,
+			strings.Repeat("#", filled), strings.Repeat(" ", progressBarWidth-filled), percent)
+
+		// Clear the bar once done so it doesn't mix with the results
+		if fraction >= 1 {
+			fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", len(label)+progressBarWidth+8))
+		}
+	}
+}
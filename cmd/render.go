@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+var (
+	jsonOutput        bool
+	jsonFullPrecision bool
+	jsonNamingFlag    string
+)
+
+// renderer presents a command's result either as human-readable text or as
+// JSON, selected by the global --json flag.
+type renderer interface {
+	// renderText prints the result for people to read.
+	renderText()
+	// jsonValue returns the result as a value to encode as JSON, with
+	// positions and angles rounded to decimals places and object keys cased
+	// per naming.
+	jsonValue(decimals int, naming satellite.JSONNaming) any
+}
+
+// render prints r as JSON if --json is set, otherwise as text.
+func render(r renderer) {
+	if !jsonOutput {
+		r.renderText()
+		return
+	}
+	writeJSON(r.jsonValue(jsonDecimals(), jsonNaming()))
+}
+
+// jsonDecimals returns the decimal places JSON positions and angles are
+// rounded to: json_decimals, or FullPrecision with --full-precision.
+func jsonDecimals() int {
+	if jsonFullPrecision {
+		return satellite.FullPrecision
+	}
+	return config.JSONDecimals
+}
+
+// jsonNaming returns the key casing of JSON output: --json-naming, or
+// json_naming. Exits if it is invalid.
+func jsonNaming() satellite.JSONNaming {
+	setting := config.JSONNaming
+	if jsonNamingFlag != "" {
+		setting = jsonNamingFlag
+	}
+	naming, err := satellite.ParseJSONNaming(setting)
+	if err != nil {
+		log.Fatalf("Invalid JSON naming: %v", err)
+	}
+	return naming
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Error encoding JSON: %v", err)
+	}
+	data = append(data, '\n')
+	if _, err := os.Stdout.Write(data); err != nil {
+		log.Fatalf("Error writing JSON: %v", err)
+	}
+}
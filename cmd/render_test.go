@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// withJSONFlags sets the global JSON flags for the duration of a test.
+func withJSONFlags(t *testing.T, fullPrecision bool, naming string) {
+	t.Helper()
+	prevOutput, prevPrecision, prevNaming := jsonOutput, jsonFullPrecision, jsonNamingFlag
+	t.Cleanup(func() { jsonOutput, jsonFullPrecision, jsonNamingFlag = prevOutput, prevPrecision, prevNaming })
+	jsonOutput, jsonFullPrecision, jsonNamingFlag = true, fullPrecision, naming
+}
+
+func TestRenderRoundsJSON(t *testing.T) {
+	loadTestConfig(t, "json_decimals: 2\n")
+	angles := &satellite.ObservationAngles{Azimuth: 123.456789, Elevation: 45.678912, Range: 987.654321}
+	result := &visibleResult{visible: []*satellite.VisibleSatellite{{Satellite: testISS(), Angles: angles}}}
+
+	decode := func(out string) satellite.ObservationAngles {
+		t.Helper()
+		var visible []struct {
+			Angles struct {
+				Azimuth, Elevation, Range float64
+			}
+		}
+		if err := json.Unmarshal([]byte(out), &visible); err != nil || len(visible) != 1 {
+			t.Fatalf("rendered %d results (%v):\n%s", len(visible), err, out)
+		}
+		a := visible[0].Angles
+		return satellite.ObservationAngles{Azimuth: a.Azimuth, Elevation: a.Elevation, Range: a.Range}
+	}
+
+	withJSONFlags(t, false, "")
+	got := decode(captureStdout(t, func() { render(result) }))
+	if got.Azimuth != 123.46 || got.Elevation != 45.68 || got.Range != 987.65 {
+		t.Errorf("json_decimals 2 rendered %+v", got)
+	}
+
+	withJSONFlags(t, true, "")
+	got = decode(captureStdout(t, func() { render(result) }))
+	if got.Azimuth != angles.Azimuth || got.Elevation != angles.Elevation || got.Range != angles.Range {
+		t.Errorf("--full-precision rendered %+v, want %+v", got, *angles)
+	}
+
+	if angles.Azimuth != 123.456789 {
+		t.Error("rendering rounded the result in place")
+	}
+}
+
+func TestRenderJSONNamingKeepsMapKeys(t *testing.T) {
+	loadTestConfig(t, "json_naming: snake\n")
+	catalog := &satellite.Catalog{Satellites: []*satellite.Satellite{testISS()}, FetchedAt: issEpoch}
+	result := &statsResult{
+		catalog:   catalog,
+		freshness: satellite.CatalogFreshness(catalog),
+		regimes:   satellite.RegimeDistribution(catalog),
+	}
+
+	withJSONFlags(t, false, "")
+	var snake map[string]json.RawMessage
+	out := captureStdout(t, func() { render(result) })
+	if err := json.Unmarshal([]byte(out), &snake); err != nil {
+		t.Fatalf("rendered invalid JSON: %v\n%s", err, out)
+	}
+	if _, ok := snake["dropped_tles"]; !ok {
+		t.Errorf("json_naming snake rendered %s, want snake_case field keys", out)
+	}
+	var regimes map[satellite.OrbitRegime]int
+	if err := json.Unmarshal(snake["regimes"], &regimes); err != nil || regimes[satellite.RegimeLEO] != 1 {
+		t.Errorf("regimes rendered as %s, want the regime names kept", snake["regimes"])
+	}
+
+	// --json-naming overrides the config, and camel case round-trips
+	withJSONFlags(t, false, "camel")
+	var camel struct {
+		Satellites  int                           `json:"satellites"`
+		DroppedTLEs int                           `json:"droppedTles"`
+		Regimes     map[satellite.OrbitRegime]int `json:"regimes"`
+	}
+	out = captureStdout(t, func() { render(result) })
+	if err := json.Unmarshal([]byte(out), &camel); err != nil || camel.Satellites != 1 || camel.Regimes[satellite.RegimeLEO] != 1 {
+		t.Errorf("--json-naming camel rendered %+v (%v):\n%s", camel, err, out)
+	}
+}
+
+func TestRenderJSONNamingGetAndSearch(t *testing.T) {
+	loadTestConfig(t, "")
+	sats := []*satellite.Satellite{testISS()}
+
+	tests := []struct {
+		naming string
+		result renderer
+		want   []string
+		not    []string
+	}{
+		{"snake", &getResult{satellites: sats}, []string{`"orbit_type":`, `"norad_id": 25544`, `"tle_epoch":`}, []string{`"orbitType"`, `"noradId"`}},
+		{"camel", &getResult{satellites: sats}, []string{`"orbitType":`, `"noradId": 25544`}, []string{`"orbit_type"`, `"norad_id"`}},
+		{"snake", &searchResult{results: sats}, []string{`"norad_id": 25544`, `"intl_id":`}, []string{`"noradId"`}},
+		{"snake", &searchResult{results: nil}, []string{`[]`}, nil},
+	}
+	for _, tt := range tests {
+		withJSONFlags(t, false, tt.naming)
+		out := captureStdout(t, func() { render(tt.result) })
+		if !json.Valid([]byte(out)) {
+			t.Fatalf("%T rendered invalid JSON:\n%s", tt.result, out)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%T with %s naming rendered %s, want %s", tt.result, tt.naming, out, want)
+			}
+		}
+		for _, not := range tt.not {
+			if strings.Contains(out, not) {
+				t.Errorf("%T with %s naming rendered %s", tt.result, tt.naming, not)
+			}
+		}
+	}
+}
@@ -2,15 +2,19 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
 
+	"github.com/dzeleniak/icu/internal/storage"
 	"github.com/dzeleniak/icu/pkg/satellite"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	config  *satellite.Config
+	cfgFile  string
+	siteName string
+	config   *satellite.Config
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -39,13 +43,111 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.icu/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&siteName, "site", "", "observe from a named site in config (or a built-in observatory) instead of the configured observer")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output results as JSON (get, search, search visible, stats)")
+	rootCmd.PersistentFlags().BoolVar(&jsonFullPrecision, "full-precision", false, "do not round numbers in JSON output (default rounds to json_decimals)")
+	rootCmd.PersistentFlags().StringVar(&jsonNamingFlag, "json-naming", "", "key casing in JSON output, camel or snake (default from json_naming)")
 }
 
 func initConfig() {
 	var err error
-	config, err = InitConfig()
+	config, err = InitConfig(cfgFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// currentObserver returns the observer selected with --site, or the configured
+// observer location. Returns nil if neither is set.
+func currentObserver() *satellite.ObserverPosition {
+	if siteName == "" {
+		return config.Observer()
+	}
+
+	observer, err := config.SiteObserver(siteName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return observer
+}
+
+// newStorage opens the catalog storage in the configured data directory and
+// catalog format. Exits on error.
+func newStorage() *satellite.Storage {
+	store, err := satellite.NewStorage(config.DataDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	if err := store.SetCatalogFormat(config.CatalogFormat); err != nil {
+		log.Fatalf("Invalid catalog_format in config: %v", err)
+	}
+	store.SetCompress(config.CompressCatalog)
+	if err := store.SetMaxSnapshots(config.MaxSnapshots); err != nil {
+		log.Fatalf("Invalid max_snapshots in config: %v", err)
+	}
+	return store
+}
+
+// newCatalogStore opens the configured catalog backend, as openCatalogStore.
+// Exits on error.
+func newCatalogStore() storage.CatalogStore {
+	store, err := catalogStoreOpener()
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	return store
+}
+
+// catalogStoreOpener opens the catalog store for newCatalogStore; tests
+// replace it to see how commands use the store.
+var catalogStoreOpener = openCatalogStore
+
+// openCatalogStore opens the configured catalog backend: the catalog file in
+// the configured format, or with catalog_backend: sqlite, a database in the
+// data directory. Snapshots, annotations and feed validators stay in the
+// files of newStorage either way.
+func openCatalogStore() (storage.CatalogStore, error) {
+	switch config.CatalogBackend {
+	case "", storage.BackendFile:
+		store, err := satellite.NewStorage(config.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.SetCatalogFormat(config.CatalogFormat); err != nil {
+			return nil, fmt.Errorf("invalid catalog_format in config: %w", err)
+		}
+		store.SetCompress(config.CompressCatalog)
+		return store, nil
+	case storage.BackendSQLite:
+		if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		store, err := storage.OpenSQLite(filepath.Join(config.DataDir, storage.SQLiteFile))
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("invalid catalog_backend in config: %q (expected %s or %s)",
+			config.CatalogBackend, storage.BackendFile, storage.BackendSQLite)
+	}
+}
+
+// snapshotStore is a catalog store that saves snapshots in the catalog files,
+// for satellite.LoadOrFetchCatalog.
+type snapshotStore struct {
+	storage.CatalogStore
+	files *satellite.Storage
+}
+
+// newSnapshotStore opens the configured catalog backend with snapshots.
+func newSnapshotStore() snapshotStore {
+	return snapshotStore{CatalogStore: newCatalogStore(), files: newStorage()}
+}
+
+// SaveSnapshot saves a snapshot of the catalog in the data directory.
+func (s snapshotStore) SaveSnapshot(catalog *satellite.Catalog) error {
+	return s.files.SaveSnapshot(catalog)
+}
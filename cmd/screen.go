@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	screenThreshold float64
+	screenHours     float64
+)
+
+var screenCmd = &cobra.Command{
+	Use:   "screen NAME_OR_ID",
+	Short: "Screen a satellite for close approaches against the catalog",
+	Long: `Find every catalog object that passes within --threshold km of a satellite
+in the coming hours, with the time and distance of each closest approach.
+Like 'icu conjunction', this is a screening aid limited by TLE accuracy.
+The satellite can be given by NORAD ID, exact name, or a unique partial name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runScreen(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(screenCmd)
+	screenCmd.Flags().Float64Var(&screenThreshold, "threshold", 5, "Report approaches closer than this many km")
+	screenCmd.Flags().Float64Var(&screenHours, "hours", 24, "How many hours ahead to search")
+}
+
+func runScreen(args []string) {
+	if screenThreshold <= 0 {
+		log.Fatalf("Threshold must be positive: %v", screenThreshold)
+	}
+	if screenHours <= 0 {
+		log.Fatalf("Hours must be positive: %v", screenHours)
+	}
+
+	sat, catalog := loadSatelliteAndCatalog(args[0])
+	if sat == nil {
+		return
+	}
+
+	start := time.Now()
+	end := start.Add(time.Duration(screenHours * float64(time.Hour)))
+	events, err := satellite.ScreenConjunctions(sat.TLE, catalog.Satellites, start, end, screenThreshold)
+	if err != nil {
+		log.Fatalf("Error screening conjunctions: %v", err)
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No objects pass within %.1f km of %s (%d) in the next %v hours.\n",
+			screenThreshold, sat.Name, sat.NoradID, screenHours)
+		return
+	}
+
+	fmt.Printf("%d approaches within %.1f km of %s (%d) in the next %v hours\n\n",
+		len(events), screenThreshold, sat.Name, sat.NoradID, screenHours)
+	fmt.Printf("%-23s  %10s  %-8s  %s\n", "Time", "Dist (km)", "NORAD", "Name")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, e := range events {
+		fmt.Printf("%-23s  %10.3f  %-8d  %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05 MST"), e.Distance, e.Satellite.NoradID, e.Satellite.Name)
+	}
+}
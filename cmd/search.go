@@ -3,6 +3,12 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dzeleniak/icu/pkg/satellite"
 	"github.com/spf13/cobra"
@@ -10,11 +16,33 @@ import (
 
 var (
 	searchName    string
+	searchRegex   string
+	searchFuzzy   bool
+	searchMaxDist int
 	searchOwner   string
 	searchType    string
 	searchRegime  string
+	searchOrbType string
+	searchYear    int
+	searchSince   string
+	searchPeriod  string
+	searchPerTol  float64
+	searchTag     string
 	searchLimit   int
 	searchVerbose bool
+	searchStats   bool
+	searchFormat  string
+	searchIDFile  string
+	searchIntlID  string
+	searchNoradLo int
+	searchNoradHi int
+
+	searchInclMin    float64
+	searchInclMax    float64
+	searchPeriodMin  float64
+	searchPeriodMax  float64
+	searchApogeeMax  float64
+	searchPerigeeMin float64
 )
 
 var searchCmd = &cobra.Command{
@@ -23,50 +51,250 @@ var searchCmd = &cobra.Command{
 	Long: `Search the satellite catalog using partial name matching and filters.
 Returns a list of matching satellites with their NORAD IDs.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runSearch()
+		runSearch(cmd)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
 	searchCmd.Flags().StringVarP(&searchName, "name", "n", "", "Search by satellite name (partial match, case-insensitive)")
+	searchCmd.Flags().StringVar(&searchRegex, "regex", "", "Search by satellite name regular expression (case-insensitive)")
+	searchCmd.Flags().BoolVar(&searchFuzzy, "fuzzy", false, "Match --name approximately, tolerating typos and spacing, closest matches first")
+	searchCmd.Flags().IntVar(&searchMaxDist, "max-distance", 0, "Largest edit distance --fuzzy accepts (default one per four letters of --name)")
 	searchCmd.Flags().StringVarP(&searchOwner, "owner", "o", "", "Filter by owner/country code")
 	searchCmd.Flags().StringVarP(&searchType, "type", "t", "", "Filter by object type (PAYLOAD, ROCKET BODY, DEBRIS)")
 	searchCmd.Flags().StringVarP(&searchRegime, "regime", "r", "", "Filter by orbital regime (LEO, MEO, GEO, HEO)")
+	searchCmd.Flags().StringVar(&searchOrbType, "orbit-type", "", "Filter by orbit type (SSO, MOLNIYA, TUNDRA, POLAR, GRAVEYARD, OTHER, UNKNOWN), or 'special' for any of the first five")
+	searchCmd.Flags().StringVar(&searchIntlID, "intl-id", "", "Filter by international designator (partial match, e.g. 1998-067 for one launch)")
+	searchCmd.Flags().IntVar(&searchNoradLo, "norad-min", 0, "Lowest NORAD ID to include")
+	searchCmd.Flags().IntVar(&searchNoradHi, "norad-max", 0, "Highest NORAD ID to include")
+	searchCmd.Flags().IntVar(&searchYear, "launch-year", 0, "Filter by launch year")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Filter to satellites launched on or after a date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchPeriod, "period", "", "Filter by orbital period band center (minutes, or a duration like 12h)")
+	searchCmd.Flags().Float64Var(&searchPerTol, "period-tol", 15.0, "Orbital period band tolerance in minutes")
+	searchCmd.Flags().Float64Var(&searchInclMin, "incl-min", 0, "Minimum inclination in degrees")
+	searchCmd.Flags().Float64Var(&searchInclMax, "incl-max", 0, "Maximum inclination in degrees")
+	searchCmd.Flags().Float64Var(&searchPeriodMin, "period-min", 0, "Minimum orbital period in minutes")
+	searchCmd.Flags().Float64Var(&searchPeriodMax, "period-max", 0, "Maximum orbital period in minutes")
+	searchCmd.Flags().Float64Var(&searchApogeeMax, "apogee-max", 0, "Maximum apogee altitude in km")
+	searchCmd.Flags().Float64Var(&searchPerigeeMin, "perigee-min", 0, "Minimum perigee altitude in km")
+	searchCmd.Flags().StringVar(&searchTag, "tag", "", "Filter to satellites carrying a tag set with 'icu tag'")
 	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 0, "Maximum number of results to display (0 = no limit)")
 	searchCmd.Flags().BoolVarP(&searchVerbose, "verbose", "v", false, "Display verbose satellite information")
+	searchCmd.Flags().BoolVar(&searchStats, "stats", false, "Display aggregate statistics for the matches instead of listing them")
+	searchCmd.Flags().StringVar(&searchFormat, "format", "text", "Output format (text, csv)")
+	searchCmd.Flags().StringVar(&searchIDFile, "id-file", "", "Only consider satellites listed in this file (NORAD IDs or names, one per line)")
 }
 
-func runSearch() {
-	// Load catalog
-	store, err := satellite.NewStorage(config.DataDir)
-	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+func runSearch(cmd *cobra.Command) {
+	format := strings.ToLower(searchFormat)
+	if format != "text" && format != "csv" {
+		log.Fatalf("Unsupported format %q (supported: text, csv)", searchFormat)
 	}
 
-	catalog, err := store.Load()
-	if err != nil {
-		log.Fatalf("Error loading catalog: %v", err)
+	if searchOrbType != "" && !validOrbitType(searchOrbType) {
+		log.Fatalf("Unknown orbit type %q", searchOrbType)
+	}
+	if searchFuzzy && searchName == "" {
+		log.Fatalf("--fuzzy requires --name")
+	}
+	if searchMaxDist < 0 {
+		log.Fatalf("Max distance must not be negative: %d", searchMaxDist)
+	}
+	name, fuzzyName := searchName, ""
+	if searchFuzzy {
+		name, fuzzyName = "", searchName
 	}
 
-	if catalog == nil {
+	var since time.Time
+	if searchSince != "" {
+		t, ok := satellite.ParseSATCATDate(searchSince)
+		if !ok {
+			log.Fatalf("Invalid --since date: %s", searchSince)
+		}
+		since = t
+	}
+
+	var periodCenter float64
+	if searchPeriod != "" {
+		p, err := parsePeriod(searchPeriod)
+		if err != nil {
+			log.Fatalf("Invalid --period: %v", err)
+		}
+		periodCenter = p
+	}
+
+	store := newCatalogStore()
+	if !store.Exists() {
 		fmt.Println("No catalog found. Run 'icu fetch' to download data.")
 		return
 	}
 
+	// Restrict to tagged satellites if requested
+	var taggedIDs []int
+	if searchTag != "" {
+		annotations, err := newStorage().LoadAnnotations()
+		if err != nil {
+			log.Fatalf("Error loading annotations: %v", err)
+		}
+		taggedIDs = annotations.WithTag(searchTag)
+		if len(taggedIDs) == 0 {
+			fmt.Printf("No satellites are tagged %q.\n", searchTag)
+			return
+		}
+	}
+
+	// Restrict to the satellites listed in the id file if given. Names in it
+	// are resolved against the whole catalog, which is then searched as is
+	var catalog *satellite.Catalog
+	if searchIDFile != "" {
+		var err error
+		catalog, err = store.Load()
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+		if catalog == nil {
+			fmt.Println("No catalog found. Run 'icu fetch' to download data.")
+			return
+		}
+		taggedIDs = loadIDFileIDs(searchIDFile, catalog.Satellites, taggedIDs)
+		if len(taggedIDs) == 0 {
+			fmt.Printf("None of the satellites in %s are in the catalog", searchIDFile)
+			if searchTag != "" {
+				fmt.Printf(" with tag %q", searchTag)
+			}
+			fmt.Println(".")
+			return
+		}
+	}
+
+	var nameRegex *regexp.Regexp
+	if searchRegex != "" {
+		re, err := satellite.CompileNameRegex(searchRegex)
+		if err != nil {
+			log.Fatalf("Invalid search: %v", err)
+		}
+		nameRegex = re
+	}
+
 	// Search satellites using library function
-	results := satellite.SearchSatellites(catalog.Satellites, satellite.SearchCriteria{
-		Name:   searchName,
-		Owner:  searchOwner,
-		Type:   searchType,
-		Regime: searchRegime,
-	})
+	criteria := satellite.SearchCriteria{
+		Name:          name,
+		NameRegex:     nameRegex,
+		FuzzyName:     fuzzyName,
+		MaxDistance:   searchMaxDist,
+		Owner:         searchOwner,
+		Type:          searchType,
+		Regime:        searchRegime,
+		OrbitType:     searchOrbType,
+		IntlID:        searchIntlID,
+		LaunchYear:    searchYear,
+		LaunchedSince: since,
+
+		PeriodCenter:    periodCenter,
+		PeriodTolerance: searchPerTol,
+
+		InclinationMin: flagBound(cmd, "incl-min", searchInclMin),
+		InclinationMax: flagBound(cmd, "incl-max", searchInclMax),
+		PeriodMin:      flagBound(cmd, "period-min", searchPeriodMin),
+		PeriodMax:      flagBound(cmd, "period-max", searchPeriodMax),
+		ApogeeMax:      flagBound(cmd, "apogee-max", searchApogeeMax),
+		PerigeeMin:     flagBound(cmd, "perigee-min", searchPerigeeMin),
+
+		NoradIDs: taggedIDs,
+		NoradMin: searchNoradLo,
+		NoradMax: searchNoradHi,
+	}
+	if err := criteria.Validate(); err != nil {
+		log.Fatalf("Invalid search: %v", err)
+	}
+
+	// The store answers the search itself, which a database backend does
+	// without reading the whole catalog
+	var results []*satellite.Satellite
+	if catalog != nil {
+		results = satellite.SearchSatellites(catalog.Satellites, criteria)
+	} else {
+		var err error
+		results, err = store.Query(criteria)
+		if err != nil {
+			log.Fatalf("Error searching catalog: %v", err)
+		}
+	}
+
+	if searchFuzzy {
+		satellite.SortByFuzzyName(results, fuzzyName)
+	}
+
+	if format == "csv" {
+		if searchLimit > 0 && len(results) > searchLimit {
+			results = results[:searchLimit]
+		}
+		if err := satellite.ExportCSV(os.Stdout, results); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
+		}
+		return
+	}
+
+	render(&searchResult{results: results})
+}
+
+// validOrbitType reports whether s names an orbit type or 'special'.
+func validOrbitType(s string) bool {
+	if strings.EqualFold(s, satellite.OrbitTypeSpecial) {
+		return true
+	}
+	for _, t := range satellite.OrbitTypes {
+		if strings.EqualFold(s, string(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagBound returns a pointer to the value of a search bound flag, or nil if
+// the flag was not given, so that an explicit 0 still counts as a bound.
+func flagBound(cmd *cobra.Command, name string, value float64) *float64 {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	return &value
+}
+
+// searchResult is the outcome of a catalog search, rendered as a listing or
+// aggregate statistics, or as the matching satellites (or statistics) in JSON.
+type searchResult struct {
+	results []*satellite.Satellite
+}
+
+func (r *searchResult) jsonValue(_ int, naming satellite.JSONNaming) any {
+	if searchStats {
+		return satellite.SetStatistics(r.results).JSONValue(naming)
+	}
+	results := r.results
+	if searchLimit > 0 && len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+	values := make([]any, len(results))
+	for i, sat := range results {
+		values[i] = sat.JSONValue(naming)
+	}
+	return values
+}
+
+func (r *searchResult) renderText() {
+	results := r.results
 
 	if len(results) == 0 {
 		fmt.Println("No satellites found matching the criteria.")
 		return
 	}
 
+	if searchStats {
+		displaySetStats(satellite.SetStatistics(results))
+		return
+	}
+
 	// Limit results
 	displayCount := len(results)
 	if searchLimit > 0 && displayCount > searchLimit {
@@ -79,7 +307,7 @@ func runSearch() {
 		if searchLimit > 0 && len(results) > searchLimit {
 			fmt.Printf(" (showing first %d)", searchLimit)
 		}
-		fmt.Println("\n")
+		fmt.Print("\n\n")
 
 		displaySatellitesVerbose(results[:displayCount])
 
@@ -92,7 +320,7 @@ func runSearch() {
 		if searchLimit > 0 && len(results) > searchLimit {
 			fmt.Printf(" (showing first %d)", searchLimit)
 		}
-		fmt.Println("\n")
+		fmt.Print("\n\n")
 
 		for i := 0; i < displayCount; i++ {
 			sat := results[i]
@@ -105,3 +333,65 @@ func runSearch() {
 	}
 }
 
+// displaySetStats prints aggregate statistics for a search result set.
+func displaySetStats(stats *satellite.SetStats) {
+	fmt.Printf("Found %d satellites\n\n", stats.Count)
+
+	fmt.Println("Orbit Regimes")
+	fmt.Println("-------------")
+	for _, regime := range regimeOrder {
+		if n := stats.Regimes[regime]; n > 0 {
+			fmt.Printf("%-16s %d\n", string(regime)+":", n)
+		}
+	}
+
+	types := make([]string, 0, len(stats.Types))
+	for t := range stats.Types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	fmt.Println()
+	fmt.Println("Object Types")
+	fmt.Println("------------")
+	for _, t := range types {
+		label := t
+		if label == "" {
+			label = "UNKNOWN"
+		}
+		fmt.Printf("%-16s %d\n", label+":", stats.Types[t])
+	}
+
+	if stats.OrbitCount == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Orbits")
+	fmt.Println("------")
+	fmt.Printf("Known elements:  %d\n", stats.OrbitCount)
+	fmt.Printf("Inclination:     mean %.1f°, range %.1f° - %.1f°\n",
+		stats.MeanInclination, stats.MinInclination, stats.MaxInclination)
+	fmt.Printf("Altitude:        %.0f - %.0f km\n", stats.MinPerigee, stats.MaxApogee)
+	fmt.Printf("Mean period:     %.1f min\n", stats.MeanPeriod)
+}
+
+// parsePeriod parses an orbital period given either as minutes ("718")
+// or as a Go duration ("12h", "95m") and returns it in minutes.
+func parsePeriod(s string) (float64, error) {
+	if minutes, err := strconv.ParseFloat(s, 64); err == nil {
+		if minutes <= 0 {
+			return 0, fmt.Errorf("period must be positive: %s", s)
+		}
+		return minutes, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("expected minutes or a duration like 12h: %s", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("period must be positive: %s", s)
+	}
+	return d.Minutes(), nil
+}
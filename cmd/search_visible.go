@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,10 +18,20 @@ var (
 	visibleOwner        string
 	visibleType         string
 	visibleRegime       string
-	visibleMinElevation float64
+	visibleMinElevation string
 	visibleMaxElevation float64
 	visibleLimit        int
 	visibleVerbose      bool
+	visibleGlyph        bool
+	visibleWatchlist    bool
+	visibleSunlit       bool
+	visibleDarkOnly     bool
+	visibleSunElevation float64
+	visibleIDFile       string
+	visibleIntlID       string
+	visibleNoradMin     int
+	visibleNoradMax     int
+	visibleMaxTLEAge    float64
 )
 
 var visibleCmd = &cobra.Command{
@@ -28,7 +41,7 @@ var visibleCmd = &cobra.Command{
 Propagates satellites to current time and checks if they are visible (above minimum elevation).
 Supports all standard search filters (name, owner, type, regime) plus elevation constraints.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runSearchVisible()
+		runSearchVisible(cmd)
 	},
 }
 
@@ -38,31 +51,47 @@ func init() {
 	visibleCmd.Flags().StringVarP(&visibleOwner, "owner", "o", "", "Filter by owner/country code")
 	visibleCmd.Flags().StringVarP(&visibleType, "type", "t", "", "Filter by object type (PAYLOAD, ROCKET BODY, DEBRIS)")
 	visibleCmd.Flags().StringVarP(&visibleRegime, "regime", "r", "", "Filter by orbital regime (LEO, MEO, GEO, HEO)")
-	visibleCmd.Flags().Float64Var(&visibleMinElevation, "min-elevation", 10.0, "Minimum elevation angle in degrees")
+	visibleCmd.Flags().StringVar(&visibleIntlID, "intl-id", "", "Filter by international designator (partial match, e.g. 1998-067 for one launch)")
+	visibleCmd.Flags().IntVar(&visibleNoradMin, "norad-min", 0, "Lowest NORAD ID to include")
+	visibleCmd.Flags().IntVar(&visibleNoradMax, "norad-max", 0, "Highest NORAD ID to include")
+	visibleCmd.Flags().StringVar(&visibleMinElevation, "min-elevation", "", "Minimum elevation angle in degrees, or 'horizon' for 0 (default from config)")
 	visibleCmd.Flags().Float64Var(&visibleMaxElevation, "max-elevation", 90.0, "Maximum elevation angle in degrees")
 	visibleCmd.Flags().IntVarP(&visibleLimit, "limit", "l", 0, "Maximum number of results to display (0 = no limit)")
 	visibleCmd.Flags().BoolVarP(&visibleVerbose, "verbose", "v", false, "Display verbose satellite information")
+	visibleCmd.Flags().BoolVarP(&visibleWatchlist, "watchlist", "w", false, "Only consider satellites on the watchlist")
+	visibleCmd.Flags().Float64Var(&visibleMaxTLEAge, "max-tle-age", 0, "Skip satellites whose TLE epoch is more than this many days old, 0 = no limit (default from config)")
+	visibleCmd.Flags().StringVar(&visibleIDFile, "id-file", "", "Only consider satellites listed in this file (NORAD IDs or names, one per line)")
+	visibleCmd.Flags().BoolVarP(&visibleGlyph, "glyph", "g", false, "Show a direction arrow column in the listing")
+	visibleCmd.Flags().BoolVar(&visibleSunlit, "sunlit", false, "Only satellites in sunlight (outside the Earth's umbra)")
+	visibleCmd.Flags().BoolVar(&visibleDarkOnly, "dark-only", false, "Only sunlit satellites while the observer's sky is dark (naked-eye visible)")
+	visibleCmd.Flags().Float64Var(&visibleSunElevation, "sun-elevation", -6, "Sun elevation in degrees below which the sky counts as dark for --dark-only (-6 civil, -12 nautical, -18 astronomical)")
 }
 
-func runSearchVisible() {
+func runSearchVisible(cmd *cobra.Command) {
+	minElevation, err := resolveMinElevation(visibleMinElevation)
+	if err != nil {
+		log.Fatalf("Invalid --min-elevation: %v", err)
+	}
+
 	// Check observer configuration
-	if config.ObserverLatitude == 0.0 && config.ObserverLongitude == 0.0 {
+	observer := currentObserver()
+	if observer == nil {
 		fmt.Println("Observer location not configured.")
 		fmt.Println("Please set observer_latitude, observer_longitude, and observer_altitude in ~/.icu/config.yaml")
 		return
 	}
 
-	observer := &satellite.ObserverPosition{
-		Latitude:  config.ObserverLatitude,
-		Longitude: config.ObserverLongitude,
-		Altitude:  config.ObserverAltitude,
+	var ids []int
+	if visibleWatchlist {
+		if len(config.Watchlist) == 0 {
+			fmt.Println("Watchlist is empty. Add satellites with 'icu watchlist add NORAD_ID'.")
+			return
+		}
+		ids = config.Watchlist
 	}
 
 	// Load catalog
-	store, err := satellite.NewStorage(config.DataDir)
-	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
-	}
+	store := newCatalogStore()
 
 	catalog, err := store.Load()
 	if err != nil {
@@ -74,32 +103,115 @@ func runSearchVisible() {
 		return
 	}
 
+	// Restrict to the satellites listed in the id file if given
+	if visibleIDFile != "" {
+		ids = loadIDFileIDs(visibleIDFile, catalog.Satellites, ids)
+		if len(ids) == 0 {
+			fmt.Printf("None of the satellites in %s are in the catalog", visibleIDFile)
+			if visibleWatchlist {
+				fmt.Print(" and on the watchlist")
+			}
+			fmt.Println(".")
+			return
+		}
+	}
+
 	// Use library function to find visible satellites
-	fmt.Printf("Searching for visible satellites...\n")
+	if !jsonOutput {
+		fmt.Printf("Searching for visible satellites...\n")
+	}
 	now := time.Now()
 
-	visible, err := satellite.FindVisibleSatellites(
-		catalog.Satellites,
-		observer,
-		now,
-		satellite.VisibilityCriteria{
-			SearchCriteria: satellite.SearchCriteria{
-				Name:   visibleName,
-				Owner:  visibleOwner,
-				Type:   visibleType,
-				Regime: visibleRegime,
-			},
-			MinElevation: visibleMinElevation,
-			MaxElevation: visibleMaxElevation,
+	criteria := satellite.VisibilityCriteria{
+		SearchCriteria: satellite.SearchCriteria{
+			Name:   visibleName,
+			Owner:  visibleOwner,
+			Type:   visibleType,
+			Regime: visibleRegime,
+			IntlID: visibleIntlID,
+
+			NoradIDs: ids,
+			NoradMin: visibleNoradMin,
+			NoradMax: visibleNoradMax,
 		},
-	)
+		MinElevation: minElevation,
+		MaxElevation: visibleMaxElevation,
+		SunlitOnly:   visibleSunlit || visibleDarkOnly,
+
+		DarkOnly:         visibleDarkOnly,
+		DarkSunElevation: visibleSunElevation,
+		Workers:          config.PropagationWorkers,
+		// A site named with --site is an explicit location, even at 0°, 0°
+		AllowNullIsland: siteName != "",
+	}
+
+	// Skip candidates whose elements are too old for SGP4 to place them,
+	// counting only those the search filters would have propagated
+	candidates := catalog.Satellites
+	maxTLEAge := config.MaxTLEAge
+	if cmd.Flags().Changed("max-tle-age") {
+		maxTLEAge = visibleMaxTLEAge
+	}
+	if maxTLEAge > 0 {
+		var stale int
+		candidates = satellite.SearchSatellites(candidates, criteria.SearchCriteria)
+		candidates, stale = satellite.ExcludeStaleTLEs(candidates, now, time.Duration(maxTLEAge*24*float64(time.Hour)))
+		if stale > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d satellites with TLEs more than %g days old (see --max-tle-age)\n", stale, maxTLEAge)
+		}
+	}
+
+	visible, err := satellite.FindVisibleSatellites(candidates, observer, now, criteria, newProgressBar("Propagating"))
+	if errors.Is(err, satellite.ErrObserverUnset) {
+		fmt.Println("Observer location not configured.")
+		fmt.Println("Please set observer_latitude, observer_longitude, and observer_altitude in ~/.icu/config.yaml")
+		return
+	}
 	if err != nil {
 		log.Fatalf("Error finding visible satellites: %v", err)
 	}
 
+	render(&visibleResult{
+		visible:      visible,
+		observer:     observer,
+		now:          now,
+		minElevation: minElevation,
+	})
+}
+
+// visibleResult is the outcome of a visibility search, rendered as a table
+// or as a JSON array of satellites with their observation angles.
+type visibleResult struct {
+	visible      []*satellite.VisibleSatellite
+	observer     *satellite.ObserverPosition
+	now          time.Time
+	minElevation float64
+}
+
+func (r *visibleResult) jsonValue(decimals int, naming satellite.JSONNaming) any {
+	visible := r.visible
+	if visibleLimit > 0 && len(visible) > visibleLimit {
+		visible = visible[:visibleLimit]
+	}
+
+	rounded := make([]any, len(visible))
+	for i, v := range visible {
+		rounded[i] = (&satellite.VisibleSatellite{Satellite: v.Satellite, Angles: v.Angles.Rounded(decimals)}).JSONValue(naming)
+	}
+	return rounded
+}
+
+func (r *visibleResult) renderText() {
+	visible, observer, now := r.visible, r.observer, r.now
+
 	if len(visible) == 0 {
+		if visibleDarkOnly && !satellite.IsDark(observer, now, visibleSunElevation) {
+			fmt.Printf("\nNo satellites can be seen by eye: the Sun is at %.1f°, above %.1f°.\n",
+				satellite.SunElevation(observer, now), visibleSunElevation)
+			return
+		}
 		fmt.Printf("\nNo satellites currently visible (elevation between %.1f° and %.1f°).\n",
-			visibleMinElevation, visibleMaxElevation)
+			r.minElevation, visibleMaxElevation)
 		return
 	}
 
@@ -120,7 +232,7 @@ func runSearchVisible() {
 	if visibleVerbose {
 		displayVisibleSatellitesVerbose(visible[:displayCount])
 	} else {
-		displayVisibleSatellitesList(visible[:displayCount])
+		displayVisibleSatellitesList(visible[:displayCount], visibleGlyph)
 	}
 
 	if visibleLimit > 0 && len(visible) > visibleLimit {
@@ -128,11 +240,26 @@ func runSearchVisible() {
 	}
 }
 
-func displayVisibleSatellitesList(visible []*satellite.VisibleSatellite) {
-	fmt.Printf("%-8s  %-40s  %-7s  %-7s  %-11s\n", "NORAD", "Name", "El (°)", "Az (°)", "Range (km)")
-	fmt.Println(strings.Repeat("-", 80))
+func displayVisibleSatellitesList(visible []*satellite.VisibleSatellite, showGlyph bool) {
+	if showGlyph {
+		fmt.Printf("%-8s  %-40s  %-7s  %-7s  %-3s  %-11s\n", "NORAD", "Name", "El (°)", "Az (°)", "Dir", "Range (km)")
+		fmt.Println(strings.Repeat("-", 85))
+	} else {
+		fmt.Printf("%-8s  %-40s  %-7s  %-7s  %-11s\n", "NORAD", "Name", "El (°)", "Az (°)", "Range (km)")
+		fmt.Println(strings.Repeat("-", 80))
+	}
 
 	for _, v := range visible {
+		if showGlyph {
+			fmt.Printf("%-8d  %-40s  %7.2f  %7.2f  %-3s  %11.0f\n",
+				v.Satellite.NoradID,
+				v.Satellite.Name,
+				v.Angles.Elevation,
+				v.Angles.Azimuth,
+				satellite.DirectionGlyph(v.Angles),
+				v.Angles.Range)
+			continue
+		}
 		fmt.Printf("%-8d  %-40s  %7.2f  %7.2f  %11.0f\n",
 			v.Satellite.NoradID,
 			v.Satellite.Name,
@@ -160,6 +287,12 @@ func displayVisibleSatellitesVerbose(visible []*satellite.VisibleSatellite) {
 		fmt.Printf("  Azimuth:      %.2f°\n", v.Angles.Azimuth)
 		fmt.Printf("  Range:        %.0f km\n", v.Angles.Range)
 		fmt.Printf("  Range Rate:   %.2f km/s\n", v.Angles.RangeRate)
+		radar := satellite.RadarDetectability(sat, v.Angles)
+		fmt.Printf("  Radar:        %s (%+.1f dB relative to 1 m² at 1000 km", radar.Level, radar.RelativeSNR)
+		if !radar.RCSKnown {
+			fmt.Print(", RCS unknown")
+		}
+		fmt.Println(")")
 
 		fmt.Printf("\nOrbital Parameters:\n")
 		fmt.Printf("  Period:       %.2f minutes\n", sat.Period)
@@ -168,3 +301,25 @@ func displayVisibleSatellitesVerbose(visible []*satellite.VisibleSatellite) {
 		fmt.Printf("  Perigee:      %.0f km\n", sat.Perigee)
 	}
 }
+
+// resolveMinElevation parses a --min-elevation value in degrees.
+// "horizon" is accepted as an alias for 0°, and an empty value falls back
+// to the configured default_min_elevation.
+func resolveMinElevation(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return config.DefaultMinElevation, nil
+	}
+	if strings.EqualFold(s, "horizon") {
+		return 0.0, nil
+	}
+
+	elevation, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected degrees or 'horizon': %s", s)
+	}
+	if elevation < -90 || elevation > 90 {
+		return 0, fmt.Errorf("elevation must be between -90 and 90 degrees: %s", s)
+	}
+	return elevation, nil
+}
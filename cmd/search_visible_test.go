@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestSearchVisibleSkipsStaleTLEs(t *testing.T) {
+	prevMax := visibleNoradMax
+	t.Cleanup(func() {
+		visibleNoradMax = prevMax
+		flag := visibleCmd.Flags().Lookup("max-tle-age")
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
+	// The search filters leave out 30002, whose TLE is also stale
+	visibleNoradMax = 30001
+
+	now := time.Now().UTC()
+	satellites := make([]*satellite.Satellite, 0, 3)
+	for i, age := range []time.Duration{24 * time.Hour, 60 * 24 * time.Hour, 90 * 24 * time.Hour} {
+		tle := leoTLE(30000+i, now.Add(-age))
+		satellites = append(satellites, &satellite.Satellite{NoradID: 30000 + i, Name: "LEO", TLE: &tle, TLEEpoch: now.Add(-age)})
+	}
+
+	tests := []struct {
+		name   string
+		yaml   string
+		flag   string
+		report string
+	}{
+		{"default", "", "", ""},
+		{"config", "max_tle_age: 30\n", "", "Skipped 1 satellites with TLEs more than 30 days old"},
+		{"flag", "", "45", "Skipped 1 satellites with TLEs more than 45 days old"},
+		{"flag overrides config", "max_tle_age: 30\n", "0", ""},
+		{"fresh", "max_tle_age: 30\n", "120", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTestConfig(t, "observer_latitude: 40\n"+tt.yaml)
+			store, err := satellite.NewStorage(cfg.DataDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Save(&satellite.Catalog{Satellites: satellites, FetchedAt: now}); err != nil {
+				t.Fatal(err)
+			}
+
+			flag := visibleCmd.Flags().Lookup("max-tle-age")
+			_ = flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+			if tt.flag != "" {
+				if err := visibleCmd.Flags().Set("max-tle-age", tt.flag); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stderr string
+			captureStdout(t, func() {
+				stderr = captureStderr(t, func() { runSearchVisible(visibleCmd) })
+			})
+			if tt.report == "" && strings.Contains(stderr, "Skipped") {
+				t.Errorf("reported skipped satellites: %q", stderr)
+			}
+			if tt.report != "" && !strings.Contains(stderr, tt.report) {
+				t.Errorf("stderr %q does not report %q", stderr, tt.report)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptContext returns a context that is canceled when the process receives
+// SIGINT (Ctrl+C) or SIGTERM, for long-running commands to stop cleanly and
+// flush their output. The returned stop function restores default signal
+// handling and must be called when the command finishes.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptContext(t *testing.T) {
+	for _, sig := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM} {
+		ctx, stop := interruptContext()
+
+		select {
+		case <-ctx.Done():
+			t.Fatalf("context canceled before %v was sent", sig)
+		default:
+		}
+
+		if err := syscall.Kill(os.Getpid(), sig); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Errorf("context not canceled by %v", sig)
+		}
+		stop()
+	}
+
+	// stop alone cancels the context too, releasing its resources
+	ctx, stop := interruptContext()
+	stop()
+	if ctx.Err() == nil {
+		t.Error("context still live after stop")
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/dzeleniak/icu/pkg/satellite"
@@ -21,15 +22,44 @@ the data (unless auto_fetch is disabled in config).`,
 	},
 }
 
+var (
+	statsCheck      bool
+	statsShell      float64
+	statsShellWidth float64
+)
+
+// regimeOrder is the display order for orbit regime breakdowns.
+var regimeOrder = []satellite.OrbitRegime{
+	satellite.RegimeLEO, satellite.RegimeMEO, satellite.RegimeGEO,
+	satellite.RegimeHEO, satellite.RegimeUnknown,
+}
+
 func init() {
 	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsCheck, "check", false, "Propagate every satellite and list those that fail")
+	statsCmd.Flags().Float64Var(&statsShell, "shell", 0, "Count objects whose orbits cross the altitude shell centered here, in km")
+	statsCmd.Flags().Float64Var(&statsShellWidth, "shell-width", 50, "Thickness of the --shell altitude shell in km")
 }
 
 func runStats() {
+	if statsShell > 0 && statsShellWidth <= 0 {
+		log.Fatalf("Shell width must be positive: %v", statsShellWidth)
+	}
+
 	// Create storage
-	store, err := satellite.NewStorage(config.DataDir)
-	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+	store := newSnapshotStore()
+
+	// JSON output can't interleave fetch progress, so fetch quietly
+	if jsonOutput {
+		catalog, _, err := satellite.LoadOrFetchCatalog(store, newAPIClient(), config)
+		if err != nil {
+			log.Fatalf("Error loading catalog: %v", err)
+		}
+		if catalog == nil {
+			log.Fatal("No catalog found. Run 'icu fetch' to download data.")
+		}
+		render(newStatsResult(catalog))
+		return
 	}
 
 	// Load catalog
@@ -41,7 +71,7 @@ func runStats() {
 	// If no catalog exists and auto_fetch is enabled, fetch it
 	if catalog == nil {
 		if config.AutoFetch {
-			fmt.Println("No catalog found. Fetching data...\n")
+			fmt.Print("No catalog found. Fetching data...\n\n")
 			runFetch()
 			return
 		} else {
@@ -60,6 +90,134 @@ func runStats() {
 		return
 	}
 
+	render(newStatsResult(catalog))
+}
+
+// statsResult holds catalog statistics, rendered as sections of text or as
+// one JSON object.
+type statsResult struct {
+	catalog   *satellite.Catalog
+	age       time.Duration
+	freshness *satellite.FreshnessReport
+	regimes   map[satellite.OrbitRegime]int
+	shell     []*satellite.Satellite  // with --shell
+	failures  []satellite.FailureInfo // with --check
+}
+
+// newStatsResult computes the statistics selected by flags.
+func newStatsResult(catalog *satellite.Catalog) *statsResult {
+	r := &statsResult{
+		catalog:   catalog,
+		age:       time.Since(catalog.FetchedAt),
+		freshness: satellite.CatalogFreshness(catalog),
+		regimes:   satellite.RegimeDistribution(catalog),
+	}
+	if statsShell > 0 {
+		r.shell = satellite.ShellOccupancy(catalog.Satellites, statsShell, statsShellWidth)
+	}
+	if statsCheck {
+		r.failures = satellite.PropagationFailures(catalog, time.Now(), newProgressBar("Checking"))
+	}
+	return r
+}
+
+func (r *statsResult) jsonValue(_ int, naming satellite.JSONNaming) any {
+	snake := naming == satellite.SnakeCase
+
+	// Each object with camelCase keys has a twin with snake_case keys
+	type epochAge struct {
+		Newest float64 `json:"newest"`
+		Median float64 `json:"median"`
+		Oldest float64 `json:"oldest"`
+	}
+	type shell struct {
+		MinAltitude float64        `json:"minAltitude"`
+		MaxAltitude float64        `json:"maxAltitude"`
+		Objects     int            `json:"objects"`
+		Types       map[string]int `json:"types"`
+	}
+	type shellSnake struct {
+		MinAltitude float64        `json:"min_altitude"`
+		MaxAltitude float64        `json:"max_altitude"`
+		Objects     int            `json:"objects"`
+		Types       map[string]int `json:"types"`
+	}
+	type failure struct {
+		NoradID  int    `json:"noradId"`
+		Name     string `json:"name"`
+		Category string `json:"category"`
+		Error    string `json:"error"`
+	}
+	type failureSnake struct {
+		NoradID  int    `json:"norad_id"`
+		Name     string `json:"name"`
+		Category string `json:"category"`
+		Error    string `json:"error"`
+	}
+	type stats struct {
+		Satellites  int                           `json:"satellites"`
+		DroppedTLEs int                           `json:"droppedTles"`
+		FetchedAt   time.Time                     `json:"fetchedAt"`
+		CatalogAge  float64                       `json:"catalogAgeHours"`
+		TLEEpochAge *epochAge                     `json:"tleEpochAgeHours,omitempty"`
+		Regimes     map[satellite.OrbitRegime]int `json:"regimes"`
+		Shell       any                           `json:"shell,omitempty"`
+		Failures    any                           `json:"propagationFailures,omitempty"` // with --check
+	}
+	type statsSnake struct {
+		Satellites  int                           `json:"satellites"`
+		DroppedTLEs int                           `json:"dropped_tles"`
+		FetchedAt   time.Time                     `json:"fetched_at"`
+		CatalogAge  float64                       `json:"catalog_age_hours"`
+		TLEEpochAge *epochAge                     `json:"tle_epoch_age_hours,omitempty"`
+		Regimes     map[satellite.OrbitRegime]int `json:"regimes"`
+		Shell       any                           `json:"shell,omitempty"`
+		Failures    any                           `json:"propagation_failures,omitempty"`
+	}
+
+	out := stats{
+		Satellites:  len(r.catalog.Satellites),
+		DroppedTLEs: r.catalog.DroppedTLEs,
+		FetchedAt:   r.catalog.FetchedAt,
+		CatalogAge:  r.age.Hours(),
+		Regimes:     r.regimes,
+	}
+	if r.freshness.Count > 0 {
+		out.TLEEpochAge = &epochAge{r.freshness.Newest.Hours(), r.freshness.Median.Hours(), r.freshness.Oldest.Hours()}
+	}
+	if statsShell > 0 {
+		s := shell{
+			MinAltitude: statsShell - statsShellWidth/2,
+			MaxAltitude: statsShell + statsShellWidth/2,
+			Objects:     len(r.shell),
+			Types:       satellite.SetStatistics(r.shell).Types,
+		}
+		out.Shell = s
+		if snake {
+			out.Shell = shellSnake(s)
+		}
+	}
+	if statsCheck {
+		failures := make([]any, 0, len(r.failures))
+		for _, f := range r.failures {
+			info := failure{f.NoradID, f.Name, f.Category, f.Err.Error()}
+			if snake {
+				failures = append(failures, failureSnake(info))
+			} else {
+				failures = append(failures, info)
+			}
+		}
+		out.Failures = failures
+	}
+	if snake {
+		return statsSnake(out)
+	}
+	return out
+}
+
+func (r *statsResult) renderText() {
+	catalog := r.catalog
+
 	// Display statistics
 	fmt.Println("Catalog Statistics")
 	fmt.Println("==================")
@@ -67,14 +225,66 @@ func runStats() {
 	fmt.Printf("Last fetched:    %s\n", catalog.FetchedAt.Format("2006-01-02 15:04:05 MST"))
 
 	// Show catalog age and staleness info
-	age := time.Since(catalog.FetchedAt)
-	fmt.Printf("Catalog age:     %v\n", age.Round(time.Minute))
+	fmt.Printf("Catalog age:     %v\n", r.age.Round(time.Minute))
 
 	if config.MaxCatalogAge > 0 {
 		maxAge := time.Duration(config.MaxCatalogAge) * time.Hour
-		remaining := maxAge - age
+		remaining := maxAge - r.age
 		if remaining > 0 {
 			fmt.Printf("Refresh in:      %v\n", remaining.Round(time.Minute))
 		}
 	}
+
+	// Show TLE epoch freshness
+	if freshness := r.freshness; freshness.Count > 0 {
+		fmt.Println()
+		fmt.Println("TLE Epoch Age")
+		fmt.Println("-------------")
+		fmt.Printf("Newest:          %v\n", freshness.Newest.Round(time.Minute))
+		fmt.Printf("Median:          %v\n", freshness.Median.Round(time.Minute))
+		fmt.Printf("Oldest:          %v\n", freshness.Oldest.Round(time.Minute))
+	}
+
+	// Show orbit regime breakdown
+	fmt.Println()
+	fmt.Println("Orbit Regimes")
+	fmt.Println("-------------")
+	for _, regime := range regimeOrder {
+		fmt.Printf("%-16s %d\n", string(regime)+":", r.regimes[regime])
+	}
+
+	// Optionally show how crowded an altitude shell is
+	if statsShell > 0 {
+		stats := satellite.SetStatistics(r.shell)
+		types := make([]string, 0, len(stats.Types))
+		for t := range stats.Types {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		fmt.Println()
+		fmt.Printf("Shell %.0f-%.0f km\n", statsShell-statsShellWidth/2, statsShell+statsShellWidth/2)
+		fmt.Println("-----------------")
+		fmt.Printf("Objects:         %d\n", len(r.shell))
+		for _, t := range types {
+			label := t
+			if label == "" {
+				label = "UNKNOWN"
+			}
+			fmt.Printf("  %-14s %d\n", label+":", stats.Types[t])
+		}
+	}
+
+	// Optionally list satellites that fail to propagate
+	if statsCheck {
+		fmt.Println()
+		fmt.Println("Propagation Failures")
+		fmt.Println("--------------------")
+		if len(r.failures) == 0 {
+			fmt.Println("None")
+		}
+		for _, f := range r.failures {
+			fmt.Printf("%-8d  %-8s  %-24s  %v\n", f.NoradID, f.Category, f.Name, f.Err)
+		}
+	}
 }
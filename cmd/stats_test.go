@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+func TestStatsJSONRoundTrip(t *testing.T) {
+	loadTestConfig(t, "")
+	withJSONFlags(t, true, "")
+	prevCheck, prevShell, prevWidth := statsCheck, statsShell, statsShellWidth
+	t.Cleanup(func() { statsCheck, statsShell, statsShellWidth = prevCheck, prevShell, prevWidth })
+	statsCheck, statsShell, statsShellWidth = true, 420, 50
+
+	iss := testISS()
+	iss.ObjectType, iss.Period, iss.Perigee, iss.Apogee = "PAYLOAD", 92.9, 415, 425
+	catalog := &satellite.Catalog{
+		Satellites:  []*satellite.Satellite{iss, {NoradID: 99999, Name: "NO TLE"}},
+		FetchedAt:   issEpoch.Add(48 * time.Hour),
+		DroppedTLEs: 3,
+	}
+	result := &statsResult{
+		catalog:   catalog,
+		age:       36 * time.Hour,
+		freshness: satellite.CatalogFreshness(catalog),
+		regimes:   satellite.RegimeDistribution(catalog),
+		shell:     satellite.ShellOccupancy(catalog.Satellites, statsShell, statsShellWidth),
+		failures: []satellite.FailureInfo{
+			{NoradID: 12345, Name: "DEBRIS", Category: satellite.FailureDecayed, Err: errors.New("decayed")},
+		},
+	}
+
+	var got struct {
+		Satellites  int       `json:"satellites"`
+		DroppedTLEs int       `json:"droppedTles"`
+		FetchedAt   time.Time `json:"fetchedAt"`
+		CatalogAge  float64   `json:"catalogAgeHours"`
+		TLEEpochAge *struct {
+			Newest, Median, Oldest float64
+		} `json:"tleEpochAgeHours"`
+		Regimes map[satellite.OrbitRegime]int `json:"regimes"`
+		Shell   *struct {
+			MinAltitude float64        `json:"minAltitude"`
+			MaxAltitude float64        `json:"maxAltitude"`
+			Objects     int            `json:"objects"`
+			Types       map[string]int `json:"types"`
+		} `json:"shell"`
+		Failures []struct {
+			NoradID  int    `json:"noradId"`
+			Name     string `json:"name"`
+			Category string `json:"category"`
+			Error    string `json:"error"`
+		} `json:"propagationFailures"`
+	}
+	out := captureStdout(t, func() { render(result) })
+	decoder := json.NewDecoder(strings.NewReader(out))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("stats JSON does not decode into its structs: %v\n%s", err, out)
+	}
+
+	if got.Satellites != 2 || got.DroppedTLEs != 3 || !got.FetchedAt.Equal(catalog.FetchedAt) || got.CatalogAge != 36 {
+		t.Errorf("decoded %d satellites, %d dropped, fetched %v, age %v", got.Satellites, got.DroppedTLEs, got.FetchedAt, got.CatalogAge)
+	}
+	if got.TLEEpochAge == nil || got.TLEEpochAge.Newest != result.freshness.Newest.Hours() {
+		t.Errorf("TLE epoch ages = %+v, want newest %v", got.TLEEpochAge, result.freshness.Newest.Hours())
+	}
+	if got.Regimes[satellite.RegimeLEO] != 1 || got.Regimes[satellite.RegimeUnknown] != 1 {
+		t.Errorf("regimes = %v, want one LEO and one unknown", got.Regimes)
+	}
+	if got.Shell == nil || got.Shell.MinAltitude != 395 || got.Shell.MaxAltitude != 445 ||
+		got.Shell.Objects != 1 || got.Shell.Types["PAYLOAD"] != 1 {
+		t.Errorf("shell = %+v, want the ISS in 395-445 km", got.Shell)
+	}
+	if len(got.Failures) != 1 || got.Failures[0].NoradID != 12345 || got.Failures[0].Category != "decayed" || got.Failures[0].Error != "decayed" {
+		t.Errorf("failures = %+v", got.Failures)
+	}
+
+	// Without --shell and --check those sections are left out
+	statsCheck, statsShell = false, 0
+	out = captureStdout(t, func() { render(result) })
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out), &sections); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"shell", "propagationFailures"} {
+		if _, ok := sections[key]; ok {
+			t.Errorf("%s rendered without its flag", key)
+		}
+	}
+}
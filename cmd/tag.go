@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage your own tags and notes on satellites",
+	Long: `Attach tags and notes to satellites by NORAD ID. Annotations are stored in
+annotations.json in the data directory, separate from the fetched catalog, so
+re-fetching does not remove them. Use 'icu search --tag' to filter by tag.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagShow(nil)
+	},
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add NORAD_ID TAG...",
+	Short: "Add tags to a satellite",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagUpdate(args[0], func(a satellite.Annotations, id int) {
+			a.AddTags(id, args[1:]...)
+		})
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove NORAD_ID TAG...",
+	Short: "Remove tags from a satellite",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagUpdate(args[0], func(a satellite.Annotations, id int) {
+			a.RemoveTags(id, args[1:]...)
+		})
+	},
+}
+
+var tagNoteCmd = &cobra.Command{
+	Use:   "note NORAD_ID [TEXT]",
+	Short: "Set a satellite's note (omit TEXT to clear it)",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagUpdate(args[0], func(a satellite.Annotations, id int) {
+			a.SetNote(id, strings.Join(args[1:], " "))
+		})
+	},
+}
+
+var tagShowCmd = &cobra.Command{
+	Use:   "show [NORAD_ID...]",
+	Short: "Show tags and notes (all annotated satellites if none given)",
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagShow(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagNoteCmd)
+	tagCmd.AddCommand(tagShowCmd)
+}
+
+// loadAnnotations opens storage and loads the user annotations
+func loadAnnotations() (*satellite.Storage, satellite.Annotations) {
+	store := newStorage()
+
+	annotations, err := store.LoadAnnotations()
+	if err != nil {
+		log.Fatalf("Error loading annotations: %v", err)
+	}
+
+	return store, annotations
+}
+
+// runTagUpdate applies update to the annotations of one satellite and saves them
+func runTagUpdate(arg string, update func(satellite.Annotations, int)) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		log.Fatalf("Invalid NORAD ID: %s", arg)
+	}
+
+	store, annotations := loadAnnotations()
+	update(annotations, id)
+
+	if err := store.SaveAnnotations(annotations); err != nil {
+		log.Fatalf("Error saving annotations: %v", err)
+	}
+
+	printAnnotation(id, annotations[id])
+}
+
+func runTagShow(args []string) {
+	_, annotations := loadAnnotations()
+
+	var ids []int
+	if len(args) > 0 {
+		parsed, err := parseNoradIDs(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ids = parsed
+	} else {
+		for id := range annotations {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+	}
+
+	if len(ids) == 0 {
+		fmt.Println("No annotations. Add one with 'icu tag add NORAD_ID TAG'.")
+		return
+	}
+
+	for _, id := range ids {
+		printAnnotation(id, annotations[id])
+	}
+}
+
+// printAnnotation prints one satellite's tags and note on a single line
+func printAnnotation(id int, ann *satellite.Annotation) {
+	if ann == nil {
+		fmt.Printf("%-8d  (no tags)\n", id)
+		return
+	}
+
+	line := fmt.Sprintf("%-8d  [%s]", id, strings.Join(ann.Tags, ", "))
+	if ann.Note != "" {
+		line += "  " + ann.Note
+	}
+	fmt.Println(line)
+}
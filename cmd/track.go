@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trackFormat    string
+	trackOutput    string
+	trackDuration  time.Duration
+	trackStep      time.Duration
+	trackFootprint bool
+)
+
+// footprintPoints is the number of vertices of an exported footprint
+const footprintPoints = 72
+
+var trackCmd = &cobra.Command{
+	Use:   "track NAME_OR_ID",
+	Short: "Write a satellite's ground track for web maps",
+	Long: `Write the ground track of a satellite from now over --duration to stdout or
+a file, either as a GeoJSON Feature split at the antimeridian or as a KML
+document for Google Earth with the path drawn at the satellite's altitude.
+With --footprint the output also has the area from which the satellite is
+above the horizon at the start of the track.
+The satellite can be given by NORAD ID, exact name, or a unique partial name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTrack(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trackCmd)
+	trackCmd.Flags().StringVar(&trackFormat, "format", "geojson", "Output format (geojson, kml)")
+	trackCmd.Flags().StringVarP(&trackOutput, "output", "o", "", "File to write (default stdout)")
+	trackCmd.Flags().DurationVar(&trackDuration, "duration", 90*time.Minute, "Length of the track")
+	trackCmd.Flags().DurationVar(&trackStep, "step", 30*time.Second, "Time between track points")
+	trackCmd.Flags().BoolVar(&trackFootprint, "footprint", false, "Include the satellite's footprint at the start of the track")
+}
+
+func runTrack(args []string) {
+	format := strings.ToLower(trackFormat)
+	if format != "geojson" && format != "kml" {
+		log.Fatalf("Unsupported format %q (supported: geojson, kml)", trackFormat)
+	}
+	if trackDuration <= 0 {
+		log.Fatalf("Duration must be positive: %v", trackDuration)
+	}
+	if trackStep <= 0 {
+		log.Fatalf("Step must be positive: %v", trackStep)
+	}
+
+	sat := loadSatellite(args[0])
+	if sat == nil {
+		return
+	}
+
+	// Name the feature after the catalog entry rather than the TLE name line
+	tle := *sat.TLE
+	tle.Name = sat.Name
+
+	start := time.Now().UTC().Truncate(time.Second)
+	end := start.Add(trackDuration)
+	var data []byte
+	var err error
+	switch {
+	case format == "kml" && trackFootprint:
+		data, err = satellite.GroundTrackFootprintKML(&tle, start, end, trackStep, footprintPoints)
+	case format == "kml":
+		data, err = satellite.GroundTrackKML(&tle, start, end, trackStep)
+	case trackFootprint:
+		data, err = satellite.GroundTrackFootprintGeoJSON(&tle, start, end, trackStep, footprintPoints)
+	default:
+		data, err = satellite.GroundTrackGeoJSON(&tle, start, end, trackStep)
+	}
+	if err != nil {
+		log.Fatalf("Error computing ground track: %v", err)
+	}
+	data = append(data, '\n')
+
+	if trackOutput == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(trackOutput, data, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", trackOutput, err)
+	}
+	fmt.Printf("Ground track of %s (%d) written to %s\n", sat.Name, sat.NoradID, trackOutput)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"github.com/dzeleniak/icu/internal/storage"
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var watchlistCmd = &cobra.Command{
+	Use:   "watchlist",
+	Short: "Manage the list of watched satellites",
+	Long: `Manage a watchlist of NORAD IDs stored in the config file.
+Watched satellites can be used to restrict other commands, e.g. 'icu search visible --watchlist'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatchlistList()
+	},
+}
+
+var watchlistAddCmd = &cobra.Command{
+	Use:   "add NORAD_ID...",
+	Short: "Add satellites to the watchlist",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatchlistAdd(args)
+	},
+}
+
+var watchlistRemoveCmd = &cobra.Command{
+	Use:   "remove NORAD_ID...",
+	Short: "Remove satellites from the watchlist",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatchlistRemove(args)
+	},
+}
+
+var watchlistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List watched satellites",
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatchlistList()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchlistCmd)
+	watchlistCmd.AddCommand(watchlistAddCmd)
+	watchlistCmd.AddCommand(watchlistRemoveCmd)
+	watchlistCmd.AddCommand(watchlistListCmd)
+}
+
+// parseNoradIDs converts command arguments to NORAD IDs
+func parseNoradIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid NORAD ID: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// saveWatchlist persists the watchlist to the config file. Only the watchlist
+// key is changed; the file's other settings are rewritten as they were
+// rather than with every default filled in.
+func saveWatchlist(ids []int) error {
+	slices.Sort(ids)
+	config.Watchlist = ids
+	viper.Set("watchlist", ids)
+
+	file := viper.New()
+	file.SetConfigFile(viper.ConfigFileUsed())
+	if err := file.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	file.Set("watchlist", ids)
+	return file.WriteConfig()
+}
+
+func runWatchlistAdd(args []string) {
+	ids, err := parseNoradIDs(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	watchlist := slices.Clone(config.Watchlist)
+	for _, id := range ids {
+		if slices.Contains(watchlist, id) {
+			fmt.Printf("%d is already on the watchlist\n", id)
+			continue
+		}
+		watchlist = append(watchlist, id)
+		fmt.Printf("Added %d to the watchlist\n", id)
+	}
+
+	if err := saveWatchlist(watchlist); err != nil {
+		log.Fatalf("Error saving watchlist: %v", err)
+	}
+}
+
+func runWatchlistRemove(args []string) {
+	ids, err := parseNoradIDs(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	watchlist := slices.Clone(config.Watchlist)
+	for _, id := range ids {
+		i := slices.Index(watchlist, id)
+		if i < 0 {
+			fmt.Printf("%d is not on the watchlist\n", id)
+			continue
+		}
+		watchlist = slices.Delete(watchlist, i, i+1)
+		fmt.Printf("Removed %d from the watchlist\n", id)
+	}
+
+	if err := saveWatchlist(watchlist); err != nil {
+		log.Fatalf("Error saving watchlist: %v", err)
+	}
+}
+
+func runWatchlistList() {
+	if len(config.Watchlist) == 0 {
+		fmt.Println("Watchlist is empty. Add satellites with 'icu watchlist add NORAD_ID'.")
+		return
+	}
+
+	// Names are shown when a catalog is available
+	names := make(map[int]string)
+	if store, err := openCatalogStore(); err == nil {
+		names = watchlistNames(store)
+	}
+
+	for _, id := range config.Watchlist {
+		fmt.Printf("%-8d  %s\n", id, names[id])
+	}
+}
+
+// watchlistNames returns the names of the watchlisted satellites in the
+// store's catalog by NORAD ID, or none if it can't be read.
+func watchlistNames(store storage.CatalogStore) map[int]string {
+	names := make(map[int]string)
+	sats, err := store.Query(satellite.SearchCriteria{NoradIDs: config.Watchlist})
+	if err != nil {
+		return names
+	}
+	for _, sat := range sats {
+		names[sat.NoradID] = sat.Name
+	}
+	return names
+}
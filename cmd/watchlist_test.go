@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	"github.com/spf13/viper"
+)
+
+func TestWatchlistAddRemove(t *testing.T) {
+	loadTestConfig(t, "observer_latitude: 40\n")
+	path := viper.ConfigFileUsed()
+
+	captureStdout(t, func() { runWatchlistAdd([]string{"43013", "25544", "25544"}) })
+	if want := []int{25544, 43013}; !slices.Equal(config.Watchlist, want) {
+		t.Errorf("watchlist after add = %v, want %v", config.Watchlist, want)
+	}
+
+	out := captureStdout(t, func() { runWatchlistRemove([]string{"43013", "99999"}) })
+	if !strings.Contains(out, "99999 is not on the watchlist") {
+		t.Errorf("removing an unlisted ID printed %q", out)
+	}
+	if want := []int{25544}; !slices.Equal(config.Watchlist, want) {
+		t.Errorf("watchlist after remove = %v, want %v", config.Watchlist, want)
+	}
+
+	// Only the watchlist is added to the file; defaults are not written out
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	keys := file.AllKeys()
+	slices.Sort(keys)
+	if want := []string{"observer_latitude", "watchlist"}; !slices.Equal(keys, want) {
+		t.Errorf("config file keys = %v, want %v", keys, want)
+	}
+
+	viper.Reset()
+	reloaded, err := InitConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(reloaded.Watchlist, []int{25544}) || reloaded.ObserverLatitude != 40 {
+		t.Errorf("reloaded watchlist %v and latitude %v, want [25544] and 40", reloaded.Watchlist, reloaded.ObserverLatitude)
+	}
+}
+
+func TestWatchlistList(t *testing.T) {
+	cfg := loadTestConfig(t, "watchlist: [25544, 43013]\n")
+
+	store, err := satellite.NewStorage(cfg.DataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(&satellite.Catalog{Satellites: []*satellite.Satellite{testISS()}, FetchedAt: issEpoch}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, runWatchlistList)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "25544") || !strings.Contains(lines[0], "ISS (ZARYA)") ||
+		strings.TrimSpace(lines[1]) != "43013" {
+		t.Errorf("watchlist list printed:\n%s", out)
+	}
+
+	config.Watchlist = nil
+	if out := captureStdout(t, runWatchlistList); !strings.Contains(out, "Watchlist is empty") {
+		t.Errorf("empty watchlist printed %q", out)
+	}
+}
+
+func TestWatchlistVisibleFilter(t *testing.T) {
+	loadTestConfig(t, "watchlist: [25544]\n")
+
+	// Two satellites on the same orbit, both overhead of the observer
+	iss := testISS()
+	twin := testISS()
+	twin.NoradID = 25545
+	pos, err := satellite.PropagateSatellite(iss.TLE, issEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := satellite.SubSatellitePoint(pos)
+	observer := &satellite.ObserverPosition{Latitude: lat, Longitude: lon}
+
+	criteria := satellite.VisibilityCriteria{MinElevation: 10, MaxElevation: 90}
+	all, err := satellite.FindVisibleSatellites([]*satellite.Satellite{iss, twin}, observer, issEpoch, criteria, nil)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("unfiltered query found %d satellites (%v), want both", len(all), err)
+	}
+
+	criteria.NoradIDs = config.Watchlist
+	watched, err := satellite.FindVisibleSatellites([]*satellite.Satellite{iss, twin}, observer, issEpoch, criteria, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(watched) != 1 || watched[0].Satellite.NoradID != 25544 {
+		t.Errorf("watchlist query returned %d satellites, want only 25544", len(watched))
+	}
+}
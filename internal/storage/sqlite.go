@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// Catalog backends selectable with catalog_backend in config
+const (
+	BackendFile   = "file"   // the catalog file of satellite.Storage, the default
+	BackendSQLite = "sqlite" // a SQLite database, see SQLite
+)
+
+// SQLiteFile is the name of the SQLite catalog database in the data directory
+const SQLiteFile = "catalog.db"
+
+// sqliteSchema holds the catalog's own fields as JSON in a single row, and one
+// row per satellite with its JSON and the fields SearchSatellites matches by
+// substring or exactly, normalized the way it compares them.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS catalog (
+	id   INTEGER PRIMARY KEY CHECK (id = 1),
+	data BLOB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS satellites (
+	norad_id    INTEGER PRIMARY KEY,
+	name        TEXT NOT NULL, -- lowercase
+	owner       TEXT NOT NULL, -- uppercase
+	object_type TEXT NOT NULL, -- lowercase
+	intl_id     TEXT NOT NULL, -- uppercase
+	regime      TEXT NOT NULL, -- uppercase
+	data        BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS satellites_regime ON satellites (regime);
+`
+
+// SQLite stores the catalog in a SQLite database with a row per satellite, so
+// that GetByNorad and Query decode only the satellites they may return rather
+// than the whole catalog.
+type SQLite struct {
+	db   *sql.DB
+	path string
+}
+
+// OpenSQLite opens the catalog database at path, creating it if needed.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open catalog database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create catalog database: %w", err)
+	}
+	return &SQLite{db: db, path: path}, nil
+}
+
+// Close closes the database.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// CatalogPath returns the path of the database
+func (s *SQLite) CatalogPath() string {
+	return s.path
+}
+
+// Exists reports whether a catalog has been saved
+func (s *SQLite) Exists() bool {
+	var n int
+	return s.db.QueryRow(`SELECT COUNT(*) FROM catalog`).Scan(&n) == nil && n > 0
+}
+
+// Save replaces the stored catalog in a single transaction, so a failed save
+// leaves the previous catalog intact. A catalog that fails Validate is not
+// saved, as with the catalog file store.
+func (s *SQLite) Save(catalog *satellite.Catalog) error {
+	if err := catalog.Validate(); err != nil {
+		return err
+	}
+	header := *catalog
+	header.Satellites = nil
+	data, err := json.Marshal(&header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM satellites`); err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO catalog (id, data) VALUES (1, ?)`, data); err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO satellites (norad_id, name, owner, object_type, intl_id, regime, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	defer insert.Close()
+
+	for _, sat := range catalog.Satellites {
+		data, err := json.Marshal(sat)
+		if err != nil {
+			return fmt.Errorf("failed to marshal satellite %d: %w", sat.NoradID, err)
+		}
+		if _, err := insert.Exec(sat.NoradID, strings.ToLower(sat.Name), strings.ToUpper(sat.Owner),
+			strings.ToLower(sat.ObjectType), strings.ToUpper(sat.IntlID), strings.ToUpper(sat.OrbitRegime), data); err != nil {
+			return fmt.Errorf("failed to save satellite %d: %w", sat.NoradID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	return nil
+}
+
+// Load returns the whole stored catalog with its satellites in order of NORAD
+// ID, or nil if none is stored.
+func (s *SQLite) Load() (*satellite.Catalog, error) {
+	catalog, err := s.header()
+	if err != nil || catalog == nil {
+		return nil, err
+	}
+	if catalog.Satellites, err = s.satellites(`SELECT data FROM satellites ORDER BY norad_id`); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// FetchedAt returns when the stored catalog was fetched, or the zero time if
+// none is stored, without reading its satellites.
+func (s *SQLite) FetchedAt() (time.Time, error) {
+	catalog, err := s.header()
+	if err != nil || catalog == nil {
+		return time.Time{}, err
+	}
+	return catalog.FetchedAt, nil
+}
+
+// header returns the stored catalog without its satellites, or nil if none is
+// stored.
+func (s *SQLite) header() (*satellite.Catalog, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM catalog WHERE id = 1`).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var catalog satellite.Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal catalog: %w", err)
+	}
+	return &catalog, nil
+}
+
+// GetByNorad returns the satellite with the NORAD ID, or nil if it is not in
+// the catalog or no catalog is stored.
+func (s *SQLite) GetByNorad(noradID int) (*satellite.Satellite, error) {
+	sats, err := s.satellites(`SELECT data FROM satellites WHERE norad_id = ?`, noradID)
+	if err != nil || len(sats) == 0 {
+		return nil, err
+	}
+	return sats[0], nil
+}
+
+// Query returns the satellites matching the criteria as
+// satellite.SearchSatellites does. The NORAD ID, name, owner, type,
+// international designator and regime filters select rows in the database;
+// the rest are applied to those by SearchSatellites.
+func (s *SQLite) Query(criteria satellite.SearchCriteria) ([]*satellite.Satellite, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+
+	var where []string
+	var args []any
+	if len(criteria.NoradIDs) > 0 {
+		ids, err := json.Marshal(criteria.NoradIDs)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, `norad_id IN (SELECT value FROM json_each(?))`)
+		args = append(args, string(ids))
+	}
+	if criteria.NoradMin > 0 {
+		where = append(where, `norad_id >= ?`)
+		args = append(args, criteria.NoradMin)
+	}
+	if criteria.NoradMax > 0 {
+		where = append(where, `norad_id <= ?`)
+		args = append(args, criteria.NoradMax)
+	}
+	contains := func(column, value string) {
+		if value != "" {
+			where = append(where, `instr(`+column+`, ?) > 0`)
+			args = append(args, value)
+		}
+	}
+	contains("name", strings.ToLower(criteria.Name))
+	contains("owner", strings.ToUpper(criteria.Owner))
+	contains("object_type", strings.ToLower(criteria.Type))
+	contains("intl_id", strings.ToUpper(criteria.IntlID))
+	if criteria.Regime != "" {
+		where = append(where, `regime = ?`)
+		args = append(args, strings.ToUpper(criteria.Regime))
+	}
+
+	query := `SELECT data FROM satellites`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY norad_id`
+	sats, err := s.satellites(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return satellite.SearchSatellites(sats, criteria), nil
+}
+
+// satellites runs a query selecting satellite JSON and decodes the rows.
+func (s *SQLite) satellites(query string, args ...any) ([]*satellite.Satellite, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog: %w", err)
+	}
+	defer rows.Close()
+
+	sats := make([]*satellite.Satellite, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read satellite: %w", err)
+		}
+		sat := new(satellite.Satellite)
+		if err := json.Unmarshal(data, sat); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal satellite: %w", err)
+		}
+		sats = append(sats, sat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query catalog: %w", err)
+	}
+	return sats, nil
+}
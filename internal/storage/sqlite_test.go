@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// testTLE returns a TLE for a synthetic satellite with a valid checksum.
+func testTLE(noradID int, incl, ecc, meanMotion float64) satellite.TLE {
+	checksum := func(line string) int {
+		sum := 0
+		for _, c := range line {
+			switch {
+			case c >= '0' && c <= '9':
+				sum += int(c - '0')
+			case c == '-':
+				sum++
+			}
+		}
+		return sum % 10
+	}
+	line1 := fmt.Sprintf("1 %05dU 24001A   24061.00000000  .00000000  00000-0  00000-0 0  999", noradID)
+	line2 := fmt.Sprintf("2 %05d %8.4f 100.0000 %07d  90.0000 270.0000 %11.8f    1", noradID, incl, int(ecc*1e7), meanMotion)
+	return satellite.TLE{
+		Line1: fmt.Sprintf("%s%d", line1, checksum(line1)),
+		Line2: fmt.Sprintf("%s%d", line2, checksum(line2)),
+	}
+}
+
+// testCatalog returns a catalog merged from synthetic feeds, with a mix of
+// regimes, owners, types, launch dates and names, some non-ASCII, and a few
+// SATCAT-only satellites.
+func testCatalog(n int) *satellite.Catalog {
+	names := []string{"STARLINK-%d", "COSMOS %d DEB", "Éclair %d", "NAVSTAR %d", "ISS (ZARYA) %d", "straße %d"}
+	owners := []string{"US", "CIS", "PRC", "FR", "ISS"}
+	types := []string{"PAYLOAD", "DEBRIS", "ROCKET BODY"}
+	orbits := [][2]float64{{53, 15.06}, {82.5, 14.2}, {55, 2.0056}, {0.05, 1.00273791}, {63.4, 2.006}}
+
+	var tles []satellite.TLE
+	var satcats []satellite.SATCAT
+	for i := 1; i <= n; i++ {
+		orbit := orbits[i%len(orbits)]
+		ecc := 0.001
+		if i%len(orbits) == 4 {
+			ecc = 0.7 // Molniya
+		}
+		if i%10 != 0 {
+			tles = append(tles, testTLE(i, orbit[0], ecc, orbit[1]))
+		}
+		if i%7 != 0 {
+			satcats = append(satcats, satellite.SATCAT{
+				NoradID:    i,
+				Name:       fmt.Sprintf(names[i%len(names)], i),
+				IntlID:     fmt.Sprintf("%d-%03dA", 1990+i%30, i%200),
+				Owner:      owners[i%len(owners)],
+				ObjectType: types[i%len(types)],
+				LaunchDate: fmt.Sprintf("%d-06-01", 1990+i%30),
+			})
+		}
+	}
+	sats, report := satellite.MergeSatelliteDataWith(tles, satcats, satellite.MergeOptions{IncludeSATCATOnly: true})
+	return &satellite.Catalog{Satellites: sats, FetchedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), DroppedTLEs: report.DroppedTLEs + 2}
+}
+
+// toJSON returns v encoded as JSON, to compare results.
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// openStores returns a catalog file store and a SQLite store in a temporary
+// directory.
+func openStores(t *testing.T) (*satellite.Storage, *SQLite) {
+	t.Helper()
+	dir := t.TempDir()
+	file, err := satellite.NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := OpenSQLite(filepath.Join(dir, SQLiteFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return file, db
+}
+
+func TestSQLiteMatchesFileStore(t *testing.T) {
+	file, db := openStores(t)
+	stores := map[string]CatalogStore{"file": file, "sqlite": db}
+
+	// Without a catalog
+	for name, store := range stores {
+		if store.Exists() {
+			t.Errorf("%s: Exists before a save", name)
+		}
+		if catalog, err := store.Load(); catalog != nil || err != nil {
+			t.Errorf("%s: Load without a catalog = %v, %v; want nil, nil", name, catalog, err)
+		}
+		if fetchedAt, err := store.FetchedAt(); !fetchedAt.IsZero() || err != nil {
+			t.Errorf("%s: FetchedAt without a catalog = %v, %v; want the zero time", name, fetchedAt, err)
+		}
+		if sat, err := store.GetByNorad(1); sat != nil || err != nil {
+			t.Errorf("%s: GetByNorad without a catalog = %v, %v; want nil, nil", name, sat, err)
+		}
+		if sats, err := store.Query(satellite.SearchCriteria{}); sats == nil || len(sats) != 0 || err != nil {
+			t.Errorf("%s: Query without a catalog = %v, %v; want an empty result", name, sats, err)
+		}
+	}
+
+	// A larger catalog is replaced by a smaller one
+	for _, catalog := range []*satellite.Catalog{testCatalog(400), testCatalog(300)} {
+		for name, store := range stores {
+			if err := store.Save(catalog); err != nil {
+				t.Fatalf("%s: Save: %v", name, err)
+			}
+		}
+	}
+	catalog := testCatalog(300)
+	want := toJSON(t, catalog)
+
+	for name, store := range stores {
+		if !store.Exists() {
+			t.Errorf("%s: Exists after a save", name)
+		}
+		loaded, err := store.Load()
+		if err != nil || loaded == nil {
+			t.Fatalf("%s: Load = %v, %v", name, loaded, err)
+		}
+		if got := toJSON(t, loaded); got != want {
+			t.Errorf("%s: Load returned a different catalog than was saved", name)
+		}
+		if fetchedAt, err := store.FetchedAt(); !fetchedAt.Equal(catalog.FetchedAt) || err != nil {
+			t.Errorf("%s: FetchedAt = %v, %v; want %v", name, fetchedAt, err, catalog.FetchedAt)
+		}
+	}
+
+	for _, id := range []int{1, 7, 10, 150, 300, 301, 0, -5} {
+		fromFile, err := file.GetByNorad(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromDB, err := db.GetByNorad(id)
+		if err != nil {
+			t.Fatalf("GetByNorad(%d): %v", id, err)
+		}
+		if got, want := toJSON(t, fromDB), toJSON(t, fromFile); got != want {
+			t.Errorf("GetByNorad(%d) = %s, want %s", id, got, want)
+		}
+	}
+
+	bound := func(v float64) *float64 { return &v }
+	queries := []struct {
+		name     string
+		criteria satellite.SearchCriteria
+	}{
+		{"everything", satellite.SearchCriteria{}},
+		{"name", satellite.SearchCriteria{Name: "starlink"}},
+		{"non-ASCII name", satellite.SearchCriteria{Name: "ÉCLAIR"}},
+		{"mixed-case non-ASCII name", satellite.SearchCriteria{Name: "STRAßE"}},
+		{"name with parentheses", satellite.SearchCriteria{Name: "(zarya)"}},
+		{"regex", satellite.SearchCriteria{NameRegex: regexp.MustCompile(`^COSMOS \d+1 `)}},
+		{"fuzzy", satellite.SearchCriteria{FuzzyName: "NAVSTAR 12"}},
+		{"owner", satellite.SearchCriteria{Owner: "prc"}},
+		{"type", satellite.SearchCriteria{Type: "rocket"}},
+		{"intl ID", satellite.SearchCriteria{IntlID: "2001-"}},
+		{"regime", satellite.SearchCriteria{Regime: "geo"}},
+		{"orbit type", satellite.SearchCriteria{OrbitType: "molniya"}},
+		{"launch year", satellite.SearchCriteria{LaunchYear: 2005}},
+		{"period band", satellite.SearchCriteria{PeriodCenter: 95, PeriodTolerance: 2}},
+		{"inclination bounds", satellite.SearchCriteria{InclinationMin: bound(50), InclinationMax: bound(60)}},
+		{"NORAD IDs", satellite.SearchCriteria{NoradIDs: []int{3, 5, 70, 299, 1000}}},
+		{"NORAD range", satellite.SearchCriteria{NoradMin: 100, NoradMax: 120}},
+		{"combined", satellite.SearchCriteria{Owner: "US", Type: "payload", Regime: "LEO", NoradMax: 200}},
+		{"no match", satellite.SearchCriteria{Name: "no such satellite"}},
+	}
+	for _, q := range queries {
+		fromFile, err := file.Query(q.criteria)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromDB, err := db.Query(q.criteria)
+		if err != nil {
+			t.Fatalf("%s: Query: %v", q.name, err)
+		}
+		if got, want := toJSON(t, fromDB), toJSON(t, fromFile); got != want {
+			t.Errorf("%s: SQLite returned %d satellites, file store %d", q.name, len(fromDB), len(fromFile))
+		}
+		if q.name != "no match" && len(fromFile) == 0 {
+			t.Errorf("%s: fixture has no matches", q.name)
+		}
+	}
+
+	invalid := satellite.SearchCriteria{Name: "a", NameRegex: regexp.MustCompile("a")}
+	for name, store := range stores {
+		if _, err := store.Query(invalid); err == nil {
+			t.Errorf("%s: Query accepted a name and a name regex", name)
+		}
+	}
+}
+
+func TestSQLiteRejectsDuplicateIDs(t *testing.T) {
+	file, db := openStores(t)
+	stores := map[string]CatalogStore{"file": file, "sqlite": db}
+
+	saved := testCatalog(20)
+	duplicated := testCatalog(20)
+	dup := *duplicated.Satellites[3]
+	dup.Name = "DUPLICATE"
+	duplicated.Satellites = append(duplicated.Satellites, &dup)
+
+	for name, store := range stores {
+		if err := store.Save(saved); err != nil {
+			t.Fatalf("%s: Save: %v", name, err)
+		}
+		err := store.Save(duplicated)
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("NORAD ID %d", dup.NoradID)) {
+			t.Errorf("%s: Save of a catalog with NORAD ID %d twice = %v, want an error naming it", name, dup.NoradID, err)
+		}
+
+		// The catalog saved before is left as it was
+		loaded, err := store.Load()
+		if err != nil || loaded == nil || toJSON(t, loaded) != toJSON(t, saved) {
+			t.Errorf("%s: Load after a rejected save = %v, %v; want the earlier catalog", name, loaded, err)
+		}
+	}
+}
+
+func TestSQLiteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFile)
+	db, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := testCatalog(50)
+	if err := db.Save(catalog); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer db.Close()
+	if db.CatalogPath() != path {
+		t.Errorf("CatalogPath = %s, want %s", db.CatalogPath(), path)
+	}
+	loaded, err := db.Load()
+	if err != nil || loaded == nil || toJSON(t, loaded) != toJSON(t, catalog) {
+		t.Errorf("reopened Load = %v, %v; want the saved catalog", loaded, err)
+	}
+}
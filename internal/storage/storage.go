@@ -0,0 +1,40 @@
+// Package storage defines the interface the CLI uses to read and write the
+// satellite catalog, so that backends other than the catalog file in
+// satellite.Storage can be plugged in.
+package storage
+
+import (
+	"time"
+
+	"github.com/dzeleniak/icu/pkg/satellite"
+)
+
+// CatalogStore persists a satellite catalog and answers queries against it.
+// Backends that index the catalog can answer GetByNorad and Query without
+// loading all of it.
+type CatalogStore interface {
+	// Save replaces the stored catalog. A catalog that fails
+	// satellite.Catalog.Validate is rejected, leaving the stored one as is.
+	Save(catalog *satellite.Catalog) error
+	// Load returns the whole stored catalog, or nil if none is stored.
+	Load() (*satellite.Catalog, error)
+	// GetByNorad returns the satellite with the NORAD ID, or nil if it is
+	// not in the catalog or no catalog is stored.
+	GetByNorad(noradID int) (*satellite.Satellite, error)
+	// Query returns the satellites matching the criteria as
+	// satellite.SearchSatellites does.
+	Query(criteria satellite.SearchCriteria) ([]*satellite.Satellite, error)
+	// FetchedAt returns when the stored catalog was fetched, or the zero
+	// time if none is stored.
+	FetchedAt() (time.Time, error)
+	// Exists reports whether a catalog is stored.
+	Exists() bool
+	// CatalogPath returns where the catalog is stored, for display.
+	CatalogPath() string
+}
+
+// The catalog file store is the default backend
+var (
+	_ CatalogStore = (*satellite.Storage)(nil)
+	_ CatalogStore = (*SQLite)(nil)
+)
@@ -0,0 +1,91 @@
+package satellite
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Annotation holds user-owned tags and a free-form note for a satellite.
+type Annotation struct {
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// Annotations maps NORAD IDs to user annotations. They are stored separately
+// from the fetched catalog so re-fetching does not discard them.
+type Annotations map[int]*Annotation
+
+// normalizeTag trims and lowercases a tag so matching is case-insensitive.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// get returns the annotation for a NORAD ID, creating it if needed.
+func (a Annotations) get(noradID int) *Annotation {
+	ann, ok := a[noradID]
+	if !ok {
+		ann = &Annotation{}
+		a[noradID] = ann
+	}
+	return ann
+}
+
+// prune removes the annotation for a NORAD ID if it no longer holds anything.
+func (a Annotations) prune(noradID int) {
+	if ann, ok := a[noradID]; ok && len(ann.Tags) == 0 && ann.Note == "" {
+		delete(a, noradID)
+	}
+}
+
+// AddTags adds tags to a satellite, ignoring blanks and duplicates.
+// Tags are stored lowercase and sorted.
+func (a Annotations) AddTags(noradID int, tags ...string) {
+	ann := a.get(noradID)
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag != "" && !slices.Contains(ann.Tags, tag) {
+			ann.Tags = append(ann.Tags, tag)
+		}
+	}
+	sort.Strings(ann.Tags)
+	a.prune(noradID)
+}
+
+// RemoveTags removes tags from a satellite.
+func (a Annotations) RemoveTags(noradID int, tags ...string) {
+	ann, ok := a[noradID]
+	if !ok {
+		return
+	}
+	for _, tag := range tags {
+		if i := slices.Index(ann.Tags, normalizeTag(tag)); i >= 0 {
+			ann.Tags = slices.Delete(ann.Tags, i, i+1)
+		}
+	}
+	a.prune(noradID)
+}
+
+// SetNote sets a satellite's note. An empty note clears it.
+func (a Annotations) SetNote(noradID int, note string) {
+	a.get(noradID).Note = strings.TrimSpace(note)
+	a.prune(noradID)
+}
+
+// HasTag reports whether a satellite carries the tag (case-insensitive).
+func (a Annotations) HasTag(noradID int, tag string) bool {
+	ann, ok := a[noradID]
+	return ok && slices.Contains(ann.Tags, normalizeTag(tag))
+}
+
+// WithTag returns the sorted NORAD IDs of satellites carrying the tag.
+func (a Annotations) WithTag(tag string) []int {
+	ids := make([]int, 0)
+	for id := range a {
+		if a.HasTag(id, tag) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,87 @@
+package satellite
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnnotationsTagging(t *testing.T) {
+	a := Annotations{}
+	a.AddTags(25544, "Downlink", " ham ", "downlink", "")
+	a.AddTags(43013, "HAM")
+	a.SetNote(43013, "  suspected tumbling ")
+
+	if got := a[25544].Tags; !slices.Equal(got, []string{"downlink", "ham"}) {
+		t.Errorf("tags = %v, want [downlink ham]", got)
+	}
+	if !a.HasTag(25544, "DOWNLINK") || a.HasTag(43013, "downlink") {
+		t.Error("HasTag is not case-insensitive per satellite")
+	}
+	if got := a[43013].Note; got != "suspected tumbling" {
+		t.Errorf("note = %q, want it trimmed", got)
+	}
+
+	if got := a.WithTag("ham"); !slices.Equal(got, []int{25544, 43013}) {
+		t.Errorf("WithTag(ham) = %v, want [25544 43013]", got)
+	}
+	if got := a.WithTag("nothing"); len(got) != 0 {
+		t.Errorf("WithTag(nothing) = %v, want none", got)
+	}
+
+	// Searching by the tagged IDs filters the catalog
+	satellites := []*Satellite{
+		testSatellite("ISS", issTLE()),
+		testSatellite("GEO", geoTLE()),
+		{NoradID: 43013, Name: "NOAA 20"},
+	}
+	if got := searchIDs(t, satellites, SearchCriteria{NoradIDs: a.WithTag("downlink")}); !slices.Equal(got, []int{25544}) {
+		t.Errorf("search by tag downlink = %v, want [25544]", got)
+	}
+
+	// Empty annotations are dropped
+	a.RemoveTags(25544, "downlink", "HAM")
+	a.SetNote(43013, "")
+	a.RemoveTags(43013, "ham")
+	if len(a) != 0 {
+		t.Errorf("annotations after removing everything = %v, want none", a)
+	}
+}
+
+func TestAnnotationsSurviveRefetch(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(&Catalog{Satellites: []*Satellite{testSatellite("ISS", issTLE())}, FetchedAt: testEpoch}); err != nil {
+		t.Fatal(err)
+	}
+	a := Annotations{}
+	a.AddTags(25544, "crewed")
+	a.SetNote(25544, "main downlink target")
+	if err := store.SaveAnnotations(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// A re-fetch replaces the catalog wholesale
+	refetched := &Catalog{Satellites: []*Satellite{testSatellite("ISS (ZARYA)", issTLE()), testSatellite("GEO", geoTLE())}, FetchedAt: testEpoch.AddDate(0, 0, 1)}
+	if err := store.Save(refetched); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.LoadAnnotations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.HasTag(25544, "crewed") || loaded[25544].Note != "main downlink target" {
+		t.Errorf("annotations after re-fetch = %+v, want the tag and note kept", loaded[25544])
+	}
+
+	empty, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := empty.LoadAnnotations(); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("LoadAnnotations with no file = %v, %v; want an empty map", got, err)
+	}
+}
@@ -0,0 +1,118 @@
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScheduledPass is a pass of a particular satellite
+type ScheduledPass struct {
+	Satellite *Satellite
+	Pass      Pass
+}
+
+// PredictSchedule predicts the passes of each satellite over the observer
+// between start and end and returns them together in order of AOS, along
+// with an error for each satellite whose passes could not be predicted.
+// Those satellites, and satellites without a TLE, are skipped.
+// If progress is non-nil it is called as satellites are processed.
+func PredictSchedule(satellites []*Satellite, observer *ObserverPosition, start, end time.Time, step time.Duration, minElevation float64, progress ProgressFunc) ([]ScheduledPass, []error) {
+	counter := newProgressCounter(len(satellites), progress)
+	defer counter.finish()
+
+	var schedule []ScheduledPass
+	var errs []error
+	for _, sat := range satellites {
+		if sat.TLE == nil {
+			counter.step()
+			continue
+		}
+		passes, err := PredictPasses(sat.TLE, observer, start, end, step, minElevation)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s (%d): %w", sat.Name, sat.NoradID, err))
+			counter.step()
+			continue
+		}
+		for _, pass := range passes {
+			schedule = append(schedule, ScheduledPass{Satellite: sat, Pass: pass})
+		}
+		counter.step()
+	}
+
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].Pass.AOS.Before(schedule[j].Pass.AOS)
+	})
+	return schedule, errs
+}
+
+// icsTime is the iCalendar UTC date-time format
+const icsTime = "20060102T150405Z"
+
+// icsEscape escapes text for an iCalendar TEXT value (RFC 5545 3.3.11)
+var icsEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icsWriter writes iCalendar content lines, folding them at 75 octets and
+// ending them with CRLF as RFC 5545 requires.
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (iw *icsWriter) line(name, value string) {
+	if iw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		// Don't split a multi-byte UTF-8 sequence
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, iw.err = iw.w.WriteString(line[:cut] + "\r\n "); iw.err != nil {
+			return
+		}
+		line = line[cut:]
+		limit = 74 // continuation lines begin with a space
+	}
+	_, iw.err = iw.w.WriteString(line + "\r\n")
+}
+
+// WritePassCalendar writes the passes as an iCalendar (.ics) file with one
+// event per pass, from AOS to LOS and named after the satellite, for import
+// into calendar applications.
+func WritePassCalendar(w io.Writer, schedule []ScheduledPass) error {
+	iw := &icsWriter{w: bufio.NewWriter(w)}
+	stamp := time.Now().UTC().Format(icsTime)
+
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", "-//icu//Satellite Passes//EN")
+	iw.line("CALSCALE", "GREGORIAN")
+	for _, sp := range schedule {
+		pass := &sp.Pass
+		desc := fmt.Sprintf("NORAD %d\nMax elevation %.1f° at %s\nDirection %s",
+			sp.Satellite.NoradID, pass.MaxElevation,
+			pass.MaxElevationTime.UTC().Format("15:04:05 UTC"), PassDirection(pass))
+
+		iw.line("BEGIN", "VEVENT")
+		iw.line("UID", fmt.Sprintf("%d-%d@icu", sp.Satellite.NoradID, pass.AOS.Unix()))
+		iw.line("DTSTAMP", stamp)
+		iw.line("DTSTART", pass.AOS.UTC().Format(icsTime))
+		iw.line("DTEND", pass.LOS.UTC().Format(icsTime))
+		iw.line("SUMMARY", icsEscape.Replace(fmt.Sprintf("%s pass (max %.0f°)", sp.Satellite.Name, pass.MaxElevation)))
+		iw.line("DESCRIPTION", icsEscape.Replace(desc))
+		iw.line("END", "VEVENT")
+	}
+	iw.line("END", "VCALENDAR")
+
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
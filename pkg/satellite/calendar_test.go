@@ -0,0 +1,118 @@
+package satellite
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPredictScheduleSkipsFailures(t *testing.T) {
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+	iss := testSatellite("ISS", issTLE())
+	sso := testSatellite("SSO", makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5))
+	decayed := testSatellite("DECAYED", makeTLE(30002, testEpoch, 53, 20, 0.15, 0, 0, 14))
+	satellites := []*Satellite{iss, decayed, {NoradID: 99999, Name: "SATCAT ONLY"}, sso}
+	start, end := testEpoch, testEpoch.Add(24*time.Hour)
+
+	schedule, errs := PredictSchedule(satellites, observer, start, end, time.Minute, 10, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "DECAYED (30002)") {
+		t.Errorf("errors = %v, want one for the decayed satellite", errs)
+	}
+
+	// The schedule is every pass of the good satellites, in order of AOS
+	want := 0
+	for _, sat := range []*Satellite{iss, sso} {
+		passes, err := PredictPasses(sat.TLE, observer, start, end, time.Minute, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(passes) == 0 {
+			t.Fatalf("%s has no passes to schedule", sat.Name)
+		}
+		want += len(passes)
+	}
+	if len(schedule) != want {
+		t.Errorf("scheduled %d passes, want %d", len(schedule), want)
+	}
+	for i := 1; i < len(schedule); i++ {
+		if schedule[i].Pass.AOS.Before(schedule[i-1].Pass.AOS) {
+			t.Errorf("pass %d at %v scheduled after one at %v", i, schedule[i].Pass.AOS, schedule[i-1].Pass.AOS)
+		}
+	}
+}
+
+func TestWritePassCalendar(t *testing.T) {
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+	satellites := []*Satellite{
+		testSatellite("ISS (ZARYA), crew; \\ station", issTLE()),
+		testSatellite("SSO SATELLITE WITH A RATHER LONG NAME THAT NEEDS FOLDING", makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5)),
+	}
+	schedule, errs := PredictSchedule(satellites, observer, testEpoch, testEpoch.Add(24*time.Hour), time.Minute, 10, nil)
+	if len(errs) != 0 || len(schedule) < 2 {
+		t.Fatalf("PredictSchedule: %d passes, errors %v", len(schedule), errs)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePassCalendar(&buf, schedule); err != nil {
+		t.Fatalf("WritePassCalendar: %v", err)
+	}
+	data := buf.String()
+	if !strings.HasSuffix(data, "\r\n") || strings.Contains(strings.ReplaceAll(data, "\r\n", ""), "\n") {
+		t.Fatal("content lines are not all ended with CRLF")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets is not folded: %q", len(line), line)
+		}
+	}
+
+	// Unfold and check the calendar holds one event per pass
+	unfolded := strings.ReplaceAll(data, "\r\n ", "")
+	lines := strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n")
+	if lines[0] != "BEGIN:VCALENDAR" || lines[1] != "VERSION:2.0" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Errorf("calendar framed by %q ... %q", lines[:2], lines[len(lines)-1])
+	}
+	var events []map[string]string
+	var event map[string]string
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			t.Fatalf("content line %q has no value", line)
+		}
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]string)
+		case line == "END:VEVENT":
+			events = append(events, event)
+			event = nil
+		case event != nil:
+			event[name] = value
+		}
+	}
+	if len(events) != len(schedule) {
+		t.Fatalf("calendar has %d events, want %d", len(events), len(schedule))
+	}
+
+	uids := make(map[string]bool)
+	for i, sp := range schedule {
+		e := events[i]
+		if uids[e["UID"]] {
+			t.Errorf("event %d repeats UID %s", i, e["UID"])
+		}
+		uids[e["UID"]] = true
+		if want := fmt.Sprintf("%d-%d@icu", sp.Satellite.NoradID, sp.Pass.AOS.Unix()); e["UID"] != want {
+			t.Errorf("event %d UID = %s, want %s", i, e["UID"], want)
+		}
+		if e["DTSTART"] != sp.Pass.AOS.UTC().Format(icsTime) || e["DTEND"] != sp.Pass.LOS.UTC().Format(icsTime) {
+			t.Errorf("event %d runs %s to %s, want the pass from AOS to LOS", i, e["DTSTART"], e["DTEND"])
+		}
+		if !strings.Contains(e["DESCRIPTION"], fmt.Sprintf(`NORAD %d\n`, sp.Satellite.NoradID)) {
+			t.Errorf("event %d description %q has no escaped NORAD line", i, e["DESCRIPTION"])
+		}
+		if sp.Satellite.NoradID == 25544 && !strings.HasPrefix(e["SUMMARY"], `ISS (ZARYA)\, crew\; \\ station pass`) {
+			t.Errorf("summary %q is not escaped", e["SUMMARY"])
+		}
+	}
+}
@@ -1,118 +1,822 @@
 package satellite
 
 import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // SearchCriteria represents multi-criteria search parameters for satellites.
 type SearchCriteria struct {
-	Name   string // partial match, case-insensitive
-	Owner  string // partial match, case-insensitive
-	Type   string // partial match, case-insensitive
-	Regime string // exact match, case-insensitive
+	Name          string         // partial match, case-insensitive
+	NameRegex     *regexp.Regexp // name pattern, see CompileNameRegex; nil = any, not with Name (see Validate)
+	Owner         string         // partial match, case-insensitive
+	Type          string         // partial match, case-insensitive
+	Regime        string         // exact match, case-insensitive
+	OrbitType     string         // exact match, case-insensitive, or "SPECIAL" for any named OrbitType
+	IntlID        string         // partial match, case-insensitive, e.g. "1998-067" for one launch
+	FuzzyName     string         // approximate name match, see FuzzyNameDistance
+	MaxDistance   int            // largest FuzzyNameDistance FuzzyName accepts, 0 = FuzzyMaxDistance of FuzzyName
+	LaunchYear    int            // launch year, 0 = any
+	LaunchedSince time.Time      // launched on or after this date, zero = any
+
+	PeriodCenter    float64 // orbital period band center in minutes, 0 = any
+	PeriodTolerance float64 // allowed deviation from PeriodCenter in minutes
+
+	// Orbital parameter bounds, inclusive; nil = unbounded. Parameters come
+	// from SATCAT, or the TLE when SATCAT has none. Satellites with neither
+	// are excluded when any bound is set.
+	InclinationMin *float64 // degrees
+	InclinationMax *float64 // degrees
+	PeriodMin      *float64 // minutes
+	PeriodMax      *float64 // minutes
+	ApogeeMax      *float64 // km
+	PerigeeMin     *float64 // km
+
+	NoradIDs []int // restrict to these NORAD IDs, empty = any
+	NoradMin int   // lowest NORAD ID, 0 = any
+	NoradMax int   // highest NORAD ID, 0 = any
 }
 
 // VisibilityCriteria represents visibility search parameters.
 type VisibilityCriteria struct {
-	SearchCriteria              // Embed standard search criteria
-	MinElevation   float64      // degrees
-	MaxElevation   float64      // degrees
+	SearchCriteria           // Embed standard search criteria
+	MinElevation     float64 // degrees
+	MaxElevation     float64 // degrees
+	SunlitOnly       bool    // only satellites out of the Earth's umbra
+	DarkOnly         bool    // only while the Sun is at or below DarkSunElevation at the observer
+	DarkSunElevation float64 // Sun elevation threshold in degrees for DarkOnly, e.g. -6 (civil twilight)
+	Workers          int     // goroutines used to propagate candidates, 0 = GOMAXPROCS
+	AllowNullIsland  bool    // accept a zero-value observer at 0°, 0° instead of returning ErrObserverUnset
 }
 
+// ErrObserverUnset is returned by FindVisibleSatellites when the observer is
+// nil or the zero value, which almost always means it was never configured.
+var ErrObserverUnset = errors.New("observer location is not set")
+
 // VisibleSatellite represents a satellite with its current observation angles.
 type VisibleSatellite struct {
-	Satellite *Satellite
-	Angles    *ObservationAngles
+	Satellite *Satellite         `json:"satellite"`
+	Angles    *ObservationAngles `json:"angles"`
+}
+
+// ProgressFunc receives the fraction (0-1) of a batch operation completed so far.
+// Calls are serialized, non-decreasing, and end with exactly 1.
+type ProgressFunc func(fraction float64)
+
+// progressCounter reports completed items out of a known total to a ProgressFunc.
+// A nil counter or callback is a no-op, so batch functions can always use one.
+type progressCounter struct {
+	mu    sync.Mutex
+	done  int
+	total int
+	fn    ProgressFunc
+}
+
+func newProgressCounter(total int, fn ProgressFunc) *progressCounter {
+	return &progressCounter{total: total, fn: fn}
+}
+
+// step records one completed item. Safe for concurrent use.
+func (p *progressCounter) step() {
+	if p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.fn(float64(p.done) / float64(p.total))
+}
+
+// finish reports completion for empty batches, which never call step.
+func (p *progressCounter) finish() {
+	if p.fn != nil && p.total == 0 {
+		p.fn(1)
+	}
+}
+
+// MergeReport describes input dropped while merging TLE and SATCAT data.
+type MergeReport struct {
+	DroppedTLEs int     // TLEs whose NORAD ID could not be parsed
+	Errors      []error // parse error for each dropped TLE
 }
 
 // MergeSatelliteData combines TLE and SATCAT data into Satellite objects.
 // TLEs are used as the primary key, with SATCAT data merged when available.
 // Satellites with missing orbital parameters have their orbit regime classified.
 func MergeSatelliteData(tles []TLE, satcats []SATCAT) []*Satellite {
-	// Create maps for efficient lookup
-	tleMap := make(map[int]*TLE)
+	satellites, _ := MergeSatelliteDataReport(tles, satcats)
+	return satellites
+}
+
+// MergeSatelliteDataReport merges like MergeSatelliteData and also reports
+// TLEs dropped because their NORAD ID could not be parsed.
+func MergeSatelliteDataReport(tles []TLE, satcats []SATCAT) ([]*Satellite, *MergeReport) {
+	return MergeSatelliteDataWith(tles, satcats, MergeOptions{})
+}
+
+// MergeOptions controls how MergeSatelliteDataWith combines feed data.
+type MergeOptions struct {
+	// TLEHistory keeps every TLE given for a NORAD ID in Satellite.TLEHistory,
+	// oldest epoch first, and uses the newest as the satellite's TLE. By default
+	// the last TLE in the feed for each NORAD ID is used and the rest dropped.
+	TLEHistory bool
+
+	// IncludeSATCATOnly adds a satellite with a nil TLE for each SATCAT entry
+	// that has no TLE, such as decayed or untracked objects, so that their
+	// metadata can still be searched and displayed. By default they are left
+	// out, since they cannot be propagated.
+	IncludeSATCATOnly bool
+}
+
+// MergeSatelliteDataWith merges like MergeSatelliteDataReport with options.
+func MergeSatelliteDataWith(tles []TLE, satcats []SATCAT, opts MergeOptions) ([]*Satellite, *MergeReport) {
+	report := &MergeReport{}
+
+	// Index the last TLE for each NORAD ID; later duplicates replace earlier ones
+	noradIDs := make([]int, len(tles))
+	tleIndex := make(map[int]int, len(tles))
+	var history map[int][]int
+	if opts.TLEHistory {
+		history = make(map[int][]int)
+	}
 	for i := range tles {
-		noradID := tles[i].GetNoradID()
-		if noradID > 0 {
-			tleMap[noradID] = &tles[i]
+		noradID, err := tles[i].GetNoradIDErr()
+		if err != nil {
+			report.DroppedTLEs++
+			report.Errors = append(report.Errors, err)
+			continue
+		}
+		noradIDs[i] = noradID
+		tleIndex[noradID] = i
+		if history != nil {
+			history[noradID] = append(history[noradID], i)
 		}
 	}
 
-	satcatMap := make(map[int]*SATCAT)
+	satcatMap := make(map[int]*SATCAT, len(satcats))
 	for i := range satcats {
 		satcatMap[satcats[i].NoradID] = &satcats[i]
 	}
 
-	// Merge satellites using TLE as primary key
-	satellites := make([]*Satellite, 0, len(tleMap))
+	// Merge satellites using TLE as primary key, in input order so that the
+	// final sort is cheap for feeds that are already ordered by NORAD ID
+	satellites := make([]*Satellite, 0, len(tleIndex))
+
+	for i := range tles {
+		noradID := noradIDs[i]
+		if last, ok := tleIndex[noradID]; !ok || last != i {
+			continue // unparseable or superseded by a later duplicate
+		}
+		tle := &tles[i]
 
-	for noradID, tle := range tleMap {
 		sat := &Satellite{
 			NoradID: noradID,
 			TLE:     tle,
 		}
+		if indices := history[noradID]; len(indices) > 1 {
+			sat.TLEHistory = tleHistory(tles, indices)
+			sat.TLE = &sat.TLEHistory[len(sat.TLEHistory)-1]
+		}
+		if epoch, err := sat.TLE.GetEpoch(); err == nil {
+			sat.TLEEpoch = epoch
+		}
+		sat.TLE.cacheElements()
 
 		// Merge SATCAT data if available
 		if satcat, exists := satcatMap[noradID]; exists {
-			sat.SATCAT = satcat
-			sat.Name = satcat.Name
-			sat.IntlID = satcat.IntlID
-			sat.ObjectType = satcat.ObjectType
-			sat.Owner = satcat.Owner
-			sat.LaunchDate = satcat.LaunchDate
-			sat.DecayDate = satcat.DecayDate
-			sat.LaunchSite = satcat.LaunchSite
-			sat.Period = satcat.Period
-			sat.Inclination = satcat.Inclination
-			sat.Apogee = satcat.Apogee
-			sat.Perigee = satcat.Perigee
-			sat.RCSSize = satcat.RCSSize
-
-			// Determine orbit regime from orbital parameters
-			sat.OrbitRegime = string(DetermineOrbitRegime(
-				sat.Apogee,
-				sat.Perigee,
-				sat.Period,
-				sat.Inclination,
-			))
+			applySATCAT(sat, satcat)
 		} else {
-			// TLE without SATCAT entry - use NORAD ID as name
-			sat.Name = ""
-			sat.OrbitRegime = "UNKNOWN"
+			// TLE without SATCAT entry - use the 3LE name line if there was one
+			sat.Name = tle.Name
 		}
 
+		// Determine orbit regime from orbital parameters, falling back to the TLE
+		sat.OrbitRegime = string(ClassifyOrbitRegime(sat))
+
 		satellites = append(satellites, sat)
 	}
 
+	if opts.IncludeSATCATOnly {
+		for i := range satcats {
+			satcat := &satcats[i]
+			if _, hasTLE := tleIndex[satcat.NoradID]; hasTLE || satcatMap[satcat.NoradID] != satcat {
+				continue // merged above or superseded by a later duplicate
+			}
+			sat := &Satellite{NoradID: satcat.NoradID}
+			applySATCAT(sat, satcat)
+			sat.OrbitRegime = string(ClassifyOrbitRegime(sat))
+			satellites = append(satellites, sat)
+		}
+	}
+
 	// Sort by NORAD ID for consistent ordering
-	sort.Slice(satellites, func(i, j int) bool {
-		return satellites[i].NoradID < satellites[j].NoradID
+	slices.SortFunc(satellites, func(a, b *Satellite) int {
+		return cmp.Compare(a.NoradID, b.NoradID)
 	})
 
-	return satellites
+	return satellites, report
+}
+
+// applySATCAT copies the SATCAT entry's metadata and orbital parameters onto sat.
+func applySATCAT(sat *Satellite, satcat *SATCAT) {
+	sat.SATCAT = satcat
+	sat.Name = satcat.Name
+	sat.IntlID = satcat.IntlID
+	sat.ObjectType = satcat.ObjectType
+	sat.Owner = satcat.Owner
+	sat.LaunchDate = satcat.LaunchDate
+	sat.DecayDate = satcat.DecayDate
+	sat.LaunchSite = satcat.LaunchSite
+	sat.Period = satcat.Period
+	sat.Inclination = satcat.Inclination
+	sat.Apogee = satcat.Apogee
+	sat.Perigee = satcat.Perigee
+	sat.RCSSize = satcat.RCSSize
+}
+
+// tleHistory returns copies of the TLEs at indices ordered by epoch, oldest
+// first. TLEs with unparseable epochs sort first; ties keep feed order.
+func tleHistory(tles []TLE, indices []int) []TLE {
+	type entry struct {
+		tle   TLE
+		epoch time.Time
+	}
+	entries := make([]entry, len(indices))
+	for i, idx := range indices {
+		entries[i].tle = tles[idx]
+		entries[i].epoch, _ = tles[idx].GetEpoch()
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int { return a.epoch.Compare(b.epoch) })
+
+	history := make([]TLE, len(entries))
+	for i, e := range entries {
+		history[i] = e.tle
+	}
+	return history
 }
 
 // FetchAndMergeCatalog fetches TLE and SATCAT data from the client and merges them into a Catalog.
 // This is a convenience function that combines fetching and merging in a single operation.
 func FetchAndMergeCatalog(client *Client) (*Catalog, error) {
-	tles, err := client.FetchTLEs()
-	if err != nil {
-		return nil, err
+	return FetchAndMergeCatalogWith(client, MergeOptions{})
+}
+
+// FetchAndMergeCatalogWith fetches and merges like FetchAndMergeCatalog with
+// merge options.
+func FetchAndMergeCatalogWith(client *Client, opts MergeOptions) (*Catalog, error) {
+	return FetchAndMergeCatalogContext(context.Background(), client, opts)
+}
+
+// FetchAndMergeCatalogContext is FetchAndMergeCatalogWith with a context for
+// canceling the fetches. If the client makes conditional requests (see
+// Client.SetConditional), returns ErrNotModified when neither the TLE nor the
+// SATCAT feed has changed; if only one has, the other is downloaded anyway.
+func FetchAndMergeCatalogContext(ctx context.Context, client *Client, opts MergeOptions) (*Catalog, error) {
+	tles, tleErr := client.FetchTLEsContext(ctx)
+	if tleErr != nil && !errors.Is(tleErr, ErrNotModified) {
+		return nil, tleErr
 	}
 
-	satcats, err := client.FetchSATCATs()
+	satcats, err := client.FetchSATCATsContext(ctx)
+	if errors.Is(err, ErrNotModified) {
+		if tleErr != nil {
+			return nil, ErrNotModified
+		}
+		client.unconditional(client.satcatURLs)
+		satcats, err = client.FetchSATCATsContext(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	satellites := MergeSatelliteData(tles, satcats)
+	if tleErr != nil {
+		client.unconditional(client.tleURLs)
+		if tles, err = client.FetchTLEsContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return newFetchedCatalog(tles, satcats, opts), nil
+}
+
+// newFetchedCatalog merges freshly fetched feeds into a catalog stamped with
+// the current time.
+func newFetchedCatalog(tles []TLE, satcats []SATCAT, opts MergeOptions) *Catalog {
+	satellites, report := MergeSatelliteDataWith(tles, satcats, opts)
+
+	fetchedAt := time.Now()
+	for _, sat := range satellites {
+		sat.FetchedAt = fetchedAt
+		if sat.TLE != nil {
+			sat.TLEUpdatedAt = fetchedAt
+		}
+	}
+
+	return &Catalog{
+		Satellites:  satellites,
+		FetchedAt:   fetchedAt,
+		DroppedTLEs: report.DroppedTLEs,
+	}
+}
+
+// UpdateTLEs returns a copy of the catalog with its TLEs replaced by those in
+// newTLEs, matched by NORAD ID, without refetching SATCAT: names and other
+// metadata are kept. Updated satellites get a TLEUpdatedAt of now and, if they
+// keep a TLE history, the new TLE appended to it. NORAD IDs not in the catalog
+// are added with the 3LE name, and satellites with a TLE missing from newTLEs
+// are kept with MissingFromFeed set; SATCAT-only satellites, which never had a
+// TLE, are kept as they are. The catalog's FetchedAt becomes now, since its
+// TLEs are current. The input catalog is not modified.
+func UpdateTLEs(catalog *Catalog, newTLEs []TLE) *Catalog {
+	now := time.Now()
+
+	// The last TLE for each NORAD ID wins, as in MergeSatelliteData
+	latest := make(map[int]*TLE, len(newTLEs))
+	dropped := 0
+	for i := range newTLEs {
+		noradID, err := newTLEs[i].GetNoradIDErr()
+		if err != nil {
+			dropped++
+			continue
+		}
+		latest[noradID] = &newTLEs[i]
+	}
+
+	satellites := make([]*Satellite, 0, len(catalog.Satellites)+len(latest))
+	for _, old := range catalog.Satellites {
+		sat := *old
+		tle, ok := latest[sat.NoradID]
+		if !ok {
+			sat.MissingFromFeed = sat.TLE != nil
+			satellites = append(satellites, &sat)
+			continue
+		}
+		delete(latest, sat.NoradID)
+
+		if len(sat.TLEHistory) > 0 && (sat.TLE == nil || sat.TLE.Line1 != tle.Line1) {
+			sat.TLEHistory = append(slices.Clone(sat.TLEHistory), *tle)
+		}
+		if sat.SATCAT == nil && tle.Name != "" {
+			sat.Name = tle.Name // named by the 3LE without SATCAT, as when merging
+		}
+		setTLE(&sat, tle, now)
+		satellites = append(satellites, &sat)
+	}
+
+	for noradID, tle := range latest {
+		sat := &Satellite{NoradID: noradID, Name: tle.Name, FetchedAt: now}
+		setTLE(sat, tle, now)
+		satellites = append(satellites, sat)
+	}
+
+	slices.SortFunc(satellites, func(a, b *Satellite) int {
+		return cmp.Compare(a.NoradID, b.NoradID)
+	})
 
 	return &Catalog{
-		Satellites: satellites,
-		FetchedAt:  time.Now(),
-	}, nil
+		Satellites:  satellites,
+		FetchedAt:   now,
+		DroppedTLEs: dropped,
+	}
+}
+
+// setTLE gives sat a new TLE fetched at now and reclassifies its orbit regime,
+// which falls back to the TLE.
+func setTLE(sat *Satellite, tle *TLE, now time.Time) {
+	sat.TLE = tle
+	sat.TLEEpoch = time.Time{}
+	if epoch, err := tle.GetEpoch(); err == nil {
+		sat.TLEEpoch = epoch
+	}
+	sat.TLEUpdatedAt = now
+	sat.MissingFromFeed = false
+	sat.OrbitRegime = string(ClassifyOrbitRegime(sat))
+}
+
+// CatalogFetcher fetches the TLE and SATCAT feeds a catalog is merged from.
+// *Client implements it.
+type CatalogFetcher interface {
+	FetchTLEs() ([]TLE, error)
+	FetchSATCATs() ([]SATCAT, error)
+}
+
+// CatalogStorage loads and saves the catalog for LoadOrFetchCatalog.
+// *Storage implements it.
+type CatalogStorage interface {
+	Load() (*Catalog, error)
+	Save(catalog *Catalog) error
+	SaveSnapshot(catalog *Catalog) error
+}
+
+// LoadOrFetchCatalog loads the stored catalog, fetching and saving a new one
+// (and a snapshot of it with cfg.CatalogSnapshots) when it is missing or stale
+// and cfg.AutoFetch is enabled.
+// Reports whether a fetch happened. Returns a nil catalog without error if none
+// is stored and auto-fetch is disabled.
+func LoadOrFetchCatalog(store CatalogStorage, fetcher CatalogFetcher, cfg *Config) (*Catalog, bool, error) {
+	catalog, err := store.Load()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !cfg.AutoFetch || (catalog != nil && !cfg.IsCatalogStale(catalog)) {
+		return catalog, false, nil
+	}
+
+	tles, err := fetcher.FetchTLEs()
+	if err != nil {
+		return nil, false, err
+	}
+	satcats, err := fetcher.FetchSATCATs()
+	if err != nil {
+		return nil, false, err
+	}
+	fresh := newFetchedCatalog(tles, satcats, cfg.MergeOptions())
+	if err := store.Save(fresh); err != nil {
+		return nil, false, err
+	}
+	if cfg.CatalogSnapshots {
+		if err := store.SaveSnapshot(fresh); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return fresh, true, nil
+}
+
+// ResolveSatellite finds a single satellite by NORAD ID or name.
+// A numeric query is matched as a NORAD ID. Otherwise an exact case-insensitive
+// name match is preferred, falling back to a partial match that must be unique.
+func ResolveSatellite(satellites []*Satellite, query string) (*Satellite, error) {
+	return newSatelliteIndex(satellites).resolve(query)
+}
+
+// satelliteIndex indexes satellites by NORAD ID and lowercase name, keeping
+// the first satellite for each, so that many queries can be resolved against
+// a catalog without scanning it for each one.
+type satelliteIndex struct {
+	satellites []*Satellite
+	byID       map[int]*Satellite
+	byName     map[string]*Satellite
+}
+
+func newSatelliteIndex(satellites []*Satellite) *satelliteIndex {
+	idx := &satelliteIndex{
+		satellites: satellites,
+		byID:       make(map[int]*Satellite, len(satellites)),
+		byName:     make(map[string]*Satellite, len(satellites)),
+	}
+	for _, sat := range satellites {
+		if _, ok := idx.byID[sat.NoradID]; !ok {
+			idx.byID[sat.NoradID] = sat
+		}
+		name := strings.ToLower(sat.Name)
+		if _, ok := idx.byName[name]; !ok {
+			idx.byName[name] = sat
+		}
+	}
+	return idx
+}
+
+// resolve resolves a query as ResolveSatellite does.
+func (idx *satelliteIndex) resolve(query string) (*Satellite, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("empty satellite name or ID")
+	}
+
+	if id, err := strconv.Atoi(query); err == nil {
+		if sat, ok := idx.byID[id]; ok {
+			return sat, nil
+		}
+		return nil, fmt.Errorf("no satellite with NORAD ID %d", id)
+	}
+
+	if sat, ok := idx.byName[strings.ToLower(query)]; ok {
+		return sat, nil
+	}
+
+	matches := SearchSatellites(idx.satellites, SearchCriteria{Name: query})
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no satellite matching %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%q matches %d satellites; use a NORAD ID or the exact name", query, len(matches))
+	}
+}
+
+// ReadIDList reads satellite queries, one per line, for SelectSatellites.
+// Text after '#' is a comment; blank lines are skipped and surrounding
+// whitespace is trimmed.
+func ReadIDList(r io.Reader) ([]string, error) {
+	var queries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			queries = append(queries, line)
+		}
+	}
+	return queries, scanner.Err()
+}
+
+// SelectSatellites resolves each query as ResolveSatellite does and returns
+// the matched satellites in query order without duplicates, along with an
+// error for each query that matched no satellite or more than one.
+func SelectSatellites(satellites []*Satellite, queries []string) ([]*Satellite, []error) {
+	var selected []*Satellite
+	var errs []error
+	seen := make(map[int]bool, len(queries))
+	idx := newSatelliteIndex(satellites)
+	for _, query := range queries {
+		sat, err := idx.resolve(query)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !seen[sat.NoradID] {
+			seen[sat.NoradID] = true
+			selected = append(selected, sat)
+		}
+	}
+	return selected, errs
+}
+
+// RegimeDistribution reclassifies every satellite in the catalog with
+// ClassifyOrbitRegime and returns the number of satellites in each regime,
+// including RegimeUnknown. Stored OrbitRegime values are not modified.
+// Returns an empty map for a nil catalog.
+func RegimeDistribution(catalog *Catalog) map[OrbitRegime]int {
+	counts := make(map[OrbitRegime]int)
+	if catalog == nil {
+		return counts
+	}
+	for _, sat := range catalog.Satellites {
+		counts[ClassifyOrbitRegime(sat)]++
+	}
+	return counts
+}
+
+// Failure categories reported by PropagationFailures
+const (
+	FailureParse   = "parse"   // TLE could not be parsed
+	FailureDecayed = "decayed" // satellite is below the Earth's surface
+	FailureNaN     = "nan"     // propagation produced a non-finite state
+	FailureOther   = "other"   // any other SGP4 error
+)
+
+// FailureInfo describes a satellite that could not be propagated.
+type FailureInfo struct {
+	NoradID  int
+	Name     string
+	Category string // one of the Failure* categories
+	Err      error
+}
+
+// PropagateCatalog propagates every satellite with a TLE to t on a pool of
+// workers goroutines (GOMAXPROCS if workers <= 0) and returns the positions
+// keyed by NORAD ID. Satellites that fail to propagate are left out.
+func PropagateCatalog(sats []*Satellite, t time.Time, workers int) map[int]*SatellitePosition {
+	return propagateCatalog(sats, t, workers, newProgressCounter(len(sats), nil))
+}
+
+// propagateCatalog is PropagateCatalog with one counter step per satellite,
+// including those without a TLE.
+func propagateCatalog(sats []*Satellite, t time.Time, workers int, counter *progressCounter) map[int]*SatellitePosition {
+	results := make([]*SatellitePosition, len(sats))
+	forEachParallel(len(sats), workers, func(i int) {
+		if sats[i].TLE != nil {
+			// Failures leave a nil slot and are skipped below
+			results[i], _ = PropagateSatellite(sats[i].TLE, t)
+		}
+		counter.step()
+	})
+	counter.finish()
+
+	positions := make(map[int]*SatellitePosition, len(sats))
+	for i, pos := range results {
+		if pos != nil {
+			positions[sats[i].NoradID] = pos
+		}
+	}
+	return positions
+}
+
+// PropagationFailures propagates every satellite with a TLE to t and returns
+// those that fail, sorted by NORAD ID. Satellites are propagated in parallel.
+// If progress is non-nil it is called as satellites are processed.
+func PropagationFailures(catalog *Catalog, t time.Time, progress ProgressFunc) []FailureInfo {
+	var sats []*Satellite
+	for _, sat := range catalog.Satellites {
+		if sat.TLE != nil {
+			sats = append(sats, sat)
+		}
+	}
+	counter := newProgressCounter(len(sats), progress)
+
+	errs := make([]error, len(sats))
+	forEachParallel(len(sats), 0, func(i int) {
+		_, errs[i] = PropagateSatellite(sats[i].TLE, t)
+		counter.step()
+	})
+	counter.finish()
+
+	failures := make([]FailureInfo, 0)
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		category := FailureOther
+		switch {
+		case errors.Is(err, ErrTLEParse):
+			category = FailureParse
+		case errors.Is(err, ErrDecayed):
+			category = FailureDecayed
+		case errors.Is(err, ErrNaNState):
+			category = FailureNaN
+		}
+		failures = append(failures, FailureInfo{
+			NoradID:  sats[i].NoradID,
+			Name:     sats[i].Name,
+			Category: category,
+			Err:      err,
+		})
+	}
+
+	slices.SortFunc(failures, func(a, b FailureInfo) int {
+		return cmp.Compare(a.NoradID, b.NoradID)
+	})
+	return failures
+}
+
+// FreshnessReport summarizes how old the TLE epochs in a catalog are.
+type FreshnessReport struct {
+	Count  int           // satellites with a known TLE epoch
+	Newest time.Duration // age of the most recent TLE epoch
+	Median time.Duration // median TLE epoch age
+	Oldest time.Duration // age of the oldest TLE epoch
+}
+
+// CatalogFreshness reports the newest, median, and oldest TLE epoch age across
+// the catalog, measured from the current time.
+// Satellites without a parseable TLE epoch are ignored.
+func CatalogFreshness(catalog *Catalog) *FreshnessReport {
+	report := &FreshnessReport{}
+	if catalog == nil {
+		return report
+	}
+
+	now := time.Now()
+	ages := make([]time.Duration, 0, len(catalog.Satellites))
+	for _, sat := range catalog.Satellites {
+		epoch := sat.TLEEpoch
+		if epoch.IsZero() && sat.TLE != nil {
+			// Catalogs saved before epochs were stored
+			e, err := sat.TLE.GetEpoch()
+			if err != nil {
+				continue
+			}
+			epoch = e
+		}
+		if epoch.IsZero() {
+			continue
+		}
+		ages = append(ages, now.Sub(epoch))
+	}
+
+	if len(ages) == 0 {
+		return report
+	}
+
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+
+	report.Count = len(ages)
+	report.Newest = ages[0]
+	report.Oldest = ages[len(ages)-1]
+	mid := len(ages) / 2
+	if len(ages)%2 == 0 {
+		report.Median = (ages[mid-1] + ages[mid]) / 2
+	} else {
+		report.Median = ages[mid]
+	}
+
+	return report
+}
+
+// SetStats summarizes the orbits of a set of satellites, such as a search result.
+type SetStats struct {
+	Count   int                 `json:"count"`   // satellites in the set
+	Regimes map[OrbitRegime]int `json:"regimes"` // satellites per orbit regime, as ClassifyOrbitRegime
+	Types   map[string]int      `json:"types"`   // satellites per SATCAT object type ("" if unknown)
+
+	OrbitCount      int     `json:"orbitCount"`      // satellites with known orbital parameters
+	MeanInclination float64 `json:"meanInclination"` // degrees
+	MinInclination  float64 `json:"minInclination"`  // degrees
+	MaxInclination  float64 `json:"maxInclination"`  // degrees
+	MinPerigee      float64 `json:"minPerigee"`      // lowest perigee altitude, km
+	MaxApogee       float64 `json:"maxApogee"`       // highest apogee altitude, km
+	MeanPeriod      float64 `json:"meanPeriod"`      // minutes
+}
+
+// SetStatistics computes aggregate statistics over the given satellites.
+// Orbital parameters come from SATCAT when present and are otherwise derived
+// from the TLE mean elements; satellites with neither are counted in Count,
+// Regimes, and Types only.
+func SetStatistics(satellites []*Satellite) *SetStats {
+	stats := &SetStats{
+		Count:   len(satellites),
+		Regimes: make(map[OrbitRegime]int),
+		Types:   make(map[string]int),
+	}
+
+	var sumInclination, sumPeriod float64
+	for _, sat := range satellites {
+		stats.Regimes[ClassifyOrbitRegime(sat)]++
+		stats.Types[sat.ObjectType]++
+
+		apogee, perigee, period, inclination, ok := satelliteOrbit(sat)
+		if !ok {
+			continue
+		}
+
+		if stats.OrbitCount == 0 {
+			stats.MinInclination, stats.MaxInclination = inclination, inclination
+			stats.MinPerigee, stats.MaxApogee = perigee, apogee
+		}
+		stats.OrbitCount++
+		sumInclination += inclination
+		sumPeriod += period
+		stats.MinInclination = math.Min(stats.MinInclination, inclination)
+		stats.MaxInclination = math.Max(stats.MaxInclination, inclination)
+		stats.MinPerigee = math.Min(stats.MinPerigee, perigee)
+		stats.MaxApogee = math.Max(stats.MaxApogee, apogee)
+	}
+
+	if stats.OrbitCount > 0 {
+		stats.MeanInclination = sumInclination / float64(stats.OrbitCount)
+		stats.MeanPeriod = sumPeriod / float64(stats.OrbitCount)
+	}
+
+	return stats
+}
+
+// satelliteOrbit returns the satellite's apogee and perigee altitudes (km),
+// period (minutes), and inclination (degrees) from SATCAT when present, and
+// otherwise derived from the TLE mean elements. ok is false if neither is
+// available.
+func satelliteOrbit(sat *Satellite) (apogee, perigee, period, inclination float64, ok bool) {
+	if sat.Period > 0 {
+		return sat.Apogee, sat.Perigee, sat.Period, sat.Inclination, true
+	}
+	if sat.TLE == nil {
+		return 0, 0, 0, 0, false
+	}
+	return tleOrbit(sat.TLE)
+}
+
+// ShellOccupancy returns the satellites whose orbits pass through the altitude
+// shell centered on centerKm and widthKm thick, i.e. whose perigee-to-apogee
+// range overlaps it, sorted by NORAD ID. The number of objects in the shell is
+// the length of the result. Orbital parameters come from SATCAT when present
+// and are otherwise derived from the TLE, as SetStatistics; satellites with
+// neither are left out.
+func ShellOccupancy(satellites []*Satellite, centerKm, widthKm float64) []*Satellite {
+	low, high := centerKm-widthKm/2, centerKm+widthKm/2
+
+	occupants := make([]*Satellite, 0)
+	for _, sat := range satellites {
+		apogee, perigee, _, _, ok := satelliteOrbit(sat)
+		if !ok {
+			continue
+		}
+
+		if perigee <= high && apogee >= low {
+			occupants = append(occupants, sat)
+		}
+	}
+
+	sort.Slice(occupants, func(i, j int) bool {
+		return occupants[i].NoradID < occupants[j].NoradID
+	})
+	return occupants
 }
 
 // FilterSatellites filters satellites by NORAD ID and/or name.
@@ -143,11 +847,57 @@ func FilterSatellites(satellites []*Satellite, noradID int, name string) []*Sate
 	return filtered
 }
 
+// hasOrbitBounds reports whether any orbital parameter bound is set.
+func (c *SearchCriteria) hasOrbitBounds() bool {
+	return c.InclinationMin != nil || c.InclinationMax != nil ||
+		c.PeriodMin != nil || c.PeriodMax != nil ||
+		c.ApogeeMax != nil || c.PerigeeMin != nil
+}
+
+// matchesOrbit reports whether the satellite's orbit is within the bounds.
+func (c *SearchCriteria) matchesOrbit(sat *Satellite) bool {
+	apogee, perigee, period, inclination, ok := satelliteOrbit(sat)
+	if !ok {
+		return false
+	}
+	atLeast := func(v float64, min *float64) bool { return min == nil || v >= *min }
+	atMost := func(v float64, max *float64) bool { return max == nil || v <= *max }
+	return atLeast(inclination, c.InclinationMin) && atMost(inclination, c.InclinationMax) &&
+		atLeast(period, c.PeriodMin) && atMost(period, c.PeriodMax) &&
+		atMost(apogee, c.ApogeeMax) && atLeast(perigee, c.PerigeeMin)
+}
+
+// CompileNameRegex compiles a pattern for SearchCriteria.NameRegex. Matching
+// is case-insensitive unless the pattern sets (?-i).
+func CompileNameRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		// Report the error against the pattern as given, without the flag
+		if _, plainErr := regexp.Compile(pattern); plainErr != nil {
+			err = plainErr
+		}
+		return nil, fmt.Errorf("invalid name regex: %w", err)
+	}
+	return re, nil
+}
+
+// Validate checks that the criteria are usable: only one of Name and
+// NameRegex may be set.
+func (c *SearchCriteria) Validate() error {
+	if c.NameRegex != nil && c.Name != "" {
+		return fmt.Errorf("name and name regex are mutually exclusive")
+	}
+	return nil
+}
+
 // SearchSatellites performs multi-criteria search on satellites.
-// All criteria are optional - empty strings are ignored.
+// All criteria are optional - empty strings, zero values, and nil bounds are ignored.
 // Name, owner, and type use partial matching (case-insensitive).
 // Regime uses exact matching (case-insensitive).
-// Results are sorted by NORAD ID.
+// NameRegex matches anywhere in the name unless anchored.
+// The period band uses the SATCAT period, or the TLE mean motion when it is missing.
+// Launch filters exclude satellites whose launch date cannot be parsed.
+// Results are sorted by NORAD ID; use SortByFuzzyName to rank FuzzyName matches.
 func SearchSatellites(satellites []*Satellite, criteria SearchCriteria) []*Satellite {
 	results := make([]*Satellite, 0)
 
@@ -155,13 +905,49 @@ func SearchSatellites(satellites []*Satellite, criteria SearchCriteria) []*Satel
 	ownerUpper := strings.ToUpper(criteria.Owner)
 	typeLower := strings.ToLower(criteria.Type)
 	regimeUpper := strings.ToUpper(criteria.Regime)
+	intlUpper := strings.ToUpper(criteria.IntlID)
+	maxDistance := criteria.MaxDistance
+	if maxDistance <= 0 {
+		maxDistance = FuzzyMaxDistance(criteria.FuzzyName)
+	}
+
+	var idSet map[int]bool
+	if len(criteria.NoradIDs) > 0 {
+		idSet = make(map[int]bool, len(criteria.NoradIDs))
+		for _, id := range criteria.NoradIDs {
+			idSet[id] = true
+		}
+	}
 
 	for _, sat := range satellites {
+		// Restrict to the given NORAD IDs
+		if idSet != nil && !idSet[sat.NoradID] {
+			continue
+		}
+
+		// Filter by NORAD ID range
+		if criteria.NoradMin > 0 && sat.NoradID < criteria.NoradMin {
+			continue
+		}
+		if criteria.NoradMax > 0 && sat.NoradID > criteria.NoradMax {
+			continue
+		}
+
 		// Filter by name (partial match)
 		if criteria.Name != "" && !strings.Contains(strings.ToLower(sat.Name), nameLower) {
 			continue
 		}
 
+		// Filter by name pattern
+		if criteria.NameRegex != nil && !criteria.NameRegex.MatchString(sat.Name) {
+			continue
+		}
+
+		// Filter by name (approximate match)
+		if criteria.FuzzyName != "" && FuzzyNameDistance(criteria.FuzzyName, sat.Name) > maxDistance {
+			continue
+		}
+
 		// Filter by owner (partial match)
 		if criteria.Owner != "" && !strings.Contains(strings.ToUpper(sat.Owner), ownerUpper) {
 			continue
@@ -172,11 +958,48 @@ func SearchSatellites(satellites []*Satellite, criteria SearchCriteria) []*Satel
 			continue
 		}
 
+		// Filter by international designator (partial match)
+		if criteria.IntlID != "" && !strings.Contains(strings.ToUpper(sat.IntlID), intlUpper) {
+			continue
+		}
+
 		// Filter by orbital regime (exact match)
 		if criteria.Regime != "" && strings.ToUpper(sat.OrbitRegime) != regimeUpper {
 			continue
 		}
 
+		// Filter by orbit type (exact match)
+		if criteria.OrbitType != "" && !matchesOrbitType(sat, criteria.OrbitType) {
+			continue
+		}
+
+		// Filter by orbital period band
+		if criteria.PeriodCenter > 0 {
+			period := sat.OrbitalPeriod()
+			if period == 0 || math.Abs(period-criteria.PeriodCenter) > criteria.PeriodTolerance {
+				continue
+			}
+		}
+
+		// Filter by orbital parameter bounds
+		if criteria.hasOrbitBounds() && !criteria.matchesOrbit(sat) {
+			continue
+		}
+
+		// Filter by launch date
+		if criteria.LaunchYear != 0 || !criteria.LaunchedSince.IsZero() {
+			launch, ok := ParseSATCATDate(sat.LaunchDate)
+			if !ok {
+				continue
+			}
+			if criteria.LaunchYear != 0 && launch.Year() != criteria.LaunchYear {
+				continue
+			}
+			if !criteria.LaunchedSince.IsZero() && launch.Before(criteria.LaunchedSince) {
+				continue
+			}
+		}
+
 		results = append(results, sat)
 	}
 
@@ -189,26 +1012,44 @@ func SearchSatellites(satellites []*Satellite, criteria SearchCriteria) []*Satel
 }
 
 // FindVisibleSatellites finds satellites currently visible from the observer's location.
-// Applies search criteria first, then filters by elevation bounds.
+// Applies search criteria first, then propagates the candidates in parallel
+// (see PropagateCatalog) and filters by elevation bounds.
 // Returns satellites with their observation angles, sorted by elevation (highest first).
+// If progress is non-nil it is called as candidates are processed.
+// Returns ErrObserverUnset if observer is nil, or is the zero value and
+// criteria.AllowNullIsland is not set.
 func FindVisibleSatellites(
 	satellites []*Satellite,
 	observer *ObserverPosition,
 	t time.Time,
 	criteria VisibilityCriteria,
+	progress ProgressFunc,
 ) ([]*VisibleSatellite, error) {
+	if observer == nil || (observer.IsZero() && !criteria.AllowNullIsland) {
+		return nil, ErrObserverUnset
+	}
+	if err := criteria.SearchCriteria.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Apply search filters first
 	candidates := SearchSatellites(satellites, criteria.SearchCriteria)
 
-	visible := make([]*VisibleSatellite, 0)
+	// Nothing to report while the sky is too bright
+	if criteria.DarkOnly && !IsDark(observer, t, criteria.DarkSunElevation) {
+		candidates = nil
+	}
+
+	positions := propagateCatalog(candidates, t, criteria.Workers, newProgressCounter(len(candidates), progress))
 
+	visible := make([]*VisibleSatellite, 0)
 	for _, sat := range candidates {
-		if sat.TLE == nil {
+		pos, ok := positions[sat.NoradID]
+		if !ok {
 			continue
 		}
 
-		pos, err := PropagateSatellite(sat.TLE, t)
-		if err != nil {
+		if criteria.SunlitOnly && !IsSunlit(pos, t) {
 			continue
 		}
 
@@ -230,3 +1071,18 @@ func FindVisibleSatellites(
 
 	return visible, nil
 }
+
+// SatellitesNearZenith returns the satellites within maxZenithAngle degrees of
+// the observer's zenith at t, closest to straight overhead first. The zenith
+// angle of each is 90 minus its elevation.
+func SatellitesNearZenith(satellites []*Satellite, observer *ObserverPosition, t time.Time, maxZenithAngle float64) ([]*VisibleSatellite, error) {
+	if maxZenithAngle < 0 || maxZenithAngle > 90 {
+		return nil, fmt.Errorf("zenith angle must be between 0 and 90 degrees: %v", maxZenithAngle)
+	}
+
+	// Elevation order is zenith-proximity order
+	return FindVisibleSatellites(satellites, observer, t, VisibilityCriteria{
+		MinElevation: 90 - maxZenithAngle,
+		MaxElevation: 90,
+	}, nil)
+}
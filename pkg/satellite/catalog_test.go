@@ -0,0 +1,1037 @@
+package satellite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// searchIDs runs SearchSatellites and returns the NORAD IDs it matched.
+func searchIDs(t *testing.T, satellites []*Satellite, criteria SearchCriteria) []int {
+	t.Helper()
+	var ids []int
+	for _, sat := range SearchSatellites(satellites, criteria) {
+		ids = append(ids, sat.NoradID)
+	}
+	return ids
+}
+
+func TestSearchSatellitesPeriodBand(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 25544, Name: "ISS (ZARYA)", Period: 92.9},
+		{NoradID: 28129, Name: "NAVSTAR 53", Period: 718.0},
+		{NoradID: 40296, Name: "MOLNIYA 2-10", Period: 717.2},
+		{NoradID: 41866, Name: "GOES 16", Period: 1436.1},
+		{NoradID: 44444, Name: "NO PERIOD"},
+		// Period from the TLE mean motion: 2.0056 rev/day is ~718 minutes
+		{NoradID: 28474, Name: "NAVSTAR 54", TLE: makeTLE(28474, testEpoch, 55, 30, 0.001, 10, 350, 2.0056)},
+	}
+
+	got := searchIDs(t, satellites, SearchCriteria{PeriodCenter: 720, PeriodTolerance: 15})
+	if want := []int{28129, 28474, 40296}; !slices.Equal(got, want) {
+		t.Errorf("12h band matched %v, want %v", got, want)
+	}
+
+	got = searchIDs(t, satellites, SearchCriteria{PeriodCenter: 718, PeriodTolerance: 0.5})
+	if want := []int{28129, 28474}; !slices.Equal(got, want) {
+		t.Errorf("718 ± 0.5 min band matched %v, want %v", got, want)
+	}
+}
+
+func TestSearchSatellitesOrbitBands(t *testing.T) {
+	satcat := func(id int, incl, period, apogee, perigee float64) *Satellite {
+		return &Satellite{NoradID: id, Inclination: incl, Period: period, Apogee: apogee, Perigee: perigee}
+	}
+	satellites := []*Satellite{
+		satcat(1, 97.4, 94.6, 505, 495),       // sun-synchronous
+		satcat(2, 98.7, 101.2, 830, 815),      // sun-synchronous, higher
+		satcat(3, 99.0, 100.5, 790, 780),      // on the upper inclination bound
+		satcat(4, 51.6, 92.9, 422, 418),       // ISS
+		satcat(5, 0.05, 1436.1, 35800, 35770), // GEO, zero-ish inclination
+		satcat(6, 0, 0, 0, 0),                 // no SATCAT orbit and no TLE
+		// No SATCAT orbit: from the TLE, 98° at 14.5 rev/day is ~710 km
+		testSatellite("SSO TLE", makeTLE(7, testEpoch, 98, 0, 0.001, 0, 0, 14.5)),
+	}
+	ptr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		criteria SearchCriteria
+		want     []int
+	}{
+		{"sun-synchronous", SearchCriteria{InclinationMin: ptr(97), InclinationMax: ptr(99)}, []int{1, 2, 3, 7}},
+		{"zero bound is set", SearchCriteria{InclinationMax: ptr(0.1)}, []int{5}},
+		{"LEO band", SearchCriteria{PerigeeMin: ptr(400), ApogeeMax: ptr(600)}, []int{1, 4}},
+		{"period band", SearchCriteria{PeriodMin: ptr(95), PeriodMax: ptr(100.5)}, []int{3, 7}},
+		{"SSO below 800 km", SearchCriteria{InclinationMin: ptr(97), ApogeeMax: ptr(800)}, []int{1, 3, 7}},
+		{"unbounded", SearchCriteria{}, []int{1, 2, 3, 4, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		if got := searchIDs(t, satellites, tt.criteria); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchSatellitesNoradRangeAndIntlID(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 25544, Name: "ISS (ZARYA)", IntlID: "1998-067A"},
+		{NoradID: 25545, Name: "ISS DEB", IntlID: "1998-067B"},
+		{NoradID: 49044, Name: "ISS (NAUKA)", IntlID: "1998-067SJ"},
+		{NoradID: 25546, Name: "SL-12 R/B", IntlID: "1998-068A"},
+		{NoradID: 25547, Name: "NO DESIGNATOR"},
+		{NoradID: 27067, Name: "DESIGNATOR ELSEWHERE", IntlID: "2001-067A"},
+	}
+
+	tests := []struct {
+		name     string
+		criteria SearchCriteria
+		want     []int
+	}{
+		{"range", SearchCriteria{NoradMin: 25544, NoradMax: 25546}, []int{25544, 25545, 25546}},
+		{"range bounds inclusive", SearchCriteria{NoradMin: 25545, NoradMax: 25545}, []int{25545}},
+		{"min only", SearchCriteria{NoradMin: 25547}, []int{25547, 27067, 49044}},
+		{"max only", SearchCriteria{NoradMax: 25545}, []int{25544, 25545}},
+		{"empty range", SearchCriteria{NoradMin: 30000, NoradMax: 20000}, nil},
+		{"launch", SearchCriteria{IntlID: "1998-067"}, []int{25544, 25545, 49044}},
+		{"launch, lowercase piece", SearchCriteria{IntlID: "1998-067s"}, []int{49044}},
+		{"launch within range", SearchCriteria{IntlID: "1998-067", NoradMax: 30000}, []int{25544, 25545}},
+		{"year", SearchCriteria{IntlID: "1998-"}, []int{25544, 25545, 25546, 49044}},
+		{"unset", SearchCriteria{}, []int{25544, 25545, 25546, 25547, 27067, 49044}},
+	}
+	for _, tt := range tests {
+		if got := searchIDs(t, satellites, tt.criteria); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogFreshnessMedian(t *testing.T) {
+	now := time.Now()
+	ageSat := func(id int, age time.Duration) *Satellite {
+		return &Satellite{NoradID: id, TLEEpoch: now.Add(-age)}
+	}
+
+	catalog := &Catalog{Satellites: []*Satellite{
+		ageSat(1, 10*24*time.Hour),
+		ageSat(2, time.Hour),
+		ageSat(3, 3*24*time.Hour),
+		ageSat(4, 2*24*time.Hour),
+		{NoradID: 5}, // no epoch and no TLE: ignored
+	}}
+	report := CatalogFreshness(catalog)
+	if report.Count != 4 {
+		t.Fatalf("Count = %d, want 4", report.Count)
+	}
+	assertNear(t, "newest (h)", report.Newest.Hours(), 1, 0.01)
+	assertNear(t, "median (h)", report.Median.Hours(), 60, 0.01) // mean of 2 and 3 days
+	assertNear(t, "oldest (h)", report.Oldest.Hours(), 240, 0.01)
+
+	// Odd count, with one epoch read from the TLE of a legacy catalog entry
+	legacy := &Satellite{NoradID: 6, TLE: makeTLE(6, now.Add(-5*24*time.Hour), 51.6, 0, 0.001, 0, 0, 15.5)}
+	catalog.Satellites = append(catalog.Satellites, legacy)
+	report = CatalogFreshness(catalog)
+	if report.Count != 5 {
+		t.Fatalf("Count = %d, want 5", report.Count)
+	}
+	assertNear(t, "median (h)", report.Median.Hours(), 72, 0.01)
+
+	if report := CatalogFreshness(nil); report.Count != 0 {
+		t.Errorf("CatalogFreshness(nil).Count = %d, want 0", report.Count)
+	}
+}
+
+// memoryStore is a CatalogStorage that keeps the catalog in memory.
+type memoryStore struct {
+	catalog   *Catalog
+	saves     int
+	snapshots int
+}
+
+func (m *memoryStore) Load() (*Catalog, error)     { return m.catalog, nil }
+func (m *memoryStore) Save(catalog *Catalog) error { m.catalog = catalog; m.saves++; return nil }
+func (m *memoryStore) SaveSnapshot(*Catalog) error { m.snapshots++; return nil }
+
+// fakeFetcher is a CatalogFetcher serving fixed feeds.
+type fakeFetcher struct {
+	tles    []TLE
+	satcats []SATCAT
+	err     error
+	calls   int
+}
+
+func (f *fakeFetcher) FetchTLEs() ([]TLE, error) {
+	f.calls++
+	return f.tles, f.err
+}
+
+func (f *fakeFetcher) FetchSATCATs() ([]SATCAT, error) { return f.satcats, f.err }
+
+func TestLoadOrFetchCatalog(t *testing.T) {
+	feeds := func() *fakeFetcher {
+		return &fakeFetcher{
+			tles:    []TLE{*issTLE(), *geoTLE()},
+			satcats: []SATCAT{{NoradID: 25544, Name: "ISS (ZARYA)"}},
+		}
+	}
+	cfg := &Config{AutoFetch: true, MaxCatalogAge: 24, CatalogSnapshots: true}
+
+	// A missing catalog is fetched, saved, and snapshotted
+	store, fetcher := &memoryStore{}, feeds()
+	catalog, fetched, err := LoadOrFetchCatalog(store, fetcher, cfg)
+	if err != nil || !fetched {
+		t.Fatalf("LoadOrFetchCatalog = fetched %v, %v; want a fetch", fetched, err)
+	}
+	if len(catalog.Satellites) != 2 || catalog.Satellites[0].Name != "ISS (ZARYA)" {
+		t.Errorf("fetched catalog has %d satellites, want ISS and the GEO merged", len(catalog.Satellites))
+	}
+	if store.catalog != catalog || store.saves != 1 || store.snapshots != 1 {
+		t.Errorf("store saved %d times and snapshotted %d times, want once each", store.saves, store.snapshots)
+	}
+
+	// A fresh catalog is used as is
+	fetcher = feeds()
+	if _, fetched, err := LoadOrFetchCatalog(store, fetcher, cfg); err != nil || fetched || fetcher.calls != 0 {
+		t.Errorf("fresh catalog: fetched %v after %d calls (%v), want no fetch", fetched, fetcher.calls, err)
+	}
+
+	// A stale one is replaced
+	store.catalog.FetchedAt = time.Now().Add(-48 * time.Hour)
+	if _, fetched, err := LoadOrFetchCatalog(store, fetcher, cfg); err != nil || !fetched || store.saves != 2 {
+		t.Errorf("stale catalog: fetched %v with %d saves (%v), want a second fetch", fetched, store.saves, err)
+	}
+
+	// Without auto-fetch nothing is fetched, even with no catalog
+	fetcher = feeds()
+	noFetch := &Config{MaxCatalogAge: 24}
+	if catalog, fetched, err := LoadOrFetchCatalog(&memoryStore{}, fetcher, noFetch); catalog != nil || fetched || err != nil || fetcher.calls != 0 {
+		t.Errorf("auto_fetch off: got %v, %v, %v after %d calls; want nil, false, nil", catalog, fetched, err, fetcher.calls)
+	}
+
+	// A failed fetch saves nothing
+	store = &memoryStore{}
+	failing := &fakeFetcher{err: errors.New("feed down")}
+	if _, _, err := LoadOrFetchCatalog(store, failing, cfg); err == nil || store.saves != 0 {
+		t.Errorf("failed fetch: err %v with %d saves, want an error and no save", err, store.saves)
+	}
+}
+
+func TestRegimeDistribution(t *testing.T) {
+	// A TLE-only Molniya orbit: 63.4°, e 0.74, two revolutions a day
+	molniya := testSatellite("MOLNIYA", makeTLE(20000, testEpoch, 63.4, 0, 0.74, 270, 0, 2.006))
+	molniya.OrbitRegime = string(RegimeUnknown)
+
+	catalog := &Catalog{Satellites: []*Satellite{
+		testSatellite("ISS", issTLE()),
+		testSatellite("GEO", geoTLE()),
+		testSatellite("GPS", makeTLE(28474, testEpoch, 55, 0, 0.01, 0, 0, 2.0056)),
+		molniya,
+		{NoradID: 99999, Name: "SATCAT ONLY"},
+		{NoradID: 99998, Name: "SATCAT LEO", Apogee: 550, Perigee: 540, Period: 95.6, Inclination: 53},
+	}}
+
+	want := map[OrbitRegime]int{RegimeLEO: 2, RegimeGEO: 1, RegimeMEO: 1, RegimeHEO: 1, RegimeUnknown: 1}
+	got := RegimeDistribution(catalog)
+	for regime, n := range want {
+		if got[regime] != n {
+			t.Errorf("%s count = %d, want %d", regime, got[regime], n)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("RegimeDistribution = %v, want %v", got, want)
+	}
+	if molniya.OrbitRegime != string(RegimeUnknown) {
+		t.Errorf("stored regime changed to %s", molniya.OrbitRegime)
+	}
+
+	if got := RegimeDistribution(nil); got == nil || len(got) != 0 {
+		t.Errorf("RegimeDistribution(nil) = %v, want an empty map", got)
+	}
+}
+
+// syntheticFeed returns n TLEs across LEO, MEO and GEO with SATCAT entries
+// for every other one, plus a superseded duplicate of every tenth TLE and an
+// unparseable TLE every hundredth.
+func syntheticFeed(n int) ([]TLE, []SATCAT) {
+	motions := []float64{15.5, 2.0, 1.00273791, 14.2}
+	var tles []TLE
+	var satcats []SATCAT
+	for i := 1; i <= n; i++ {
+		epoch := testEpoch.Add(time.Duration(i) * time.Minute)
+		tle := makeTLE(i, epoch, float64(i%180), float64(i%360), 0.001, 0, 0, motions[i%len(motions)])
+		if i%10 == 0 {
+			stale := makeTLE(i, epoch.Add(-24*time.Hour), 10, 10, 0.001, 0, 0, 15)
+			tles = append(tles, *stale)
+		}
+		if i%100 == 0 {
+			bad := *tle
+			bad.Line1 = "1 ????" + bad.Line1[6:]
+			tles = append(tles, bad)
+		}
+		tles = append(tles, *tle)
+		if i%2 == 0 {
+			satcats = append(satcats, SATCAT{NoradID: i, Name: fmt.Sprintf("SAT-%d", i)})
+		}
+	}
+	return tles, satcats
+}
+
+func TestMergeSatelliteDataOutput(t *testing.T) {
+	tles, satcats := syntheticFeed(1000)
+	sats, report := MergeSatelliteDataReport(tles, satcats)
+
+	if len(sats) != 1000 || report.DroppedTLEs != 10 {
+		t.Fatalf("merged %d satellites and dropped %d TLEs, want 1000 and 10", len(sats), report.DroppedTLEs)
+	}
+	for i, sat := range sats {
+		if sat.NoradID != i+1 {
+			t.Fatalf("satellite %d has NORAD ID %d, want sorted IDs", i, sat.NoradID)
+		}
+
+		// The newest TLE wins, and the cached elements match a fresh parse
+		want := makeTLE(sat.NoradID, testEpoch.Add(time.Duration(sat.NoradID)*time.Minute),
+			float64(sat.NoradID%180), float64(sat.NoradID%360), 0.001, 0, 0, []float64{15.5, 2.0, 1.00273791, 14.2}[sat.NoradID%4])
+		if sat.TLE.Line2 != want.Line2 {
+			t.Fatalf("NORAD %d kept line 2 %q, want %q", sat.NoradID, sat.TLE.Line2, want.Line2)
+		}
+		if !sat.TLE.parsed {
+			t.Fatalf("NORAD %d elements not cached by the merge", sat.NoradID)
+		}
+		cached, _ := sat.TLE.elements()
+		fresh, err := sat.TLE.parseElements()
+		if err != nil || *cached != *fresh {
+			t.Fatalf("NORAD %d cached elements %+v, parsed %+v (%v)", sat.NoradID, cached, fresh, err)
+		}
+
+		uncached := &Satellite{TLE: &TLE{Line1: sat.TLE.Line1, Line2: sat.TLE.Line2}}
+		if regime := string(ClassifyOrbitRegime(uncached)); sat.OrbitRegime != regime {
+			t.Fatalf("NORAD %d regime %s, want %s", sat.NoradID, sat.OrbitRegime, regime)
+		}
+		if wantName := fmt.Sprintf("SAT-%d", sat.NoradID); (sat.NoradID%2 == 0) != (sat.Name == wantName) {
+			t.Fatalf("NORAD %d named %q", sat.NoradID, sat.Name)
+		}
+	}
+}
+
+func TestMergeSatelliteDataCatalogNumberZero(t *testing.T) {
+	bad := *issTLE()
+	bad.Line1 = "1 ????" + bad.Line1[6:]
+	zero := makeTLE(0, testEpoch, 51.6, 0, 0.001, 0, 0, 15.5)
+
+	// An unparseable TLE must not be taken for, or displace, catalog number 0
+	for _, tles := range [][]TLE{{bad, *zero}, {*zero, bad}, {bad}} {
+		sats, report := MergeSatelliteDataReport(tles, []SATCAT{{NoradID: 0, Name: "ZERO"}})
+		if report.DroppedTLEs != 1 {
+			t.Errorf("dropped %d TLEs, want the unparseable one", report.DroppedTLEs)
+		}
+		wantZero := len(tles) == 2
+		if got := len(sats) == 1 && sats[0].TLE.Line1 == zero.Line1 && sats[0].Name == "ZERO"; got != wantZero {
+			t.Errorf("merge of %d TLEs = %+v, want catalog number 0: %v", len(tles), sats, wantZero)
+		}
+		if !wantZero && len(sats) != 0 {
+			t.Errorf("unparseable TLE merged as %+v", sats[0])
+		}
+	}
+}
+
+func TestMergeSatelliteDataSATCATOnly(t *testing.T) {
+	tles := []TLE{*issTLE(), *geoTLE()}
+	tles[1].Name = "GEO SAT"
+	satcats := []SATCAT{
+		{NoradID: 25544, Name: "ISS (ZARYA)", Owner: "ISS", Period: 92.9, Inclination: 51.6, Apogee: 423, Perigee: 416},
+		{NoradID: 5, Name: "VANGUARD 1", Owner: "US", Period: 132.7, Inclination: 34.2, Apogee: 3834, Perigee: 654},
+		{NoradID: 12345, Name: "DECAYED OLD", DecayDate: "1990-01-01"},
+		{NoradID: 12345, Name: "DECAYED", Owner: "CIS", DecayDate: "1990-01-01"},
+	}
+
+	// By default only satellites with a TLE are kept
+	sats, _ := MergeSatelliteDataWith(tles, satcats, MergeOptions{})
+	if got := searchIDs(t, sats, SearchCriteria{}); !slices.Equal(got, []int{25544, 40000}) {
+		t.Fatalf("default merge = %v, want [25544 40000]", got)
+	}
+
+	sats, _ = MergeSatelliteDataWith(tles, satcats, MergeOptions{IncludeSATCATOnly: true})
+	if got := searchIDs(t, sats, SearchCriteria{}); !slices.Equal(got, []int{5, 12345, 25544, 40000}) {
+		t.Fatalf("merge with SATCAT-only entries = %v, want [5 12345 25544 40000]", got)
+	}
+	byID := make(map[int]*Satellite)
+	for _, sat := range sats {
+		byID[sat.NoradID] = sat
+	}
+
+	// Merged: the TLE with the SATCAT metadata
+	if iss := byID[25544]; iss.TLE == nil || iss.TLE.Line1 != tles[0].Line1 || iss.Name != "ISS (ZARYA)" || iss.Owner != "ISS" || iss.SATCAT == nil {
+		t.Errorf("merged ISS = %+v, want its TLE and SATCAT metadata", iss)
+	}
+	// TLE-only: named from the 3LE, orbit from the TLE
+	if geo := byID[40000]; geo.TLE == nil || geo.SATCAT != nil || geo.Name != "GEO SAT" || geo.OrbitRegime != string(RegimeGEO) {
+		t.Errorf("TLE-only GEO = %+v, want its TLE, 3LE name and GEO regime", geo)
+	}
+	// SATCAT-only: metadata without a TLE, the last duplicate winning
+	vanguard, decayed := byID[5], byID[12345]
+	if vanguard.TLE != nil || vanguard.Name != "VANGUARD 1" || vanguard.Owner != "US" || vanguard.OrbitRegime != string(RegimeMEO) {
+		t.Errorf("SATCAT-only Vanguard = %+v, want SATCAT metadata, no TLE and the MEO regime", vanguard)
+	}
+	if decayed.TLE != nil || decayed.Name != "DECAYED" || decayed.Owner != "CIS" || decayed.DecayDate != "1990-01-01" {
+		t.Errorf("SATCAT-only decayed object = %+v, want the last SATCAT entry", decayed)
+	}
+
+	// SATCAT-only satellites can be searched but are not propagated
+	if got := searchIDs(t, sats, SearchCriteria{Name: "vanguard"}); !slices.Equal(got, []int{5}) {
+		t.Errorf("search for vanguard = %v, want [5]", got)
+	}
+	if got := searchIDs(t, sats, SearchCriteria{Owner: "CIS"}); !slices.Equal(got, []int{12345}) {
+		t.Errorf("search by owner CIS = %v, want [12345]", got)
+	}
+	positions := PropagateCatalog(sats, testEpoch, 0)
+	if _, ok := positions[5]; ok || len(positions) != 2 {
+		t.Errorf("propagated %d satellites, want the 2 with TLEs", len(positions))
+	}
+}
+
+func TestUpdateTLEsPreservesMetadata(t *testing.T) {
+	iss, geo := issTLE(), geoTLE()
+	satcats := []SATCAT{
+		{NoradID: 25544, Name: "ISS (ZARYA)", IntlID: "1998-067A", Owner: "ISS", ObjectType: "PAYLOAD",
+			LaunchDate: "1998-11-20", LaunchSite: "TYMSC", Period: 92.9, Inclination: 51.6, Apogee: 423, Perigee: 416, RCSSize: "LARGE"},
+		{NoradID: 40000, Name: "GEO SAT", Owner: "US", Period: 1436, Inclination: 0.02, Apogee: 35790, Perigee: 35780},
+		{NoradID: 12345, Name: "DECAYED", Owner: "CIS", DecayDate: "1990-01-01"},
+	}
+	olderISS := makeTLE(25544, testEpoch.Add(-6*time.Hour), 51.64, 202, 0.0005, 90, 270, 15.5)
+	sats, _ := MergeSatelliteDataWith([]TLE{*olderISS, *iss, *geo}, satcats, MergeOptions{TLEHistory: true, IncludeSATCATOnly: true})
+	catalog := &Catalog{Satellites: sats, FetchedAt: testEpoch}
+	before := catalogJSON(t, catalog)
+
+	// A new ISS TLE, the GEO TLE unchanged, and a satellite new to the catalog
+	newISS := makeTLE(25544, testEpoch.Add(6*time.Hour), 51.64, 198, 0.0005, 90, 270, 15.5)
+	newISS.Name = "ISS FROM 3LE"
+	added := makeTLE(30000, testEpoch, 98, 10, 0.001, 0, 0, 14.5)
+	added.Name = "NEW SAT"
+	updated := UpdateTLEs(catalog, []TLE{*newISS, *geo, *added})
+
+	if catalogJSON(t, catalog) != before {
+		t.Error("UpdateTLEs modified its input catalog")
+	}
+	if got := searchIDs(t, updated.Satellites, SearchCriteria{}); !slices.Equal(got, []int{12345, 25544, 30000, 40000}) {
+		t.Fatalf("updated catalog = %v, want [12345 25544 30000 40000]", got)
+	}
+	byID := make(map[int]*Satellite)
+	for _, sat := range updated.Satellites {
+		byID[sat.NoradID] = sat
+	}
+
+	// SATCAT metadata survives a TLE-only update; the 3LE name does not replace it
+	issSat := byID[25544]
+	if issSat.TLE.Line1 != newISS.Line1 || !issSat.TLEEpoch.Equal(testEpoch.Add(6*time.Hour)) {
+		t.Errorf("ISS TLE %q epoch %v, want the new TLE", issSat.TLE.Line1, issSat.TLEEpoch)
+	}
+	if issSat.Name != "ISS (ZARYA)" || issSat.IntlID != "1998-067A" || issSat.Owner != "ISS" || issSat.ObjectType != "PAYLOAD" ||
+		issSat.LaunchDate != "1998-11-20" || issSat.LaunchSite != "TYMSC" || issSat.Period != 92.9 || issSat.Apogee != 423 ||
+		issSat.RCSSize != "LARGE" || issSat.SATCAT == nil || issSat.SATCAT.Name != "ISS (ZARYA)" {
+		t.Errorf("ISS metadata not preserved: %+v", issSat)
+	}
+	if len(issSat.TLEHistory) != 3 || issSat.TLEHistory[2].Line1 != newISS.Line1 {
+		t.Errorf("ISS history has %d TLEs, want the new one appended", len(issSat.TLEHistory))
+	}
+	if issSat.MissingFromFeed || issSat.OrbitRegime != string(RegimeLEO) {
+		t.Errorf("ISS missing %v, regime %s; want present and LEO", issSat.MissingFromFeed, issSat.OrbitRegime)
+	}
+
+	// An unchanged TLE is kept as is
+	if geoSat := byID[40000]; geoSat.TLE.Line1 != geo.Line1 || len(geoSat.TLEHistory) != 0 || geoSat.Owner != "US" || geoSat.MissingFromFeed {
+		t.Errorf("GEO after an unchanged TLE: %d history entries, owner %q", len(geoSat.TLEHistory), geoSat.Owner)
+	}
+	// A new satellite takes the 3LE name
+	if newSat := byID[30000]; newSat.Name != "NEW SAT" || newSat.TLE == nil || newSat.SATCAT != nil {
+		t.Errorf("new satellite = %+v, want NEW SAT with its TLE", newSat)
+	}
+	// A SATCAT-only satellite is not in any TLE feed, and not flagged missing
+	if decayed := byID[12345]; decayed.TLE != nil || decayed.MissingFromFeed || decayed.Owner != "CIS" || decayed.DecayDate != "1990-01-01" {
+		t.Errorf("SATCAT-only satellite = %+v, want it kept unflagged", decayed)
+	}
+
+	// A satellite with a TLE that drops out of the feed keeps it, flagged
+	again := UpdateTLEs(updated, []TLE{*geo})
+	for _, sat := range again.Satellites {
+		wantMissing := sat.NoradID == 25544 || sat.NoradID == 30000
+		if sat.MissingFromFeed != wantMissing {
+			t.Errorf("NORAD %d MissingFromFeed = %v, want %v", sat.NoradID, sat.MissingFromFeed, wantMissing)
+		}
+		if sat.NoradID == 25544 && (sat.TLE == nil || sat.TLE.Line1 != newISS.Line1 || sat.Name != "ISS (ZARYA)") {
+			t.Errorf("missing ISS = %+v, want its last TLE and name kept", sat)
+		}
+	}
+}
+
+func BenchmarkMergeSatelliteData(b *testing.B) {
+	tles, satcats := syntheticFeed(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Copy the TLEs so every iteration starts with an empty element cache
+		fresh := slices.Clone(tles)
+		MergeSatelliteData(fresh, satcats)
+	}
+}
+
+func TestPropagationFailures(t *testing.T) {
+	badLine2 := *makeTLE(90001, testEpoch, 51.6, 0, 0.001, 0, 0, 15.5)
+	badLine2.Line2 = badLine2.Line2[:10] + "X" + badLine2.Line2[11:]
+
+	catalog := &Catalog{Satellites: []*Satellite{
+		testSatellite("ISS", issTLE()),
+		testSatellite("GEO", geoTLE()),
+		{NoradID: 90000, Name: "SATCAT ONLY"},
+		testSatellite("BAD LINE 2", &badLine2),
+		// Perigee 200 km below the surface
+		testSatellite("DECAYED", makeTLE(90002, testEpoch, 51.6, 0, 0.15, 0, 0, 14)),
+		// Zero mean motion leaves SGP4 dividing by zero
+		testSatellite("NO MOTION", makeTLE(90003, testEpoch, 51.6, 0, 0.001, 0, 0, 0)),
+	}}
+
+	var progress float64
+	failures := PropagationFailures(catalog, testEpoch, func(fraction float64) { progress = fraction })
+
+	want := []struct {
+		noradID  int
+		category string
+		err      error
+	}{
+		{90001, FailureParse, ErrTLEParse},
+		{90002, FailureDecayed, ErrDecayed},
+		{90003, FailureNaN, ErrNaNState},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("got %d failures %+v, want %d", len(failures), failures, len(want))
+	}
+	for i, w := range want {
+		f := failures[i]
+		if f.NoradID != w.noradID || f.Category != w.category || !errors.Is(f.Err, w.err) {
+			t.Errorf("failure %d = %d %s %v; want %d %s %v", i, f.NoradID, f.Category, f.Err, w.noradID, w.category, w.err)
+		}
+	}
+	if progress != 1 {
+		t.Errorf("progress reached %v, want 1", progress)
+	}
+}
+
+func TestSetStatisticsOverSearchResults(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 1, Name: "STARLINK-1", ObjectType: "PAYLOAD", OrbitRegime: "LEO", Inclination: 53, Period: 95.6, Apogee: 550, Perigee: 540},
+		{NoradID: 2, Name: "STARLINK-2", ObjectType: "PAYLOAD", OrbitRegime: "LEO", Inclination: 53.2, Period: 95.5, Apogee: 560, Perigee: 345},
+		{NoradID: 3, Name: "ISS (ZARYA)", ObjectType: "PAYLOAD", OrbitRegime: "LEO", Inclination: 51.6, Period: 92.9, Apogee: 420, Perigee: 415},
+		{NoradID: 4, Name: "SL-16 DEB", ObjectType: "DEBRIS", OrbitRegime: "LEO", Inclination: 71, Period: 101, Apogee: 900, Perigee: 800},
+		{NoradID: 5, Name: "GOES 16", ObjectType: "PAYLOAD", OrbitRegime: "GEO", Inclination: 0.1, Period: 1436.1, Apogee: 35790, Perigee: 35780},
+		// No SATCAT orbit: parameters come from the TLE
+		{NoradID: 6, Name: "TLE ONLY", ObjectType: "PAYLOAD", OrbitRegime: "LEO", TLE: issTLE()},
+	}
+
+	results := SearchSatellites(satellites, SearchCriteria{Type: "payload", Regime: "leo"})
+	stats := SetStatistics(results)
+
+	if stats.Count != 4 || stats.OrbitCount != 4 {
+		t.Fatalf("stats over %d satellites with %d orbits, want 4 LEO payloads", stats.Count, stats.OrbitCount)
+	}
+	if stats.Regimes[RegimeLEO] != 4 || len(stats.Regimes) != 1 || stats.Types["PAYLOAD"] != 4 || len(stats.Types) != 1 {
+		t.Errorf("regimes %v and types %v, want only LEO payloads", stats.Regimes, stats.Types)
+	}
+
+	_, _, tlePeriod, _, _ := tleOrbit(issTLE())
+	assertNear(t, "mean inclination", stats.MeanInclination, (53+53.2+51.6+51.64)/4, 1e-9)
+	assertNear(t, "min inclination", stats.MinInclination, 51.6, 1e-9)
+	assertNear(t, "max inclination", stats.MaxInclination, 53.2, 1e-9)
+	assertNear(t, "min perigee", stats.MinPerigee, 345, 1e-9)
+	assertNear(t, "max apogee", stats.MaxApogee, 560, 1e-9)
+	assertNear(t, "mean period", stats.MeanPeriod, (95.6+95.5+92.9+tlePeriod)/4, 1e-9)
+
+	if empty := SetStatistics(nil); empty.Count != 0 || empty.OrbitCount != 0 || empty.MeanPeriod != 0 {
+		t.Errorf("SetStatistics(nil) = %+v, want zeros", empty)
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	satellites := []*Satellite{
+		testSatellite("ISS", issTLE()),
+		testSatellite("GEO", geoTLE()),
+		{NoradID: 99999, Name: "SATCAT ONLY"},
+		testSatellite("SSO", makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5)),
+	}
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+
+	batches := []struct {
+		name string
+		run  func(ProgressFunc) error
+	}{
+		{"FindVisibleSatellites", func(p ProgressFunc) error {
+			_, err := FindVisibleSatellites(satellites, observer, testEpoch, VisibilityCriteria{MaxElevation: 90}, p)
+			return err
+		}},
+		{"PropagationFailures", func(p ProgressFunc) error {
+			PropagationFailures(&Catalog{Satellites: satellites}, testEpoch, p)
+			return nil
+		}},
+		{"CoverageGrid", func(p ProgressFunc) error {
+			_, err := CoverageGrid(satellites, 10, 10, testEpoch, p)
+			return err
+		}},
+		{"PredictSchedule", func(p ProgressFunc) error {
+			_, errs := PredictSchedule(satellites, observer, testEpoch, testEpoch.Add(12*time.Hour), time.Minute, 10, p)
+			return errors.Join(errs...)
+		}},
+		{"PredictSchedule empty", func(p ProgressFunc) error {
+			_, errs := PredictSchedule(nil, observer, testEpoch, testEpoch.Add(time.Hour), time.Minute, 10, p)
+			return errors.Join(errs...)
+		}},
+	}
+	for _, b := range batches {
+		var fractions []float64
+		if err := b.run(func(f float64) { fractions = append(fractions, f) }); err != nil {
+			t.Fatalf("%s: %v", b.name, err)
+		}
+		if len(fractions) == 0 || fractions[len(fractions)-1] != 1 {
+			t.Errorf("%s: progress %v does not end at 1", b.name, fractions)
+		}
+		for i, f := range fractions {
+			if f < 0 || f > 1 || (i > 0 && f < fractions[i-1]) {
+				t.Errorf("%s: progress %v is not non-decreasing within [0, 1]", b.name, fractions)
+				break
+			}
+		}
+	}
+}
+
+func TestSatellitesNearZenith(t *testing.T) {
+	// Stand under the ISS, with two satellites trailing it in the same orbit
+	iss := issTLE()
+	pos, err := PropagateSatellite(iss, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := SubSatellitePoint(pos)
+	observer := &ObserverPosition{Latitude: lat, Longitude: lon}
+
+	sats := []*Satellite{
+		testSatellite("TRAILING 2", makeTLE(30002, testEpoch, 51.64, 200, 0.0005, 90, 268, 15.5)),
+		testSatellite("GEO", geoTLE()),
+		testSatellite("ISS", iss),
+		testSatellite("TRAILING 1", makeTLE(30001, testEpoch, 51.64, 200, 0.0005, 90, 269, 15.5)),
+	}
+
+	near, err := SatellitesNearZenith(sats, observer, testEpoch, 60)
+	if err != nil {
+		t.Fatalf("SatellitesNearZenith: %v", err)
+	}
+	var names []string
+	for _, v := range near {
+		names = append(names, v.Satellite.Name)
+	}
+	if strings.Join(names, ", ") != "ISS, TRAILING 1, TRAILING 2" {
+		t.Fatalf("near zenith: %v, want the ISS first and then the trailing satellites in order", names)
+	}
+	if zenith := 90 - near[0].Angles.Elevation; zenith > 0.5 {
+		t.Errorf("ISS %.2f° from the zenith, want directly overhead", zenith)
+	}
+	for _, v := range near {
+		if v.Angles.Elevation < 30 {
+			t.Errorf("%s at %.1f° elevation, outside the 60° cone", v.Satellite.Name, v.Angles.Elevation)
+		}
+	}
+
+	// A narrow cone keeps only the ISS
+	if near, err := SatellitesNearZenith(sats, observer, testEpoch, 5); err != nil || len(near) != 1 || near[0].Satellite.Name != "ISS" {
+		t.Errorf("within 5° of the zenith: %d satellites, %v; want the ISS alone", len(near), err)
+	}
+	for _, angle := range []float64{-1, 91} {
+		if _, err := SatellitesNearZenith(sats, observer, testEpoch, angle); err == nil {
+			t.Errorf("SatellitesNearZenith accepted a zenith angle of %v", angle)
+		}
+	}
+}
+
+func TestShellOccupancy(t *testing.T) {
+	orbit := func(id int, perigee, apogee float64) *Satellite {
+		return &Satellite{NoradID: id, Name: fmt.Sprintf("SAT-%d", id), Perigee: perigee, Apogee: apogee, Period: 95}
+	}
+	sats := []*Satellite{
+		orbit(6, 540, 560),   // inside the 500-600 km shell
+		orbit(2, 300, 350),   // wholly below
+		orbit(5, 200, 35786), // crosses it on a transfer orbit
+		orbit(3, 650, 700),   // wholly above
+		orbit(4, 590, 650),   // perigee inside
+		orbit(1, 450, 500),   // apogee touching the lower edge
+		orbit(7, 600.1, 620), // just above the upper edge
+		{NoradID: 8, Name: "NO ORBIT"},
+		// No SATCAT orbit: derived from the TLE, ~420 km and ~35786 km
+		testSatellite("ISS", issTLE()),
+		testSatellite("GEO", geoTLE()),
+	}
+
+	var ids []int
+	for _, sat := range ShellOccupancy(sats, 550, 100) {
+		ids = append(ids, sat.NoradID)
+	}
+	if want := []int{1, 4, 5, 6}; !slices.Equal(ids, want) {
+		t.Errorf("500-600 km shell holds %v, want %v", ids, want)
+	}
+
+	ids = ids[:0]
+	for _, sat := range ShellOccupancy(sats, 420, 40) {
+		ids = append(ids, sat.NoradID)
+	}
+	if want := []int{5, 25544}; !slices.Equal(ids, want) {
+		t.Errorf("400-440 km shell holds %v, want %v", ids, want)
+	}
+
+	if got := ShellOccupancy(sats, 20000, 10); len(got) != 1 || got[0].NoradID != 5 {
+		t.Errorf("shell at 20000 km holds %d satellites, want only the transfer orbit", len(got))
+	}
+}
+
+// syntheticCatalog returns the satellites merged from syntheticFeed(n).
+func syntheticCatalog(n int) []*Satellite {
+	tles, satcats := syntheticFeed(n)
+	return MergeSatelliteData(tles, satcats)
+}
+
+func TestPropagateCatalogMatchesSerial(t *testing.T) {
+	sats := syntheticCatalog(2000)
+	sats = append(sats, &Satellite{NoradID: 99999, Name: "NO TLE"})
+	at := testEpoch.Add(6 * time.Hour)
+
+	want := make(map[int]*SatellitePosition)
+	for _, sat := range sats {
+		if sat.TLE == nil {
+			continue
+		}
+		if pos, err := PropagateSatellite(sat.TLE, at); err == nil {
+			want[sat.NoradID] = pos
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("no satellites propagated serially")
+	}
+
+	for _, workers := range []int{0, 1, 3, 64} {
+		got := PropagateCatalog(sats, at, workers)
+		if len(got) != len(want) {
+			t.Errorf("workers %d: %d positions, want %d", workers, len(got), len(want))
+		}
+		for id, pos := range want {
+			if g := got[id]; g == nil || *g != *pos {
+				t.Errorf("workers %d: satellite %d at %+v, want %+v", workers, id, g, pos)
+			}
+		}
+	}
+}
+
+func BenchmarkPropagateCatalog(b *testing.B) {
+	sats := syntheticCatalog(5000)
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "gomaxprocs"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				PropagateCatalog(sats, testEpoch, workers)
+			}
+		})
+	}
+}
+
+func TestMergeTLEHistory(t *testing.T) {
+	day := 24 * time.Hour
+	older := makeTLE(25544, testEpoch.Add(-2*day), 51.64, 210, 0.0005, 90, 270, 15.5)
+	newest := makeTLE(25544, testEpoch, 51.64, 200, 0.0005, 90, 270, 15.5)
+	middle := makeTLE(25544, testEpoch.Add(-day), 51.64, 205, 0.0005, 90, 270, 15.5)
+	geo := geoTLE()
+	tles := []TLE{*older, *geo, *newest, *middle}
+
+	// By default the last TLE in the feed wins and no history is kept
+	satellites, _ := MergeSatelliteDataWith(tles, nil, MergeOptions{})
+	iss := satellites[slices.IndexFunc(satellites, func(s *Satellite) bool { return s.NoradID == 25544 })]
+	if iss.TLE.Line1 != middle.Line1 || iss.TLEHistory != nil {
+		t.Errorf("default merge kept TLE %q with %d history entries, want the last in the feed and none", iss.TLE.Line1, len(iss.TLEHistory))
+	}
+
+	satellites, _ = MergeSatelliteDataWith(tles, nil, MergeOptions{TLEHistory: true})
+	iss = satellites[slices.IndexFunc(satellites, func(s *Satellite) bool { return s.NoradID == 25544 })]
+	var epochs []time.Time
+	for _, tle := range iss.TLEHistory {
+		epoch, err := tle.GetEpoch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		epochs = append(epochs, epoch)
+	}
+	want := []time.Time{testEpoch.Add(-2 * day), testEpoch.Add(-day), testEpoch}
+	if len(epochs) != len(want) {
+		t.Fatalf("history has epochs %v, want %v", epochs, want)
+	}
+	for i := range want {
+		if !epochs[i].Equal(want[i]) {
+			t.Errorf("history epoch %d = %v, want %v", i, epochs[i], want[i])
+		}
+	}
+	if iss.TLE.Line1 != newest.Line1 || !iss.TLEEpoch.Equal(testEpoch) {
+		t.Errorf("history merge uses TLE %q at %v, want the newest", iss.TLE.Line1, iss.TLEEpoch)
+	}
+	if geoSat := satellites[slices.IndexFunc(satellites, func(s *Satellite) bool { return s.NoradID == 40000 })]; geoSat.TLEHistory != nil {
+		t.Errorf("a single TLE was kept as a history of %d", len(geoSat.TLEHistory))
+	}
+
+	for _, tt := range []struct {
+		at   time.Time
+		want *TLE
+	}{
+		{testEpoch.Add(-3 * day), older}, // before every epoch
+		{testEpoch.Add(-2 * day), older},
+		{testEpoch.Add(-day / 2), middle},
+		{testEpoch.Add(day), newest},
+	} {
+		if got := iss.TLEAt(tt.at); got.Line1 != tt.want.Line1 {
+			t.Errorf("TLEAt(%v) = %q, want %q", tt.at, got.Line1, tt.want.Line1)
+		}
+	}
+
+	// The history survives a save and load
+	data, err := json.Marshal(iss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Satellite
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.TLEHistory) != len(iss.TLEHistory) {
+		t.Fatalf("history after a JSON round trip has %d TLEs, want %d", len(loaded.TLEHistory), len(iss.TLEHistory))
+	}
+	for i, tle := range loaded.TLEHistory {
+		if tle.Line1 != iss.TLEHistory[i].Line1 || tle.Line2 != iss.TLEHistory[i].Line2 {
+			t.Errorf("history TLE %d after a JSON round trip = %q, want %q", i, tle.Line1, iss.TLEHistory[i].Line1)
+		}
+	}
+}
+
+func TestReadIDList(t *testing.T) {
+	input := "# header comment\n25544\n\n  ISS (ZARYA)  # trailing comment\n\t\n#\n43013\r\n"
+	queries, err := ReadIDList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadIDList: %v", err)
+	}
+	if want := []string{"25544", "ISS (ZARYA)", "43013"}; !slices.Equal(queries, want) {
+		t.Errorf("ReadIDList = %q, want %q", queries, want)
+	}
+}
+
+func TestSelectSatellites(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 25544, Name: "ISS (ZARYA)"},
+		{NoradID: 48274, Name: "CSS (TIANHE)"},
+		{NoradID: 43013, Name: "NOAA 20"},
+		{NoradID: 28654, Name: "NOAA 18"},
+		{NoradID: 33591, Name: "NOAA 19"},
+	}
+	queries := []string{"43013", "iss (zarya)", "TIANHE", "99999", "NOAA", "", "25544", "noaa 20"}
+
+	selected, errs := SelectSatellites(satellites, queries)
+	var ids []int
+	for _, sat := range selected {
+		ids = append(ids, sat.NoradID)
+	}
+	if want := []int{43013, 25544, 48274}; !slices.Equal(ids, want) {
+		t.Errorf("selected %v, want %v in query order without repeats", ids, want)
+	}
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	want := []string{
+		"no satellite with NORAD ID 99999",
+		`"NOAA" matches 3 satellites; use a NORAD ID or the exact name`,
+		"empty satellite name or ID",
+	}
+	if !slices.Equal(messages, want) {
+		t.Errorf("errors = %q, want %q", messages, want)
+	}
+}
+
+func BenchmarkSelectSatellites(b *testing.B) {
+	satellites := syntheticCatalog(20000)
+	queries := make([]string, 0, 2000)
+	for i := 0; i < 1000; i++ {
+		sat := satellites[i*18+1] // every other satellite has a SATCAT name
+		queries = append(queries, strconv.Itoa(sat.NoradID), sat.Name)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, errs := SelectSatellites(satellites, queries); len(errs) > 0 {
+			b.Fatal(errs[0])
+		}
+	}
+}
+
+func TestFindVisibleSatellitesObserverUnset(t *testing.T) {
+	geo := testSatellite("GEO", geoTLE())
+	pos, err := PropagateSatellite(geo.TLE, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lon, _ := SubSatellitePoint(pos)
+	satellites := []*Satellite{geo}
+
+	tests := []struct {
+		name     string
+		observer *ObserverPosition
+		allow    bool
+		unset    bool
+	}{
+		{"nil", nil, false, true},
+		{"nil even when allowed", nil, true, true},
+		{"zero value", &ObserverPosition{}, false, true},
+		{"explicit null island", &ObserverPosition{}, true, false},
+		{"near null island", &ObserverPosition{Altitude: 5}, false, false},
+		{"under the satellite", &ObserverPosition{Longitude: lon}, false, false},
+	}
+	for _, tt := range tests {
+		criteria := VisibilityCriteria{MinElevation: 0, MaxElevation: 90, AllowNullIsland: tt.allow}
+		visible, err := FindVisibleSatellites(satellites, tt.observer, testEpoch, criteria, nil)
+		if tt.unset {
+			if !errors.Is(err, ErrObserverUnset) || visible != nil {
+				t.Errorf("%s: got %d satellites, error %v; want ErrObserverUnset", tt.name, len(visible), err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		// The search runs from the given location like any other
+		above := CalculateObservationAngles(pos, tt.observer).Elevation >= 0
+		if (len(visible) == 1) != above {
+			t.Errorf("%s: got %d visible satellites, want the GEO satellite only if above the horizon (%v)", tt.name, len(visible), above)
+		}
+	}
+}
+
+func TestFuzzyNameSearch(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 1, Name: "STARLINK-1234"},
+		{NoradID: 2, Name: "COSMOS 2251"},
+		{NoradID: 3, Name: "GPS BIIR-2 (PRN 13)"},
+		{NoradID: 4, Name: "TIANGONG"},
+		{NoradID: 5, Name: "ISS (ZARYA)"},
+		{NoradID: 6, Name: "STARLINK-30001"},
+		{NoradID: 7, Name: "SENTINEL-2A"},
+	}
+
+	for _, tt := range []struct {
+		query, name string
+		want        int
+	}{
+		{"starlnk", "STARLINK-1234", 1},
+		{"star link", "STARLINK-1234", 0},
+		{"Starlink 1234", "STARLINK-1234", 0},
+		{"sentnel", "SENTINEL-2A", 1},
+		{"ISS", "COSMOS 2251", 2},
+		{"", "COSMOS 2251", 0},
+	} {
+		if got := FuzzyNameDistance(tt.query, tt.name); got != tt.want {
+			t.Errorf("FuzzyNameDistance(%q, %q) = %d, want %d", tt.query, tt.name, got, tt.want)
+		}
+	}
+
+	tests := []struct {
+		query       string
+		maxDistance int
+		want        []int
+	}{
+		{"starlnk", 0, []int{1, 6}},
+		{"starlnk 1234", 0, []int{1}},
+		{"sentnel", 0, []int{7}},
+		// Short queries match exactly, not every name sharing a letter or two
+		{"ISS", 0, []int{5}},
+		{"gps", 0, []int{3}},
+		{"ISS", 1, []int{5}},
+		// Longer queries allow more edits, and an explicit distance is used as given
+		{"tiangnog", 0, []int{4}},
+		{"tiangnog", 1, nil},
+	}
+	for _, tt := range tests {
+		got := searchIDs(t, satellites, SearchCriteria{FuzzyName: tt.query, MaxDistance: tt.maxDistance})
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzy %q within %d matched %v, want %v", tt.query, tt.maxDistance, got, tt.want)
+		}
+	}
+
+	results := SearchSatellites(satellites, SearchCriteria{FuzzyName: "starlink 3000", MaxDistance: 4})
+	SortByFuzzyName(results, "starlink 3000")
+	if len(results) != 2 || results[0].NoradID != 6 || results[1].NoradID != 1 {
+		t.Errorf("ranked %d results, want STARLINK-30001 then STARLINK-1234", len(results))
+	}
+}
+
+func TestSearchSatellitesNameRegex(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 22675, Name: "COSMOS 2251"},
+		{NoradID: 34427, Name: "COSMOS 2251 DEB"},
+		{NoradID: 44713, Name: "STARLINK-1007"},
+		{NoradID: 25544, Name: "ISS (ZARYA)"},
+		{NoradID: 99999, Name: "Cosmos 9"},
+	}
+
+	tests := []struct {
+		pattern string
+		want    []int
+	}{
+		{`^COSMOS \d+$`, []int{22675, 99999}},
+		{`(?-i)^COSMOS \d+$`, []int{22675}},
+		{`deb$`, []int{34427}},
+		{`starlink-\d{4}`, []int{44713}},
+		{`\(ZARYA\)`, []int{25544}},
+		{`^$`, nil},
+	}
+	for _, tt := range tests {
+		re, err := CompileNameRegex(tt.pattern)
+		if err != nil {
+			t.Errorf("CompileNameRegex(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := searchIDs(t, satellites, SearchCriteria{NameRegex: re}); !slices.Equal(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+
+	for _, pattern := range []string{`COSMOS (`, `[a-`, `*`, `\p{Nope}`} {
+		re, err := CompileNameRegex(pattern)
+		if err == nil || re != nil {
+			t.Errorf("CompileNameRegex(%q) = %v, want an error", pattern, re)
+			continue
+		}
+		if !strings.Contains(err.Error(), "invalid name regex") || strings.Contains(err.Error(), "(?i)") {
+			t.Errorf("CompileNameRegex(%q) error %q, want one about the pattern as given", pattern, err)
+		}
+	}
+
+	re, _ := CompileNameRegex("COSMOS")
+	criteria := SearchCriteria{Name: "cosmos", NameRegex: re}
+	if err := criteria.Validate(); err == nil {
+		t.Error("Validate accepted both Name and NameRegex")
+	}
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Query(criteria); err == nil {
+		t.Error("Query accepted both Name and NameRegex")
+	}
+	if _, err := FindVisibleSatellites(satellites, &ObserverPosition{Latitude: 1}, testEpoch, VisibilityCriteria{SearchCriteria: criteria}, nil); err == nil {
+		t.Error("FindVisibleSatellites accepted both Name and NameRegex")
+	}
+}
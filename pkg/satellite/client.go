@@ -3,55 +3,342 @@ package satellite
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Client handles API requests to spacebook.com
 type Client struct {
-	httpClient *http.Client
-	tleURL     string
-	satcatURL  string
+	httpClient   *http.Client
+	tleURLs      []string
+	satcatURLs   []string
+	tleObjectURL string // per-object TLE URL template; see SetTLEObjectEndpoint
+
+	maxAttempts int           // attempts per request, at least 1; see SetRetry
+	retryDelay  time.Duration // backoff before the first retry, doubled after each
+
+	mu          sync.Mutex
+	conditional map[string]CacheValidators // sent with requests by URL; see SetConditional
+	validators  map[string]CacheValidators // from responses by URL; see Validators
 }
 
+// ErrNotModified is returned by a conditional fetch when the server reports
+// that the feed has not changed since the validators given to SetConditional.
+var ErrNotModified = errors.New("not modified")
+
+// CacheValidators are the HTTP cache validators of a response, sent back in
+// If-None-Match and If-Modified-Since to ask whether it has changed.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// TLEObjectIDPlaceholder is replaced by the NORAD ID in a per-object TLE URL template.
+const TLEObjectIDPlaceholder = "{id}"
+
 // NewClient creates a new API client with a configured HTTP client
 func NewClient(tleURL, satcatURL string, timeout time.Duration) *Client {
+	return NewClientWithEndpoints([]string{tleURL}, []string{satcatURL}, timeout)
+}
+
+// NewClientWithEndpoints creates a new API client that fails over between endpoints.
+// Each fetch tries the URLs in order and returns the first successful response.
+func NewClientWithEndpoints(tleURLs, satcatURLs []string, timeout time.Duration) *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		tleURL:    tleURL,
-		satcatURL: satcatURL,
+		tleURLs:     tleURLs,
+		satcatURLs:  satcatURLs,
+		maxAttempts: 1,
 	}
 }
 
-// FetchTLEs retrieves all TLE entries from the API.
-// TLEs are returned as plain text with two lines per entry.
-func (c *Client) FetchTLEs() ([]TLE, error) {
-	resp, err := c.httpClient.Get(c.tleURL)
+// SetConditional makes requests to the given URLs conditional on their
+// validators, as saved from Validators after an earlier fetch: a feed that
+// the server reports unchanged fails with ErrNotModified instead of being
+// downloaded again. Passing nil makes every request unconditional.
+func (c *Client) SetConditional(validators map[string]CacheValidators) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conditional = make(map[string]CacheValidators, len(validators))
+	for url, v := range validators {
+		c.conditional[url] = v
+	}
+}
+
+// Validators returns the cache validators of the feeds fetched so far by URL,
+// including those given to SetConditional for feeds that were not modified.
+func (c *Client) Validators() map[string]CacheValidators {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	validators := make(map[string]CacheValidators, len(c.conditional)+len(c.validators))
+	for url, v := range c.conditional {
+		validators[url] = v
+	}
+	for url, v := range c.validators {
+		if v == (CacheValidators{}) {
+			delete(validators, url)
+			continue
+		}
+		validators[url] = v
+	}
+	return validators
+}
+
+// unconditional stops sending validators for the given URLs, so they are
+// downloaded even if unchanged.
+func (c *Client) unconditional(urls []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, url := range urls {
+		delete(c.conditional, url)
+	}
+}
+
+// maxRetryAfter caps how long a server's Retry-After header can make a request wait
+const maxRetryAfter = time.Minute
+
+// SetRetry makes each request try up to maxAttempts times when it fails with
+// a transient network error, such as a refused or reset connection or a
+// timeout, or a 5xx or 429 response, waiting retryDelay before the
+// first retry and twice as long before each next one, with random jitter. A
+// Retry-After header on the response overrides the wait, up to a minute.
+// Other 4xx responses, TLS failures and bad URLs are not retried. maxAttempts below 1 is treated as 1,
+// which disables retries (the default).
+func (c *Client) SetRetry(maxAttempts int, retryDelay time.Duration) {
+	c.maxAttempts = max(maxAttempts, 1)
+	c.retryDelay = retryDelay
+}
+
+// fetchFirst requests each URL in order and returns the body of the first
+// successful response. If all fail, the individual errors are joined. Once ctx
+// is done, the remaining URLs are not tried.
+func (c *Client) fetchFirst(ctx context.Context, urls []string, what string) ([]byte, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no %s endpoints configured", what)
+	}
+
+	var errs []error
+	for _, url := range urls {
+		body, err := c.fetchContext(ctx, url, what)
+		if err == nil {
+			return body, nil
+		}
+		if errors.Is(err, ErrNotModified) {
+			return nil, err
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// fetchContext performs a GET request and returns the response body, retrying
+// transient failures as configured by SetRetry until ctx is done.
+func (c *Client) fetchContext(ctx context.Context, url, what string) ([]byte, error) {
+	delay := c.retryDelay
+	for attempt := 1; ; attempt++ {
+		body, retryAfter, err := c.fetchOnce(ctx, url, what)
+		if err == nil || retryAfter < 0 || attempt >= max(c.maxAttempts, 1) {
+			return body, err
+		}
+
+		// Equal jitter, half the backoff plus a random part of the other
+		// half, spreads out clients retrying together
+		wait := delay/2 + time.Duration(rand.Int64N(int64(delay/2)+1))
+		if retryAfter > 0 {
+			wait = min(retryAfter, maxRetryAfter)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// fetchOnce performs a single GET request. On failure, retryAfter is
+// negative if the request should not be retried, the server's Retry-After if
+// it gave one, and 0 otherwise.
+func (c *Client) fetchOnce(ctx context.Context, url, what string) (body []byte, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	// Feeds can run to several megabytes; setting this ourselves turns off the
+	// transport's transparent decompression, so the body is handled below
+	req.Header.Set("Accept-Encoding", "gzip")
+	c.mu.Lock()
+	if v, ok := c.conditional[url]; ok {
+		if v.ETag != "" {
+			req.Header.Set("If-None-Match", v.ETag)
+		}
+		if v.LastModified != "" {
+			req.Header.Set("If-Modified-Since", v.LastModified)
+		}
+	}
+	c.mu.Unlock()
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch TLEs: %w", err)
+		if ctx.Err() != nil || !transient(err) {
+			return nil, -1, fmt.Errorf("failed to fetch %s: %w", what, err)
+		}
+		return nil, 0, fmt.Errorf("failed to fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, -1, fmt.Errorf("%s from %s: %w", what, url, ErrNotModified)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		err := fmt.Errorf("unexpected status code from %s: %d", url, resp.StatusCode)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return nil, -1, err
+		}
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.validators == nil {
+		c.validators = make(map[string]CacheValidators)
 	}
+	c.validators[url] = CacheValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	c.mu.Unlock()
 
-	// Parse TLE data (each TLE is 2 lines)
+	return body, 0, nil
+}
+
+// transient reports whether a failed request may succeed if retried: network
+// errors and connections closed mid-response are, while TLS failures and
+// requests the client rejects outright, such as for an unsupported URL
+// scheme, are not.
+func transient(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &certErr) || errors.As(err, &recordErr) {
+		return false
+	}
+	// url.Error is itself a net.Error, so look at what it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// readBody reads a response body, decompressing it if the server sent it
+// gzip-encoded.
+func readBody(resp *http.Response) ([]byte, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") || resp.Uncompressed {
+		return io.ReadAll(resp.Body)
+	}
+
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// parseRetryAfter parses a Retry-After header, given in seconds or as an
+// HTTP date, into a wait. Returns 0 if it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// FetchTLEs retrieves all TLE entries from the API.
+// TLEs are returned as plain text in either two-line or three-line (name line
+// followed by the two element lines) format; see parseTLEs.
+func (c *Client) FetchTLEs() ([]TLE, error) {
+	return c.FetchTLEsContext(context.Background())
+}
+
+// FetchTLEsContext is FetchTLEs with a context, which aborts the request in
+// flight and any retries or fallback endpoints when canceled.
+func (c *Client) FetchTLEsContext(ctx context.Context) ([]TLE, error) {
+	body, err := c.fetchFirst(ctx, c.tleURLs, "TLEs")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTLEs(body)
+}
+
+// SetTLEObjectEndpoint sets the URL template used by FetchTLEByID, such as
+// "https://example.com/tle/{id}". An empty template disables per-object fetches.
+func (c *Client) SetTLEObjectEndpoint(template string) {
+	c.tleObjectURL = template
+}
+
+// FetchTLEByID retrieves the TLE for a single satellite from the per-object
+// endpoint, which is far cheaper than downloading the whole set.
+// The response may be in 2LE or 3LE format and must contain the requested object.
+func (c *Client) FetchTLEByID(ctx context.Context, noradID int) (*TLE, error) {
+	if c.tleObjectURL == "" {
+		return nil, fmt.Errorf("no per-object TLE endpoint configured")
+	}
+
+	url := strings.ReplaceAll(c.tleObjectURL, TLEObjectIDPlaceholder, strconv.Itoa(noradID))
+	body, err := c.fetchContext(ctx, url, fmt.Sprintf("TLE for %d", noradID))
+	if err != nil {
+		return nil, err
+	}
+
+	tles, err := parseTLEs(body)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tles {
+		if tles[i].GetNoradID() == noradID {
+			return &tles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no TLE for NORAD ID %d in response from %s", noradID, url)
+}
+
+// parseTLEs parses a stream of 2LE and 3LE records, which may be mixed.
+// Any line that is not a "1 " or "2 " element line is taken as the name of the
+// following element set, with a leading "0 " stripped. A line 1 that is not
+// followed by a line 2, or a line 2 without a line 1, is discarded.
+func parseTLEs(data []byte) ([]TLE, error) {
 	var tles []TLE
-	scanner := bufio.NewScanner(bytes.NewReader(body))
-	var line1 string
-	lineNum := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var name, line1 string
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -59,15 +346,22 @@ func (c *Client) FetchTLEs() ([]TLE, error) {
 			continue
 		}
 
-		if lineNum%2 == 0 {
+		switch {
+		case strings.HasPrefix(line, "1 "):
 			line1 = line
-		} else {
-			tles = append(tles, TLE{
-				Line1: line1,
-				Line2: line,
-			})
+		case strings.HasPrefix(line, "2 "):
+			if line1 != "" {
+				tles = append(tles, TLE{
+					Name:  name,
+					Line1: line1,
+					Line2: line,
+				})
+			}
+			name, line1 = "", ""
+		default:
+			name = strings.TrimSpace(strings.TrimPrefix(line, "0 "))
+			line1 = ""
 		}
-		lineNum++
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -80,19 +374,15 @@ func (c *Client) FetchTLEs() ([]TLE, error) {
 // FetchSATCATs retrieves all SATCAT entries from the API.
 // SATCAT data is returned as JSON.
 func (c *Client) FetchSATCATs() ([]SATCAT, error) {
-	resp, err := c.httpClient.Get(c.satcatURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch SATCATs: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	return c.FetchSATCATsContext(context.Background())
+}
 
-	body, err := io.ReadAll(resp.Body)
+// FetchSATCATsContext is FetchSATCATs with a context, which aborts the request
+// in flight and any retries or fallback endpoints when canceled.
+func (c *Client) FetchSATCATsContext(ctx context.Context) ([]SATCAT, error) {
+	body, err := c.fetchFirst(ctx, c.satcatURLs, "SATCATs")
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	var satcats []SATCAT
@@ -0,0 +1,330 @@
+package satellite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tleFeed returns a 3LE feed with the given satellites' TLEs.
+func tleFeed(tles ...*TLE) string {
+	var b strings.Builder
+	for i, tle := range tles {
+		b.WriteString("0 SAT " + string(rune('A'+i)) + "\n" + tle.Line1 + "\n" + tle.Line2 + "\n")
+	}
+	return b.String()
+}
+
+// newFeedServer returns a test server answering every request with status
+// and body, and counting the requests in hits.
+func newFeedServer(t *testing.T, status int, body string, hits *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			*hits++
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientEndpointFailover(t *testing.T) {
+	var primaryHits, fallbackHits int
+	primary := newFeedServer(t, http.StatusInternalServerError, "down", &primaryHits)
+	fallback := newFeedServer(t, http.StatusOK, tleFeed(issTLE(), geoTLE()), &fallbackHits)
+	satcatFallback := newFeedServer(t, http.StatusOK, `[{"noradId": 25544, "name": "ISS (ZARYA)"}]`, nil)
+
+	client := NewClientWithEndpoints(
+		[]string{primary.URL, fallback.URL},
+		[]string{primary.URL, satcatFallback.URL},
+		5*time.Second)
+
+	tles, err := client.FetchTLEs()
+	if err != nil {
+		t.Fatalf("FetchTLEs: %v", err)
+	}
+	if len(tles) != 2 || tles[0].GetNoradID() != 25544 {
+		t.Errorf("FetchTLEs returned %d TLEs from the fallback, want 2 starting with 25544", len(tles))
+	}
+	if primaryHits != 1 || fallbackHits != 1 {
+		t.Errorf("primary hit %d times and fallback %d, want 1 each", primaryHits, fallbackHits)
+	}
+
+	satcats, err := client.FetchSATCATs()
+	if err != nil {
+		t.Fatalf("FetchSATCATs: %v", err)
+	}
+	if len(satcats) != 1 || satcats[0].NoradID != 25544 {
+		t.Errorf("FetchSATCATs = %+v, want ISS from the fallback", satcats)
+	}
+}
+
+func TestClientAllEndpointsFail(t *testing.T) {
+	first := newFeedServer(t, http.StatusInternalServerError, "", nil)
+	second := newFeedServer(t, http.StatusNotFound, "", nil)
+
+	client := NewClientWithEndpoints([]string{first.URL, second.URL}, nil, 5*time.Second)
+	_, err := client.FetchTLEs()
+	if err == nil {
+		t.Fatal("FetchTLEs succeeded with every endpoint failing")
+	}
+	for _, want := range []string{": 500", ": 404"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %s", err, want)
+		}
+	}
+
+	if _, err := client.FetchSATCATs(); err == nil || !strings.Contains(err.Error(), "no SATCATs endpoints") {
+		t.Errorf("FetchSATCATs without endpoints = %v, want a configuration error", err)
+	}
+}
+
+func TestParseTLEsMixedFormats(t *testing.T) {
+	iss, geo, leo := issTLE(), geoTLE(), makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5)
+	feed := strings.Join([]string{
+		"0 ISS (ZARYA)", iss.Line1, iss.Line2, // 3LE with the "0 " prefix
+		geo.Line1, geo.Line2, // 2LE
+		"",
+		"  SSO SAT  ", leo.Line1, leo.Line2, // 3LE without the prefix
+		"ORPHAN LINE 1", "1 99999U 24001A   24061.00000000  .00000000  00000-0  00000-0 0  9990",
+		"ORPHAN LINE 2", "2 99998  51.6400 200.0000 0005000  90.0000 270.0000 15.50000000    10",
+		geo.Line1, geo.Line2, // a 2LE after the orphans takes no name from them
+	}, "\n")
+
+	tles, err := parseTLEs([]byte(feed))
+	if err != nil {
+		t.Fatalf("parseTLEs: %v", err)
+	}
+	want := []TLE{
+		{Name: "ISS (ZARYA)", Line1: iss.Line1, Line2: iss.Line2},
+		{Line1: geo.Line1, Line2: geo.Line2},
+		{Name: "SSO SAT", Line1: leo.Line1, Line2: leo.Line2},
+		{Line1: geo.Line1, Line2: geo.Line2},
+	}
+	if len(tles) != len(want) {
+		t.Fatalf("parsed %d TLEs %+v, want %d", len(tles), tles, len(want))
+	}
+	for i := range want {
+		if tles[i].Name != want[i].Name || tles[i].Line1 != want[i].Line1 || tles[i].Line2 != want[i].Line2 {
+			t.Errorf("TLE %d = %+v, want %+v", i, tles[i], want[i])
+		}
+	}
+}
+
+func TestFetchTLEByID(t *testing.T) {
+	iss := issTLE()
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/tle/25544":
+			_, _ = w.Write([]byte("ISS (ZARYA)\n" + iss.Line1 + "\n" + iss.Line2 + "\n"))
+		case "/tle/40000":
+			// The endpoint answers with another object's TLE
+			_, _ = w.Write([]byte(tleFeed(iss)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClientWithEndpoints(nil, nil, 5*time.Second)
+	// A 404 is not retried
+	client.SetRetry(3, time.Millisecond)
+	if _, err := client.FetchTLEByID(context.Background(), 25544); err == nil {
+		t.Error("FetchTLEByID succeeded without an endpoint configured")
+	}
+
+	client.SetTLEObjectEndpoint(server.URL + "/tle/" + TLEObjectIDPlaceholder)
+	tle, err := client.FetchTLEByID(context.Background(), 25544)
+	if err != nil {
+		t.Fatalf("FetchTLEByID: %v", err)
+	}
+	if tle.Name != "ISS (ZARYA)" || tle.Line1 != iss.Line1 || tle.Line2 != iss.Line2 {
+		t.Errorf("FetchTLEByID = %+v, want the ISS TLE", tle)
+	}
+
+	if _, err := client.FetchTLEByID(context.Background(), 40000); err == nil || !strings.Contains(err.Error(), "no TLE for NORAD ID 40000") {
+		t.Errorf("mismatched response: %v, want a missing-TLE error", err)
+	}
+	if _, err := client.FetchTLEByID(context.Background(), 12345); err == nil {
+		t.Error("FetchTLEByID succeeded on a 404")
+	}
+	if want := []string{"/tle/25544", "/tle/40000", "/tle/12345"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("requested %v, want %v", paths, want)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	feed := tleFeed(issTLE())
+	var hits int
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits <= 2 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(feed))
+	}))
+	t.Cleanup(flaky.Close)
+
+	// Fails twice, then succeeds on the third and last attempt
+	client := NewClientWithEndpoints([]string{flaky.URL}, nil, 5*time.Second)
+	client.SetRetry(3, time.Millisecond)
+	tles, err := client.FetchTLEs()
+	if err != nil || len(tles) != 1 {
+		t.Fatalf("FetchTLEs = %d TLEs, %v; want the ISS after two retries", len(tles), err)
+	}
+	if hits != 3 {
+		t.Errorf("server hit %d times, want 3", hits)
+	}
+
+	// One attempt too few gives up with the last error
+	hits = 0
+	client.SetRetry(2, time.Millisecond)
+	if _, err := client.FetchTLEs(); err == nil || !strings.Contains(err.Error(), ": 503") {
+		t.Errorf("FetchTLEs with 2 attempts = %v, want a 503", err)
+	}
+	if hits != 2 {
+		t.Errorf("server hit %d times, want 2", hits)
+	}
+
+	// Without SetRetry a request is tried once
+	hits = 0
+	if _, err := NewClientWithEndpoints([]string{flaky.URL}, nil, 5*time.Second).FetchTLEs(); err == nil || hits != 1 {
+		t.Errorf("FetchTLEs without retries = %v after %d hits, want a 503 after 1", err, hits)
+	}
+}
+
+func TestClientRetryPermanentErrors(t *testing.T) {
+	var hits int
+	notFound := newFeedServer(t, http.StatusNotFound, "", &hits)
+	var connections int
+	untrusted := httptest.NewUnstartedServer(http.NotFoundHandler())
+	untrusted.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections++
+		}
+	}
+	untrusted.Config.ErrorLog = log.New(io.Discard, "", 0)
+	untrusted.StartTLS()
+	t.Cleanup(untrusted.Close)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"not found", notFound.URL},
+		{"unsupported scheme", "ftp://example.com/tles.txt"},
+		{"untrusted certificate", untrusted.URL},
+	}
+	for _, tt := range tests {
+		// A retry would wait an hour and hit the deadline
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		client := NewClientWithEndpoints([]string{tt.url}, nil, 5*time.Second)
+		client.SetRetry(3, time.Hour)
+		_, err := client.FetchTLEsContext(ctx)
+		cancel()
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: FetchTLEs = %v, want an error without retrying", tt.name, err)
+		}
+	}
+	if hits != 1 || connections != 1 {
+		t.Errorf("404 server hit %d times and TLS server connected %d times, want 1 each", hits, connections)
+	}
+
+	// A refused connection is transient
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err := http.Get(closed.URL)
+	if err == nil || !transient(err) {
+		t.Errorf("refused connection %v is not transient", err)
+	}
+}
+
+func TestClientContextCancel(t *testing.T) {
+	started := make(chan struct{})
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(stalled.Close)
+	var fallbackHits int
+	fallback := newFeedServer(t, http.StatusOK, tleFeed(issTLE()), &fallbackHits)
+
+	client := NewClientWithEndpoints([]string{stalled.URL, fallback.URL}, nil, time.Minute)
+	client.SetRetry(3, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	begin := time.Now()
+	_, err := client.FetchTLEsContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchTLEsContext = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Errorf("canceled request returned after %v", elapsed)
+	}
+	if fallbackHits != 0 {
+		t.Errorf("fallback hit %d times after the context was canceled", fallbackHits)
+	}
+}
+
+func TestClientGzipResponse(t *testing.T) {
+	feed := tleFeed(issTLE(), geoTLE())
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write([]byte(feed)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(compressed.Bytes())
+		case "/corrupt":
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write([]byte(feed))
+		default:
+			_, _ = w.Write([]byte(feed))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{"/gzip", "/plain"} {
+		tles, err := NewClientWithEndpoints([]string{server.URL + path}, nil, 5*time.Second).FetchTLEs()
+		if err != nil {
+			t.Fatalf("%s: FetchTLEs: %v", path, err)
+		}
+		if len(tles) != 2 || tles[0].GetNoradID() != 25544 || tles[1].GetNoradID() != 40000 {
+			t.Errorf("%s: FetchTLEs returned %d TLEs, want the ISS and GEO", path, len(tles))
+		}
+		if acceptEncoding != "gzip" {
+			t.Errorf("%s: Accept-Encoding %q, want gzip", path, acceptEncoding)
+		}
+	}
+
+	if _, err := NewClientWithEndpoints([]string{server.URL + "/corrupt"}, nil, 5*time.Second).FetchTLEs(); err == nil || !strings.Contains(err.Error(), "gzip") {
+		t.Errorf("FetchTLEs of a corrupt gzip body = %v, want a gzip error", err)
+	}
+}
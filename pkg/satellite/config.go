@@ -1,36 +1,91 @@
 package satellite
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Config represents satellite catalog configuration.
 // This struct can be instantiated programmatically or loaded from a configuration file.
 type Config struct {
-	DataDir           string  // Directory for storing catalog data
-	AutoFetch         bool    // Automatically fetch data if stale or missing
-	APITimeout        int     // API request timeout in seconds
-	MaxCatalogAge     int     // Maximum catalog age in hours before considered stale (0 = never stale)
-	TLEEndpoint       string  // URL for TLE data endpoint
-	SATCATEndpoint    string  // URL for SATCAT data endpoint
-	ObserverLatitude  float64 // Observer latitude in degrees
-	ObserverLongitude float64 // Observer longitude in degrees
-	ObserverAltitude  float64 // Observer altitude in meters above sea level
+	DataDir             string   `mapstructure:"data_dir"`                  // Directory for storing catalog data
+	CatalogFormat       string   `mapstructure:"catalog_format"`            // Catalog file format: "json" or "gob" (faster to load)
+	CompressCatalog     bool     `mapstructure:"compress_catalog"`          // Save the catalog gzip-compressed (catalog.json.gz)
+	CatalogBackend      string   `mapstructure:"catalog_backend"`           // Where the catalog is kept: "file" (see CatalogFormat) or "sqlite" (catalog.db)
+	AutoFetch           bool     `mapstructure:"auto_fetch"`                // Automatically fetch data if stale or missing
+	APITimeout          int      `mapstructure:"api_timeout"`               // API request timeout in seconds
+	APIRetries          int      `mapstructure:"api_retries"`               // Retries of a request after a network error or 5xx/429 response
+	APIRetryDelay       float64  `mapstructure:"api_retry_delay"`           // Seconds before the first retry, doubled for each next one
+	MaxCatalogAge       int      `mapstructure:"max_catalog_age"`           // Maximum catalog age in hours before considered stale (0 = never stale)
+	TLEEndpoint         string   `mapstructure:"tle_endpoint"`              // URL for TLE data endpoint
+	SATCATEndpoint      string   `mapstructure:"satcat_endpoint"`           // URL for SATCAT data endpoint
+	TLEFallbacks        []string `mapstructure:"tle_fallback_endpoints"`    // TLE endpoints tried in order if the primary fails
+	SATCATFallbacks     []string `mapstructure:"satcat_fallback_endpoints"` // SATCAT endpoints tried in order if the primary fails
+	TLEObjectEndpoint   string   `mapstructure:"tle_object_endpoint"`       // Per-object TLE URL template with {id} for the NORAD ID ("" = disabled)
+	ObserverLatitude    float64  `mapstructure:"observer_latitude"`         // Observer latitude in degrees
+	ObserverLongitude   float64  `mapstructure:"observer_longitude"`        // Observer longitude in degrees east (0-360 is normalized to -180..180)
+	ObserverAltitude    float64  `mapstructure:"observer_altitude"`         // Observer altitude in meters above sea level
+	DefaultMinElevation float64  `mapstructure:"default_min_elevation"`     // Minimum elevation in degrees used when not given explicitly
+	Watchlist           []int    `mapstructure:"watchlist"`                 // NORAD IDs of satellites the user tracks
+	JSONDecimals        int      `mapstructure:"json_decimals"`             // Decimal places for numeric fields in JSON output
+	JSONNaming          string   `mapstructure:"json_naming"`               // Key casing in JSON output: "camel" or "snake"
+	PropagationWorkers  int      `mapstructure:"propagation_workers"`       // Goroutines for catalog-wide propagation (0 = GOMAXPROCS)
+	TLEHistory          bool     `mapstructure:"tle_history"`               // Keep every TLE per NORAD ID in the feed instead of only the last
+	MaxTLEAge           float64  `mapstructure:"max_tle_age"`               // Days from its epoch beyond which a TLE is skipped by visible (0 = no limit)
+	IncludeSATCATOnly   bool     `mapstructure:"include_satcat_only"`       // Keep SATCAT entries without a TLE (e.g. decayed objects) in the catalog
+	CatalogSnapshots    bool     `mapstructure:"catalog_snapshots"`         // Keep a timestamped copy of every fetched catalog
+	MaxSnapshots        int      `mapstructure:"max_snapshots"`             // Number of catalog snapshots to keep, oldest pruned first (0 = all)
+
+	Sites map[string]Site `mapstructure:"sites"` // Named observer locations, selectable with --site
+}
+
+// Site is a named observer location.
+type Site struct {
+	Latitude  float64 `mapstructure:"latitude"`  // degrees
+	Longitude float64 `mapstructure:"longitude"` // degrees east
+	Altitude  float64 `mapstructure:"altitude"`  // meters above sea level
+}
+
+// BuiltinSites are well-known observatories available by name without configuration.
+// Sites in Config.Sites with the same name take precedence.
+var BuiltinSites = map[string]Site{
+	"greenwich": {Latitude: 51.4769, Longitude: -0.0005, Altitude: 46},
+	"maunakea":  {Latitude: 19.8206, Longitude: -155.4681, Altitude: 4205},
+	"paranal":   {Latitude: -24.6275, Longitude: -70.4044, Altitude: 2635},
 }
 
 // DefaultConfig returns a Config with sensible defaults.
 // Users can modify the returned config as needed before use.
 func DefaultConfig() *Config {
 	return &Config{
+		CatalogFormat:     CatalogFormatJSON,
+		CatalogBackend:    "file",
 		AutoFetch:         true,
 		APITimeout:        30,
+		APIRetries:        3,
+		APIRetryDelay:     1,
 		MaxCatalogAge:     24,
 		TLEEndpoint:       "https://spacebook.com/api/entity/tle",
 		SATCATEndpoint:    "https://spacebook.com/api/entity/satcat",
+		TLEObjectEndpoint: "https://celestrak.org/NORAD/elements/gp.php?CATNR={id}&FORMAT=TLE",
 		ObserverLatitude:  0.0,
 		ObserverLongitude: 0.0,
 		ObserverAltitude:  0.0,
+
+		DefaultMinElevation: 10.0,
+		MaxSnapshots:        30,
+		JSONDecimals:        3,
+		JSONNaming:          string(CamelCase),
 	}
 }
 
+// MergeOptions returns the options for merging fetched data into a catalog.
+func (c *Config) MergeOptions() MergeOptions {
+	return MergeOptions{TLEHistory: c.TLEHistory, IncludeSATCATOnly: c.IncludeSATCATOnly}
+}
+
 // IsCatalogStale checks if the catalog needs refreshing based on age.
 // Returns true if the catalog is nil, or if it exceeds MaxCatalogAge.
 // Returns false if MaxCatalogAge is 0 (no age limit) or if catalog is fresh.
@@ -45,3 +100,68 @@ func (c *Config) IsCatalogStale(catalog *Catalog) bool {
 	age := time.Since(catalog.FetchedAt)
 	return age > maxAge
 }
+
+// Observer returns the configured observer location, or nil if none is set.
+func (c *Config) Observer() *ObserverPosition {
+	if c.ObserverLatitude == 0.0 && c.ObserverLongitude == 0.0 {
+		return nil
+	}
+	return &ObserverPosition{
+		Latitude:  c.ObserverLatitude,
+		Longitude: c.ObserverLongitude,
+		Altitude:  c.ObserverAltitude,
+	}
+}
+
+// SiteObserver resolves a named site (case-insensitive) to an observer location,
+// looking in Sites first and then BuiltinSites.
+func (c *Config) SiteObserver(name string) (*ObserverPosition, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+
+	site, ok := c.Sites[key]
+	if !ok {
+		site, ok = BuiltinSites[key]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown site %q (known sites: %s)", name, strings.Join(c.SiteNames(), ", "))
+	}
+
+	observer := &ObserverPosition{
+		Latitude:  site.Latitude,
+		Longitude: site.Longitude,
+		Altitude:  site.Altitude,
+	}
+	if err := observer.Validate(); err != nil {
+		return nil, fmt.Errorf("site %q: %w", name, err)
+	}
+
+	return observer, nil
+}
+
+// SiteNames returns the sorted names of all configured and built-in sites.
+func (c *Config) SiteNames() []string {
+	seen := make(map[string]bool)
+	for name := range c.Sites {
+		seen[name] = true
+	}
+	for name := range BuiltinSites {
+		seen[name] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TLEEndpoints returns the primary TLE endpoint followed by any fallbacks.
+func (c *Config) TLEEndpoints() []string {
+	return append([]string{c.TLEEndpoint}, c.TLEFallbacks...)
+}
+
+// SATCATEndpoints returns the primary SATCAT endpoint followed by any fallbacks.
+func (c *Config) SATCATEndpoints() []string {
+	return append([]string{c.SATCATEndpoint}, c.SATCATFallbacks...)
+}
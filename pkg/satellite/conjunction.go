@@ -0,0 +1,324 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ClosestApproach finds when two satellites come closest together between
+// start and end and returns that time and their separation in km.
+// Both are sampled every coarseStep; the closest sample is refined by
+// golden-section search to one second and then by extrapolating the relative
+// motion linearly, since objects can close at over 10 km/s. coarseStep must
+// be short enough that the closest sample falls within one step of the true
+// minimum; tens of seconds suits LEO.
+func ClosestApproach(tleA, tleB *TLE, start, end time.Time, coarseStep time.Duration) (time.Time, float64, error) {
+	if coarseStep <= 0 {
+		return time.Time{}, 0, fmt.Errorf("step must be positive: %v", coarseStep)
+	}
+	if end.Before(start) {
+		return time.Time{}, 0, fmt.Errorf("end time must be after start time")
+	}
+
+	a, err := NewPropagator(tleA)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	b, err := NewPropagator(tleB)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return closestApproach(a, b, start, end, coarseStep)
+}
+
+// closestApproach is ClosestApproach for parsed TLEs.
+func closestApproach(a, b *Propagator, start, end time.Time, coarseStep time.Duration) (time.Time, float64, error) {
+	// Separation is frame-independent, so skip the rotation to ECEF
+	relative := func(t time.Time) (r, v [3]float64, err error) {
+		pa, err := a.teme(t)
+		if err != nil {
+			return r, v, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		pb, err := b.teme(t)
+		if err != nil {
+			return r, v, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		r = [3]float64{pb.X - pa.X, pb.Y - pa.Y, pb.Z - pa.Z}
+		v = [3]float64{pb.Vx - pa.Vx, pb.Vy - pa.Vy, pb.Vz - pa.Vz}
+		return r, v, nil
+	}
+	separation := func(t time.Time) (float64, error) {
+		r, _, err := relative(t)
+		if err != nil {
+			return 0, err
+		}
+		return math.Sqrt(r[0]*r[0] + r[1]*r[1] + r[2]*r[2]), nil
+	}
+
+	best, bestDist := start, math.Inf(1)
+	for t := start; ; t = t.Add(coarseStep) {
+		if t.After(end) {
+			t = end
+		}
+		d, err := separation(t)
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+		if d < bestDist {
+			best, bestDist = t, d
+		}
+		if !t.Before(end) {
+			break
+		}
+	}
+
+	lo, hi := best.Add(-coarseStep), best.Add(coarseStep)
+	if lo.Before(start) {
+		lo = start
+	}
+	if hi.After(end) {
+		hi = end
+	}
+	tca, _, err := goldenSectionMin(lo, hi, separation)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	// SGP4 resolves whole seconds; finish with straight-line relative motion
+	tca = tca.Truncate(time.Second)
+	r, v, err := relative(tca)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	speed2 := v[0]*v[0] + v[1]*v[1] + v[2]*v[2]
+	dt := 0.0
+	if speed2 > 0 {
+		dt = math.Max(-1, math.Min(1, -(r[0]*v[0]+r[1]*v[1]+r[2]*v[2])/speed2))
+	}
+	offset := time.Duration(dt * float64(time.Second))
+	if t := tca.Add(offset); !t.Before(start) && !t.After(end) {
+		tca = t
+	} else {
+		dt = 0
+	}
+
+	dx, dy, dz := r[0]+v[0]*dt, r[1]+v[1]*dt, r[2]+v[2]*dt
+	return tca, math.Sqrt(dx*dx + dy*dy + dz*dz), nil
+}
+
+const (
+	// screenStep is the coarse sampling interval for conjunction screening
+	screenStep = time.Minute
+	// screenRefineStep is the sampling interval within windows that survive pruning
+	screenRefineStep = 10 * time.Second
+	// screenShellMarginKm pads altitude shells for the difference between the
+	// mean elements they are derived from and SGP4's osculating state, which
+	// reaches tens of km through J2 short-period terms and drag
+	screenShellMarginKm = 50.0
+	// screenSpeedMarginKmS pads sampled speeds for acceleration between samples
+	screenSpeedMarginKmS = 1.0
+)
+
+// ConjunctionEvent is a close approach found by ScreenConjunctions.
+type ConjunctionEvent struct {
+	Satellite *Satellite // the other object
+	Time      time.Time  // time of closest approach
+	Distance  float64    // separation at Time in km
+}
+
+// screenSample is a TEME position and speed at one screening time.
+type screenSample struct {
+	x, y, z float64
+	speed   float64 // km/s
+}
+
+// ScreenConjunctions finds the satellites in catalog that pass within
+// thresholdKm of target between start and end, one event per local minimum
+// of their separation, sorted by time. An approach still closing at end is
+// reported at end. Candidates are pruned before any pairwise refinement:
+// first by altitude shells (perigee to apogee, padded by threshold and a
+// margin for mean-versus-osculating elements), then interval by interval
+// with bounding boxes around each object's coarse samples, grown by the
+// distance it can travel between them. Only windows whose boxes come within
+// thresholdKm are refined, each minimum in them as ClosestApproach. Satellites that fail to
+// propagate are skipped; the target failing is an error.
+func ScreenConjunctions(target *TLE, catalog []*Satellite, start, end time.Time, thresholdKm float64) ([]ConjunctionEvent, error) {
+	if thresholdKm <= 0 {
+		return nil, fmt.Errorf("threshold must be positive: %v", thresholdKm)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	targetProp, err := NewPropagator(target)
+	if err != nil {
+		return nil, err
+	}
+	targetApogee, targetPerigee, _, _, ok := tleOrbit(target)
+	if !ok {
+		return nil, fmt.Errorf("%w: no orbital elements", ErrTLEParse)
+	}
+	targetID := target.GetNoradID()
+
+	var times []time.Time
+	for t := start; ; t = t.Add(screenStep) {
+		if t.After(end) {
+			t = end
+		}
+		times = append(times, t)
+		if !t.Before(end) {
+			break
+		}
+	}
+	targetSamples, err := screenSamples(targetProp, times)
+	if err != nil {
+		return nil, err
+	}
+
+	margin := thresholdKm + screenShellMarginKm
+	var candidates []*Satellite
+	for _, sat := range catalog {
+		if sat.TLE == nil || sat.NoradID == targetID {
+			continue
+		}
+		apogee, perigee, _, _, ok := tleOrbit(sat.TLE)
+		if !ok || perigee > targetApogee+margin || apogee < targetPerigee-margin {
+			continue
+		}
+		candidates = append(candidates, sat)
+	}
+
+	found := make([][]ConjunctionEvent, len(candidates))
+	forEachParallel(len(candidates), 0, func(i int) {
+		found[i] = screenSatellite(targetProp, targetSamples, candidates[i], times, thresholdKm)
+	})
+	events := make([]ConjunctionEvent, 0)
+	for _, f := range found {
+		events = append(events, f...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
+
+// screenSamples propagates p to each of times.
+func screenSamples(p *Propagator, times []time.Time) ([]screenSample, error) {
+	samples := make([]screenSample, len(times))
+	for i, t := range times {
+		pos, err := p.teme(t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		samples[i] = screenSample{
+			x: pos.X, y: pos.Y, z: pos.Z,
+			speed: math.Sqrt(pos.Vx*pos.Vx + pos.Vy*pos.Vy + pos.Vz*pos.Vz),
+		}
+	}
+	return samples, nil
+}
+
+// screenSatellite returns the encounters of sat with the target closer than
+// thresholdKm. Consecutive intervals whose bounding boxes come within
+// thresholdKm are merged into one window and refined together.
+func screenSatellite(target *Propagator, targetSamples []screenSample, sat *Satellite, times []time.Time, thresholdKm float64) []ConjunctionEvent {
+	p, err := NewPropagator(sat.TLE)
+	if err != nil {
+		return nil
+	}
+	samples, err := screenSamples(p, times)
+	if err != nil {
+		return nil
+	}
+
+	var events []ConjunctionEvent
+	windowStart := -1
+	for i := 0; i < len(times); i++ {
+		near := i+1 < len(times) && boxesWithin(targetSamples[i], targetSamples[i+1], samples[i], samples[i+1],
+			times[i+1].Sub(times[i]), thresholdKm)
+		if near && windowStart < 0 {
+			windowStart = i
+		}
+		if near || windowStart < 0 {
+			continue
+		}
+
+		for _, approach := range windowApproaches(target, p, times[windowStart], times[i]) {
+			if approach.Distance < thresholdKm {
+				approach.Satellite = sat
+				events = append(events, approach)
+			}
+		}
+		windowStart = -1
+	}
+	return events
+}
+
+// windowApproaches returns one closest approach per local minimum of the
+// separation between start and end, so that a window spanning several
+// encounters, such as objects sharing an orbit, reports each of them. The
+// separation is sampled every screenRefineStep and each minimum refined as
+// ClosestApproach. The events have no Satellite set.
+func windowApproaches(target, p *Propagator, start, end time.Time) []ConjunctionEvent {
+	var times []time.Time
+	var distances []float64
+	for t := start; ; t = t.Add(screenRefineStep) {
+		if t.After(end) {
+			t = end
+		}
+		pa, errA := target.teme(t)
+		pb, errB := p.teme(t)
+		if errA != nil || errB != nil {
+			return nil
+		}
+		times = append(times, t)
+		distances = append(distances, math.Sqrt((pb.X-pa.X)*(pb.X-pa.X)+(pb.Y-pa.Y)*(pb.Y-pa.Y)+(pb.Z-pa.Z)*(pb.Z-pa.Z)))
+		if !t.Before(end) {
+			break
+		}
+	}
+
+	var events []ConjunctionEvent
+	last := len(times) - 1
+	for k := range times {
+		// Equal neighbors count once, at the last sample of a flat bottom
+		if (k > 0 && distances[k] > distances[k-1]) || (k < last && distances[k] >= distances[k+1]) {
+			continue
+		}
+		lo, hi := times[max(k-1, 0)], times[min(k+1, last)]
+		tca, distance, err := closestApproach(target, p, lo, hi, screenRefineStep)
+		if err != nil {
+			continue
+		}
+		events = append(events, ConjunctionEvent{Time: tca, Distance: distance})
+	}
+	return events
+}
+
+// boxesWithin reports whether two objects can come within thresholdKm over an
+// interval of length dt, given their samples at either end. Each object's
+// box spans its two samples, grown by the farthest it can be from the nearer
+// one; the boxes are compared axis by axis.
+func boxesWithin(a0, a1, b0, b1 screenSample, dt time.Duration, thresholdKm float64) bool {
+	half := dt.Seconds() / 2
+	padA := (math.Max(a0.speed, a1.speed) + screenSpeedMarginKmS) * half
+	padB := (math.Max(b0.speed, b1.speed) + screenSpeedMarginKmS) * half
+
+	axes := [3][4]float64{
+		{a0.x, a1.x, b0.x, b1.x},
+		{a0.y, a1.y, b0.y, b1.y},
+		{a0.z, a1.z, b0.z, b1.z},
+	}
+	for _, v := range axes {
+		loA, hiA := math.Min(v[0], v[1])-padA, math.Max(v[0], v[1])+padA
+		loB, hiB := math.Min(v[2], v[3])-padB, math.Max(v[2], v[3])+padB
+		if loA > hiB+thresholdKm || loB > hiA+thresholdKm {
+			return false
+		}
+	}
+	return true
+}
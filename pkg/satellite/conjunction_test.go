@@ -0,0 +1,161 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// tleSeparation returns the distance in km between two TLEs' positions at t.
+func tleSeparation(t *testing.T, a, b *TLE, at time.Time) float64 {
+	t.Helper()
+	pa, err := PropagateSatellite(a, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := PropagateSatellite(b, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return math.Sqrt((pa.X-pb.X)*(pa.X-pb.X) + (pa.Y-pb.Y)*(pa.Y-pb.Y) + (pa.Z-pb.Z)*(pa.Z-pb.Z))
+}
+
+func TestClosestApproachCoplanar(t *testing.T) {
+	// Two circular orbits in the same plane: B starts 1° behind A with a mean
+	// motion 0.01 rev/day higher, so it gains 3.6°/day and overtakes A after
+	// about 6.7 hours, passing below it by the difference in semi-major axis,
+	// (2/3)·a·Δn/n ≈ 2.9 km
+	const n, dn = 15.5, 0.01
+	a := makeTLE(30000, testEpoch, 51.6, 100, 0.0001, 0, 1, n)
+	b := makeTLE(30001, testEpoch, 51.6, 100, 0.0001, 0, 0, n+dn)
+	wantTime := testEpoch.Add(time.Duration(1.0 / (dn * 360) * float64(24*time.Hour)))
+	semiMajor := math.Cbrt(398600.4418 / math.Pow(n*2*math.Pi/86400, 2))
+	wantDist := 2.0 / 3 * semiMajor * dn / n
+
+	tca, dist, err := ClosestApproach(a, b, testEpoch, testEpoch.Add(12*time.Hour), 30*time.Second)
+	if err != nil {
+		t.Fatalf("ClosestApproach: %v", err)
+	}
+	if d := tca.Sub(wantTime).Abs(); d > 15*time.Minute {
+		t.Errorf("closest approach at %v, want about %v", tca, wantTime)
+	}
+	assertNear(t, "miss distance", dist, wantDist, 1)
+
+	// No second of the surrounding minutes comes closer than the refined minimum
+	for at := tca.Add(-2 * time.Minute).Truncate(time.Second); at.Before(tca.Add(2 * time.Minute)); at = at.Add(time.Second) {
+		if d := tleSeparation(t, a, b, at); d < dist-0.01 {
+			t.Errorf("separation %.3f km at %v, below the refined minimum %.3f km", d, at, dist)
+		}
+	}
+
+	if _, _, err := ClosestApproach(a, b, testEpoch, testEpoch.Add(time.Hour), 0); err == nil {
+		t.Error("ClosestApproach accepted a zero step")
+	}
+	if _, _, err := ClosestApproach(a, b, testEpoch.Add(time.Hour), testEpoch, time.Minute); err == nil {
+		t.Error("ClosestApproach accepted an end before the start")
+	}
+}
+
+func TestScreenConjunctionsFindsEveryMinimum(t *testing.T) {
+	const threshold = 10.0
+	start, end := testEpoch, testEpoch.Add(6*time.Hour)
+	target := makeTLE(30000, testEpoch, 51.6, 100, 0.0001, 0, 0, 15.5)
+
+	catalog := []*Satellite{
+		testSatellite("TARGET", target),
+		// Overtakes the target from 1° behind, 8 km lower: the altitude
+		// shells only overlap once padded by the threshold
+		testSatellite("UNDERTAKER", makeTLE(30001, testEpoch, 51.6, 100, 0.0001, 0, 359, 15.5274)),
+		// Shares the target's orbit a few km behind, with a slightly more
+		// eccentric shape, so they close in again every revolution
+		testSatellite("COORBITAL", makeTLE(30002, testEpoch, 51.6, 100, 0.0006, 0, 359.96, 15.5)),
+		// Crosses the target's plane; no approach within the threshold
+		testSatellite("CROSSING", makeTLE(30003, testEpoch, 97.5, 190, 0.001, 0, 0, 15.2)),
+		testSatellite("GEO", geoTLE()),
+		{NoradID: 30004, Name: "NO TLE"},
+	}
+	catalog = append(catalog, syntheticCatalog(40)...)
+
+	events, err := ScreenConjunctions(target, catalog, start, end, threshold)
+	if err != nil {
+		t.Fatalf("ScreenConjunctions: %v", err)
+	}
+
+	// Brute force every satellite without pruning: sample every 10 s and
+	// refine each local minimum
+	var want []ConjunctionEvent
+	for _, sat := range catalog {
+		if sat.TLE == nil || sat.NoradID == 30000 {
+			continue
+		}
+		if _, err := NewPropagator(sat.TLE); err != nil {
+			continue
+		}
+		// Past either end of the window counts as infinitely far, so an
+		// approach still closing at the end is a minimum there
+		prev, prevPrev := math.Inf(1), math.Inf(1)
+		for at := start; !at.After(end.Add(10 * time.Second)); at = at.Add(10 * time.Second) {
+			d := math.Inf(1)
+			if !at.After(end) {
+				d = tleSeparation(t, target, sat.TLE, at)
+			}
+			if prev < prevPrev && prev <= d && prev < threshold+5 {
+				lo, hi := at.Add(-20*time.Second), at
+				lo, hi = maxTime(lo, start), minTime(hi, end)
+				tca, dist, err := ClosestApproach(target, sat.TLE, lo, hi, time.Second)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if dist < threshold {
+					want = append(want, ConjunctionEvent{Satellite: sat, Time: tca, Distance: dist})
+				}
+			}
+			prevPrev, prev = prev, d
+		}
+	}
+
+	coorbital := 0
+	for _, w := range want {
+		if w.Satellite.Name == "COORBITAL" {
+			coorbital++
+		}
+	}
+	if coorbital < 3 {
+		t.Fatalf("brute force found %d co-orbital approaches, want one per revolution", coorbital)
+	}
+
+	if len(events) != len(want) {
+		t.Errorf("screening found %d events, brute force %d", len(events), len(want))
+	}
+	for _, w := range want {
+		found := false
+		for _, e := range events {
+			if e.Satellite == w.Satellite && e.Time.Sub(w.Time).Abs() < 30*time.Second {
+				found = true
+				assertNear(t, w.Satellite.Name+" miss distance", e.Distance, w.Distance, 0.05)
+			}
+		}
+		if !found {
+			t.Errorf("screening missed %s at %v, %.2f km", w.Satellite.Name, w.Time, w.Distance)
+		}
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Time.Before(events[i-1].Time) {
+			t.Errorf("events not sorted by time: %v after %v", events[i].Time, events[i-1].Time)
+		}
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,179 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// CoverageCell is one cell of a coverage grid with the number of satellites
+// visible from its center.
+type CoverageCell struct {
+	Latitude  float64 // cell center latitude in degrees
+	Longitude float64 // cell center longitude in degrees
+	Count     int     // satellites above the minimum elevation
+}
+
+// footprintHalfAngle returns the Earth central angle in radians between the
+// sub-satellite point and the edge of the region from which a satellite at
+// geocentric distance r (km) appears above minElevation (degrees).
+// Assumes a spherical Earth; returns 0 if the satellite is below the surface.
+func footprintHalfAngle(r, minElevation float64) float64 {
+	if r <= earthRadiusKm {
+		return 0
+	}
+	el := minElevation * math.Pi / 180.0
+	return math.Acos(earthRadiusKm*math.Cos(el)/r) - el
+}
+
+// GroundPoint is a location on the Earth's surface.
+type GroundPoint struct {
+	Latitude  float64 // degrees
+	Longitude float64 // degrees, -180 to 180
+}
+
+// FootprintPolygon returns the boundary of the region from which the satellite
+// is above minElevation as a closed ring of numPoints+1 points (the first point
+// is repeated at the end), ordered clockwise starting due north of the subpoint.
+// Assumes a spherical Earth. Longitudes are normalized to [-180, 180), so rings
+// that cross the antimeridian jump across it and rings that enclose a pole
+// span every longitude; map renderers should split them accordingly.
+// Returns nil if numPoints < 3 or the satellite has no footprint.
+func FootprintPolygon(satPos *SatellitePosition, minElevation float64, numPoints int) []GroundPoint {
+	if numPoints < 3 {
+		return nil
+	}
+
+	r := math.Sqrt(satPos.X*satPos.X + satPos.Y*satPos.Y + satPos.Z*satPos.Z)
+	half := footprintHalfAngle(r, minElevation)
+	if half <= 0 {
+		return nil
+	}
+
+	// Geocentric subpoint, consistent with the spherical footprint
+	lat1 := math.Atan2(satPos.Z, math.Hypot(satPos.X, satPos.Y))
+	lon1 := math.Atan2(satPos.Y, satPos.X)
+	sinLat1, cosLat1 := math.Sin(lat1), math.Cos(lat1)
+	sinHalf, cosHalf := math.Sin(half), math.Cos(half)
+
+	ring := make([]GroundPoint, 0, numPoints+1)
+	for i := 0; i < numPoints; i++ {
+		bearing := 2 * math.Pi * float64(i) / float64(numPoints)
+
+		// Destination point at central angle half along the bearing
+		lat2 := math.Asin(math.Max(-1, math.Min(1, sinLat1*cosHalf+cosLat1*sinHalf*math.Cos(bearing))))
+		lon2 := lon1 + math.Atan2(math.Sin(bearing)*sinHalf*cosLat1, cosHalf-sinLat1*math.Sin(lat2))
+		if cosLat1 < 1e-9 {
+			// Directly over a pole every bearing is along a meridian
+			if sinLat1 > 0 {
+				lon2 = lon1 + math.Pi - bearing
+			} else {
+				lon2 = lon1 + bearing
+			}
+		}
+
+		ring = append(ring, GroundPoint{
+			Latitude:  lat2 * 180.0 / math.Pi,
+			Longitude: NormalizeLongitude(lon2 * 180.0 / math.Pi),
+		})
+	}
+
+	return append(ring, ring[0])
+}
+
+// FootprintRadiusKm returns the radius, measured along the Earth's surface in
+// km, of the region from which the satellite is above the horizon. On a
+// spherical Earth of radius R this is R·acos(R/r) for a satellite at
+// geocentric distance r. Returns 0 if the satellite is below the surface.
+func FootprintRadiusKm(pos *SatellitePosition) float64 {
+	r := math.Sqrt(pos.X*pos.X + pos.Y*pos.Y + pos.Z*pos.Z)
+	return earthRadiusKm * footprintHalfAngle(r, 0)
+}
+
+// FootprintCircle returns the edge of the region from which the satellite is
+// above the horizon as a closed ring of points+1 [latitude, longitude] pairs
+// in degrees, as FootprintPolygon does at zero elevation.
+func FootprintCircle(pos *SatellitePosition, points int) [][2]float64 {
+	ring := FootprintPolygon(pos, 0, points)
+	if ring == nil {
+		return nil
+	}
+	circle := make([][2]float64, len(ring))
+	for i, p := range ring {
+		circle[i] = [2]float64{p.Latitude, p.Longitude}
+	}
+	return circle
+}
+
+// CoverageGrid counts, for each cell of a lat/lon grid with the given resolution,
+// how many satellites are above minElevation as seen from the cell center at time t.
+// Satellites that fail to propagate are skipped. Cells are returned row by row
+// from south to north, west to east.
+// If progress is non-nil it is called as grid rows are completed.
+func CoverageGrid(satellites []*Satellite, gridResDeg, minElevation float64, t time.Time, progress ProgressFunc) ([]CoverageCell, error) {
+	if gridResDeg <= 0 || gridResDeg > 90 {
+		return nil, fmt.Errorf("grid resolution must be in (0, 90] degrees: %f", gridResDeg)
+	}
+
+	// Propagate each satellite once and precompute its footprint
+	type candidate struct {
+		pos          *SatellitePosition
+		ux, uy, uz   float64 // geocentric unit vector
+		cosHalfAngle float64 // cosine of footprint half angle, with margin
+	}
+	candidates := make([]candidate, 0, len(satellites))
+	for _, sat := range satellites {
+		if sat.TLE == nil {
+			continue
+		}
+		pos, err := PropagateSatellite(sat.TLE, t)
+		if err != nil {
+			continue
+		}
+		r := math.Sqrt(pos.X*pos.X + pos.Y*pos.Y + pos.Z*pos.Z)
+		half := footprintHalfAngle(r, minElevation)
+		if half <= 0 {
+			continue
+		}
+		// Pad the spherical footprint to absorb Earth oblateness before the exact check
+		candidates = append(candidates, candidate{
+			pos: pos,
+			ux:  pos.X / r, uy: pos.Y / r, uz: pos.Z / r,
+			cosHalfAngle: math.Cos(math.Min(math.Pi, half+0.01)),
+		})
+	}
+
+	rows := int(math.Ceil(180.0 / gridResDeg))
+	cols := int(math.Ceil(360.0 / gridResDeg))
+	cells := make([]CoverageCell, rows*cols)
+	counter := newProgressCounter(rows, progress)
+
+	// Fan rows out across workers; each row writes only its own cells
+	forEachParallel(rows, 0, func(i int) {
+		lat := math.Min(-90.0+(float64(i)+0.5)*gridResDeg, 90.0)
+		latRad := lat * math.Pi / 180.0
+		for j := 0; j < cols; j++ {
+			lon := math.Min(-180.0+(float64(j)+0.5)*gridResDeg, 180.0)
+			lonRad := lon * math.Pi / 180.0
+			cx := math.Cos(latRad) * math.Cos(lonRad)
+			cy := math.Cos(latRad) * math.Sin(lonRad)
+			cz := math.Sin(latRad)
+
+			observer := &ObserverPosition{Latitude: lat, Longitude: lon}
+			count := 0
+			for _, c := range candidates {
+				if cx*c.ux+cy*c.uy+cz*c.uz < c.cosHalfAngle {
+					continue
+				}
+				if CalculateObservationAngles(c.pos, observer).Elevation >= minElevation {
+					count++
+				}
+			}
+			cells[i*cols+j] = CoverageCell{Latitude: lat, Longitude: lon, Count: count}
+		}
+		counter.step()
+	})
+	counter.finish()
+
+	return cells, nil
+}
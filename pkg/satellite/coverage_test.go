@@ -0,0 +1,123 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+)
+
+// centralAngle returns the great-circle angle in degrees between two points.
+func centralAngle(lat1, lon1, lat2, lon2 float64) float64 {
+	const deg = math.Pi / 180
+	cos := math.Sin(lat1*deg)*math.Sin(lat2*deg) + math.Cos(lat1*deg)*math.Cos(lat2*deg)*math.Cos((lon2-lon1)*deg)
+	return math.Acos(math.Max(-1, math.Min(1, cos))) / deg
+}
+
+func TestCoverageGridSingleGEO(t *testing.T) {
+	sat := testSatellite("GEO", geoTLE())
+	pos, err := PropagateSatellite(sat.TLE, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subLat, subLon, _ := SubSatellitePoint(pos)
+
+	cells, err := CoverageGrid([]*Satellite{sat}, 10, 10, testEpoch, nil)
+	if err != nil {
+		t.Fatalf("CoverageGrid: %v", err)
+	}
+	if len(cells) != 18*36 {
+		t.Fatalf("got %d cells, want 18x36", len(cells))
+	}
+
+	// A GEO satellite is above 10° elevation within ~71° of its subpoint
+	covered := 0
+	for _, cell := range cells {
+		angle := centralAngle(subLat, subLon, cell.Latitude, cell.Longitude)
+		switch {
+		case angle < 65 && cell.Count != 1:
+			t.Errorf("cell %.0f,%.0f at %.0f° from the subpoint has count %d, want 1", cell.Latitude, cell.Longitude, angle, cell.Count)
+		case angle > 78 && cell.Count != 0:
+			t.Errorf("cell %.0f,%.0f at %.0f° from the subpoint has count %d, want 0", cell.Latitude, cell.Longitude, angle, cell.Count)
+		}
+		covered += cell.Count
+	}
+	if covered == 0 || covered == len(cells) {
+		t.Errorf("%d of %d cells covered, want a bounded region", covered, len(cells))
+	}
+
+	if _, err := CoverageGrid(nil, 0, 10, testEpoch, nil); err == nil {
+		t.Error("CoverageGrid accepted a zero resolution")
+	}
+}
+
+func TestFootprintPolygonGroundRange(t *testing.T) {
+	// Satellites 500 km up over the equator, the antimeridian and a pole
+	r := earthRadiusKm + 500
+	const deg = math.Pi / 180
+	position := func(lat, lon float64) *SatellitePosition {
+		return &SatellitePosition{
+			X: r * math.Cos(lat*deg) * math.Cos(lon*deg),
+			Y: r * math.Cos(lat*deg) * math.Sin(lon*deg),
+			Z: r * math.Sin(lat*deg),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+	}{
+		{"equator", 0, 30},
+		{"antimeridian", 45, 179},
+		{"north pole", 90, 0},
+		{"near south pole", -85, -60},
+	}
+	for _, minElevation := range []float64{0, 10} {
+		// Ground range to the edge, from the triangle formed by the Earth's
+		// center, the satellite and an observer seeing it at minElevation
+		el := minElevation * deg
+		nadir := math.Asin(earthRadiusKm * math.Cos(el) / r)
+		want := earthRadiusKm * (math.Pi/2 - el - nadir)
+
+		for _, tt := range tests {
+			ring := FootprintPolygon(position(tt.lat, tt.lon), minElevation, 36)
+			if len(ring) != 37 || ring[0] != ring[36] {
+				t.Fatalf("%s: ring of %d points, want 36 closed with a repeat", tt.name, len(ring))
+			}
+			for _, p := range ring {
+				got := earthRadiusKm * centralAngle(tt.lat, tt.lon, p.Latitude, p.Longitude) * deg
+				assertNear(t, tt.name+" ground range", got, want, 0.01)
+				if p.Longitude < -180 || p.Longitude >= 180 {
+					t.Errorf("%s: longitude %v outside [-180, 180)", tt.name, p.Longitude)
+				}
+			}
+		}
+	}
+
+	if ring := FootprintPolygon(position(0, 0), 0, 2); ring != nil {
+		t.Errorf("FootprintPolygon with 2 points = %v, want nil", ring)
+	}
+	if ring := FootprintPolygon(&SatellitePosition{X: earthRadiusKm - 1}, 0, 36); ring != nil {
+		t.Errorf("FootprintPolygon below the surface = %v, want nil", ring)
+	}
+}
+
+func TestFootprintRadiusKm(t *testing.T) {
+	prev := 0.0
+	for _, altKm := range []float64{-10, 0, 200, 420, 1200, 20200, 35786} {
+		r := earthRadiusKm + altKm
+		got := FootprintRadiusKm(&SatellitePosition{X: r * 0.6, Y: r * 0.8})
+
+		want := 0.0
+		if altKm > 0 {
+			want = earthRadiusKm * math.Acos(earthRadiusKm/r)
+		}
+		assertNear(t, "footprint radius", got, want, 1e-9)
+		if altKm > 0 && got <= prev {
+			t.Errorf("footprint at %.0f km (%.0f km) is not larger than the one below it (%.0f km)", altKm, got, prev)
+		}
+		prev = got
+	}
+
+	// A GEO satellite sees out to about 81° of arc
+	r := earthRadiusKm + 35786
+	assertNear(t, "GEO footprint angle", FootprintRadiusKm(&SatellitePosition{Z: r})/earthRadiusKm*180/math.Pi, 81.3, 0.1)
+}
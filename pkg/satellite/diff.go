@@ -0,0 +1,118 @@
+package satellite
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Fields reported in SatelliteChange.Fields
+const (
+	ChangedTLE         = "tle"
+	ChangedPeriod      = "period"
+	ChangedInclination = "inclination"
+	ChangedApogee      = "apogee"
+	ChangedPerigee     = "perigee"
+)
+
+// Smallest differences in SATCAT orbital parameters that DiffCatalogs reports
+const (
+	diffPeriodTolerance      = 0.01 // minutes
+	diffInclinationTolerance = 0.01 // degrees
+	diffAltitudeTolerance    = 1.0  // km, for apogee and perigee
+)
+
+// CatalogDiff describes how one catalog differs from an older one.
+type CatalogDiff struct {
+	Added   []int             // NORAD IDs only in the new catalog, ascending
+	Removed []int             // NORAD IDs only in the old catalog, ascending
+	Changed []SatelliteChange // satellites in both that changed, by NORAD ID
+}
+
+// SatelliteChange describes a satellite that changed between two catalogs.
+type SatelliteChange struct {
+	NoradID int
+	Name    string   // name in the new catalog
+	Fields  []string // Changed* fields that differ, TLE first
+}
+
+// TLEChanged reports whether the satellite has a new TLE.
+func (c SatelliteChange) TLEChanged() bool {
+	return len(c.Fields) > 0 && c.Fields[0] == ChangedTLE
+}
+
+// DiffCatalogs compares newer against older and returns the satellites added,
+// removed, and materially changed. A TLE has changed if either line, and so
+// possibly the epoch, differs, or if one was added or removed; SATCAT period,
+// inclination, apogee, and perigee have changed if they differ by more than
+// rounding. A nil catalog counts as empty.
+func DiffCatalogs(older, newer *Catalog) CatalogDiff {
+	oldSats := make(map[int]*Satellite)
+	if older != nil {
+		for _, sat := range older.Satellites {
+			oldSats[sat.NoradID] = sat
+		}
+	}
+
+	diff := CatalogDiff{Added: []int{}, Removed: []int{}, Changed: []SatelliteChange{}}
+	seen := make(map[int]bool, len(oldSats))
+	if newer != nil {
+		for _, sat := range newer.Satellites {
+			seen[sat.NoradID] = true
+			old, ok := oldSats[sat.NoradID]
+			if !ok {
+				diff.Added = append(diff.Added, sat.NoradID)
+				continue
+			}
+			if fields := changedFields(old, sat); len(fields) > 0 {
+				diff.Changed = append(diff.Changed, SatelliteChange{NoradID: sat.NoradID, Name: sat.Name, Fields: fields})
+			}
+		}
+	}
+	for id := range oldSats {
+		if !seen[id] {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	sort.Ints(diff.Added)
+	sort.Ints(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].NoradID < diff.Changed[j].NoradID
+	})
+	return diff
+}
+
+// changedFields lists the Changed* fields that differ materially between two
+// versions of a satellite.
+func changedFields(old, sat *Satellite) []string {
+	var fields []string
+	if tleChanged(old.TLE, sat.TLE) {
+		fields = append(fields, ChangedTLE)
+	}
+	differs := func(a, b, tolerance float64) bool { return math.Abs(a-b) > tolerance }
+	if differs(old.Period, sat.Period, diffPeriodTolerance) {
+		fields = append(fields, ChangedPeriod)
+	}
+	if differs(old.Inclination, sat.Inclination, diffInclinationTolerance) {
+		fields = append(fields, ChangedInclination)
+	}
+	if differs(old.Apogee, sat.Apogee, diffAltitudeTolerance) {
+		fields = append(fields, ChangedApogee)
+	}
+	if differs(old.Perigee, sat.Perigee, diffAltitudeTolerance) {
+		fields = append(fields, ChangedPerigee)
+	}
+	return fields
+}
+
+// tleChanged reports whether two TLEs differ in either line, ignoring
+// surrounding whitespace. The epoch is part of line 1, so a new epoch is
+// always a change.
+func tleChanged(a, b *TLE) bool {
+	if a == nil || b == nil {
+		return a != b
+	}
+	return strings.TrimSpace(a.Line1) != strings.TrimSpace(b.Line1) ||
+		strings.TrimSpace(a.Line2) != strings.TrimSpace(b.Line2)
+}
@@ -0,0 +1,70 @@
+package satellite
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	satellite := func(id int, tle *TLE, period, incl, apogee, perigee float64) *Satellite {
+		return &Satellite{NoradID: id, Name: "SAT", TLE: tle, Period: period, Inclination: incl, Apogee: apogee, Perigee: perigee}
+	}
+	leo := func(id int, epoch time.Time) *TLE { return makeTLE(id, epoch, 53, 20, 0.001, 0, 0, 15.1) }
+	padded := leo(30005, testEpoch)
+	padded.Line1 += "  "
+
+	older := &Catalog{Satellites: []*Satellite{
+		satellite(30000, leo(30000, testEpoch), 95, 53, 550, 540),     // unchanged
+		satellite(30001, leo(30001, testEpoch), 95, 53, 550, 540),     // removed
+		satellite(30002, leo(30002, testEpoch), 95, 53, 550, 540),     // new TLE epoch
+		satellite(30003, leo(30003, testEpoch), 95, 53, 550, 540),     // orbit raised
+		satellite(30004, nil, 95, 53, 550, 540),                       // TLE added
+		satellite(30005, leo(30005, testEpoch), 95.004, 53, 550, 540), // changes within rounding
+		satellite(30006, leo(30006, testEpoch), 95, 53, 550, 540),     // TLE dropped, inclination changed
+	}}
+	newer := &Catalog{Satellites: []*Satellite{
+		satellite(30000, leo(30000, testEpoch), 95, 53, 550, 540),
+		satellite(30002, leo(30002, testEpoch.Add(time.Hour)), 95, 53, 550, 540),
+		satellite(30003, leo(30003, testEpoch), 97, 53, 700, 690),
+		satellite(30004, leo(30004, testEpoch), 95, 53, 550, 540),
+		satellite(30005, padded, 95, 53.005, 550.5, 539.5),
+		satellite(30006, nil, 95, 53.2, 550, 540),
+		satellite(30008, leo(30008, testEpoch), 95, 53, 550, 540), // added
+		satellite(30007, leo(30007, testEpoch), 95, 53, 550, 540), // added, out of order
+	}}
+
+	diff := DiffCatalogs(older, newer)
+	if want := []int{30007, 30008}; !slices.Equal(diff.Added, want) {
+		t.Errorf("added = %v, want %v", diff.Added, want)
+	}
+	if want := []int{30001}; !slices.Equal(diff.Removed, want) {
+		t.Errorf("removed = %v, want %v", diff.Removed, want)
+	}
+	want := []SatelliteChange{
+		{NoradID: 30002, Name: "SAT", Fields: []string{ChangedTLE}},
+		{NoradID: 30003, Name: "SAT", Fields: []string{ChangedPeriod, ChangedApogee, ChangedPerigee}},
+		{NoradID: 30004, Name: "SAT", Fields: []string{ChangedTLE}},
+		{NoradID: 30006, Name: "SAT", Fields: []string{ChangedTLE, ChangedInclination}},
+	}
+	if !reflect.DeepEqual(diff.Changed, want) {
+		t.Errorf("changed = %+v, want %+v", diff.Changed, want)
+	}
+	for _, change := range diff.Changed {
+		if change.TLEChanged() != (change.NoradID != 30003) {
+			t.Errorf("NORAD %d TLEChanged = %v", change.NoradID, change.TLEChanged())
+		}
+	}
+
+	// A nil catalog counts as empty
+	if diff := DiffCatalogs(nil, older); len(diff.Added) != len(older.Satellites) || len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+		t.Errorf("diff against nil = %+v, want every satellite added", diff)
+	}
+	if diff := DiffCatalogs(older, nil); len(diff.Removed) != len(older.Satellites) || len(diff.Added) != 0 {
+		t.Errorf("diff to nil = %+v, want every satellite removed", diff)
+	}
+	if diff := DiffCatalogs(older, older); len(diff.Added)+len(diff.Removed)+len(diff.Changed) != 0 {
+		t.Errorf("diff of a catalog with itself = %+v, want none", diff)
+	}
+}
@@ -0,0 +1,111 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// speedOfLightKmS is the speed of light in km/s
+const speedOfLightKmS = 299792.458
+
+// dopplerShift returns the Doppler shift in Hz of a signal at frequency (Hz)
+// for a given range rate in km/s. Approaching satellites (negative range rate)
+// give a positive shift.
+func dopplerShift(frequency, rangeRate float64) float64 {
+	return -frequency * rangeRate / speedOfLightKmS
+}
+
+// DopplerShift returns the frequency in Hz at which a signal transmitted at
+// baseFreqHz is received, given the observation's range rate.
+func DopplerShift(angles *ObservationAngles, baseFreqHz float64) float64 {
+	return baseFreqHz + dopplerShift(baseFreqHz, angles.RangeRate)
+}
+
+// PassDopplerProfile summarizes the Doppler shift over a pass.
+type PassDopplerProfile struct {
+	MaxUpshift   float64   // largest positive shift in Hz (satellite approaching)
+	MaxDownshift float64   // largest negative shift in Hz (satellite receding)
+	PeakRate     float64   // largest magnitude rate of change in Hz/s
+	PeakRateTime time.Time // time of PeakRate, near closest approach
+}
+
+// PassDoppler computes the maximum Doppler upshift, downshift, and peak Doppler
+// rate over a pass for a signal at frequency (Hz).
+// The pass is sampled every second from AOS to LOS.
+func PassDoppler(pass *Pass, tle *TLE, observer *ObserverPosition, frequency float64) (*PassDopplerProfile, error) {
+	const step = time.Second
+
+	profile := &PassDopplerProfile{}
+	var prevShift float64
+	var prevTime time.Time
+
+	for t := pass.AOS; !t.After(pass.LOS); t = t.Add(step) {
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		shift := dopplerShift(frequency, obs.RangeRate)
+		profile.MaxUpshift = math.Max(profile.MaxUpshift, shift)
+		profile.MaxDownshift = math.Min(profile.MaxDownshift, shift)
+
+		if !prevTime.IsZero() {
+			rate := (shift - prevShift) / t.Sub(prevTime).Seconds()
+			if math.Abs(rate) > math.Abs(profile.PeakRate) {
+				profile.PeakRate = rate
+				// Attribute the rate to the middle of the interval
+				profile.PeakRateTime = prevTime.Add(t.Sub(prevTime) / 2)
+			}
+		}
+		prevShift, prevTime = shift, t
+	}
+
+	return profile, nil
+}
+
+// DopplerSample is the Doppler-shifted frequency of a signal at one instant.
+type DopplerSample struct {
+	Time      time.Time
+	Azimuth   float64 // degrees
+	Elevation float64 // degrees
+	RangeRate float64 // km/s, negative while approaching
+	Frequency float64 // received frequency in Hz
+	Shift     float64 // Frequency minus the transmitted frequency in Hz
+}
+
+// DopplerShiftRange samples the received frequency of a signal transmitted at
+// baseFreqHz every step from AOS to LOS inclusive. The shift is first order in
+// range rate; the relativistic correction is below a hertz at UHF even for
+// the fastest LEO passes.
+func DopplerShiftRange(pass *Pass, tle *TLE, observer *ObserverPosition, baseFreqHz float64, step time.Duration) ([]DopplerSample, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive: %v", step)
+	}
+
+	var samples []DopplerSample
+	for t := pass.AOS; ; t = t.Add(step) {
+		if t.After(pass.LOS) {
+			t = pass.LOS
+		}
+
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		frequency := DopplerShift(obs, baseFreqHz)
+		samples = append(samples, DopplerSample{
+			Time:      t,
+			Azimuth:   obs.Azimuth,
+			Elevation: obs.Elevation,
+			RangeRate: obs.RangeRate,
+			Frequency: frequency,
+			Shift:     frequency - baseFreqHz,
+		})
+
+		if !t.Before(pass.LOS) {
+			return samples, nil
+		}
+	}
+}
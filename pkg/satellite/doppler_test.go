@@ -0,0 +1,54 @@
+package satellite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPassDopplerZeroCrossingAtClosestApproach(t *testing.T) {
+	const frequency = 437e6
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1.6}
+	passes, err := PredictPasses(tle, observer, testEpoch, testEpoch.Add(24*time.Hour), 30*time.Second, 0)
+	if err != nil || len(passes) == 0 {
+		t.Fatalf("PredictPasses = %d passes, %v", len(passes), err)
+	}
+
+	for _, pass := range passes {
+		if pass.AOS.Equal(testEpoch) || pass.LOS.Equal(testEpoch.Add(24*time.Hour)) {
+			continue // cut off by the window
+		}
+
+		profile, err := PassDoppler(&pass, tle, observer, frequency)
+		if err != nil {
+			t.Fatalf("PassDoppler: %v", err)
+		}
+		// ISS range rates approach ±7 km/s, about ±10 kHz at 437 MHz
+		if profile.MaxUpshift <= 0 || profile.MaxUpshift > 11e3 || profile.MaxDownshift >= 0 || profile.MaxDownshift < -11e3 {
+			t.Errorf("pass at %v: shifts %+.0f/%+.0f Hz, want an upshift then a downshift", pass.AOS, profile.MaxUpshift, profile.MaxDownshift)
+		}
+		if profile.PeakRate >= 0 {
+			t.Errorf("pass at %v: peak rate %+.1f Hz/s, want the frequency falling", pass.AOS, profile.PeakRate)
+		}
+		if d := profile.PeakRateTime.Sub(pass.MinRangeTime).Abs(); d > 30*time.Second {
+			t.Errorf("pass at %v: peak rate %v from closest approach", pass.AOS, d)
+		}
+
+		samples, err := DopplerShiftRange(&pass, tle, observer, frequency, time.Second)
+		if err != nil {
+			t.Fatalf("DopplerShiftRange: %v", err)
+		}
+		crossings := 0
+		for i := 1; i < len(samples); i++ {
+			if samples[i-1].Shift > 0 && samples[i].Shift <= 0 {
+				crossings++
+				if d := samples[i].Time.Sub(pass.MinRangeTime).Abs(); d > 2*time.Second {
+					t.Errorf("pass at %v: Doppler crosses zero %v from closest approach", pass.AOS, d)
+				}
+			}
+		}
+		if crossings != 1 {
+			t.Errorf("pass at %v: Doppler crosses zero %d times, want once", pass.AOS, crossings)
+		}
+	}
+}
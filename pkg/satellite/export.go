@@ -0,0 +1,293 @@
+package satellite
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// azElTimeLayout is the timestamp layout used in az/el listings
+const azElTimeLayout = "2006/01/02 15:04:05"
+
+// WritePassAzEl writes a Gpredict-style az/el listing of a pass, sampled every
+// step from AOS to LOS inclusive. The format is plain text:
+//
+//	# <name> (<NORAD ID>)
+//	# AOS <time> LOS <time> MaxEl <deg>
+//	#  Time (UTC)             Az       El   Range
+//	2026/10/16 12:33:13   176.30    10.00  2313.4
+//
+// Header lines start with '#'. Each data line has a UTC timestamp followed by
+// azimuth and elevation in degrees (two decimals) and slant range in km (one
+// decimal), separated by whitespace, which rig-control scripts can split directly.
+func WritePassAzEl(w io.Writer, sat *Satellite, observer *ObserverPosition, pass *Pass, step time.Duration) error {
+	if sat.TLE == nil {
+		return fmt.Errorf("satellite %d has no TLE", sat.NoradID)
+	}
+	if step <= 0 {
+		return fmt.Errorf("step must be positive: %v", step)
+	}
+
+	if _, err := fmt.Fprintf(w, "# %s (%d)\n# AOS %s LOS %s MaxEl %.2f\n#  Time (UTC)             Az       El   Range\n",
+		sat.Name, sat.NoradID,
+		pass.AOS.UTC().Format(azElTimeLayout), pass.LOS.UTC().Format(azElTimeLayout), pass.MaxElevation); err != nil {
+		return err
+	}
+
+	for t := pass.AOS; ; t = t.Add(step) {
+		if t.After(pass.LOS) {
+			t = pass.LOS
+		}
+
+		obs, err := observe(sat.TLE, observer, t)
+		if err != nil {
+			return fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		if _, err := fmt.Fprintf(w, "%s %8.2f %8.2f %8.1f\n",
+			t.UTC().Format(azElTimeLayout), obs.Azimuth, obs.Elevation, obs.Range); err != nil {
+			return err
+		}
+
+		if !t.Before(pass.LOS) {
+			return nil
+		}
+	}
+}
+
+// TrackCSVHeader is the header row for CSV written by WriteTrackCSV.
+var TrackCSVHeader = []string{"norad_id", "time", "x_km", "y_km", "z_km", "vx_km_s", "vy_km_s", "vz_km_s"}
+
+// WriteTrackCSV writes the satellite's ECEF position and velocity every step
+// from start to end inclusive as CSV rows matching TrackCSVHeader. The whole
+// track is propagated before anything is written, so a propagation failure
+// leaves w untouched.
+func WriteTrackCSV(w *csv.Writer, sat *Satellite, start, end time.Time, step time.Duration) error {
+	if sat.TLE == nil {
+		return fmt.Errorf("satellite %d has no TLE", sat.NoradID)
+	}
+	if step <= 0 {
+		return fmt.Errorf("step must be positive: %v", step)
+	}
+
+	positions, err := PropagateRange(sat.TLE, start, end, step)
+	if err != nil {
+		return err
+	}
+
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+
+	id := strconv.Itoa(sat.NoradID)
+	for _, pos := range positions {
+		if err := w.Write([]string{
+			id, pos.Time.UTC().Format(time.RFC3339),
+			f(pos.X, 3), f(pos.Y, 3), f(pos.Z, 3),
+			f(pos.Vx, 6), f(pos.Vy, 6), f(pos.Vz, 6),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SatelliteCSVHeader is the header row for CSV written by ExportCSV. The
+// column order is stable; new columns are only ever appended.
+var SatelliteCSVHeader = []string{
+	"norad_id", "name", "intl_id", "object_type", "owner",
+	"launch_date", "decay_date", "launch_site",
+	"period_min", "inclination_deg", "apogee_km", "perigee_km",
+	"rcs_size", "orbit_regime", "tle_epoch", "tle_line1", "tle_line2",
+}
+
+// ExportCSV writes a header row and one row per satellite with its SATCAT
+// and orbital data in the columns of SatelliteCSVHeader. Fields the catalog
+// lacks are left empty: the orbital columns when there is no SATCAT entry
+// (or no period in it), and the TLE columns when there is no TLE. Values
+// containing commas or quotes are quoted per RFC 4180.
+func ExportCSV(w io.Writer, sats []*Satellite) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(SatelliteCSVHeader); err != nil {
+		return err
+	}
+
+	for _, sat := range sats {
+		num := func(v float64) string {
+			if sat.SATCAT == nil {
+				return ""
+			}
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		period := num(sat.Period)
+		if sat.Period == 0 {
+			period = ""
+		}
+
+		var epoch, line1, line2 string
+		if !sat.TLEEpoch.IsZero() {
+			epoch = sat.TLEEpoch.UTC().Format(time.RFC3339)
+		}
+		if sat.TLE != nil {
+			line1, line2 = sat.TLE.Line1, sat.TLE.Line2
+		}
+
+		if err := cw.Write([]string{
+			strconv.Itoa(sat.NoradID), sat.Name, sat.IntlID, sat.ObjectType, sat.Owner,
+			sat.LaunchDate, sat.DecayDate, sat.LaunchSite,
+			period, num(sat.Inclination), num(sat.Apogee), num(sat.Perigee),
+			sat.RCSSize, sat.OrbitRegime, epoch, line1, line2,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// geoJSONGeometry is a GeoJSON geometry: a MultiLineString or Polygon with
+// coordinates, or a GeometryCollection of other geometries.
+type geoJSONGeometry struct {
+	Type        string            `json:"type"`
+	Coordinates [][][2]float64    `json:"coordinates,omitempty"`
+	Geometries  []geoJSONGeometry `json:"geometries,omitempty"`
+}
+
+// geoJSONFeature is a GeoJSON Feature.
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Properties map[string]any  `json:"properties"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+}
+
+// groundTrackFeature returns the ground track feature of GroundTrackGeoJSON
+// and the positions it was drawn from.
+func groundTrackFeature(tle *TLE, start, end time.Time, step time.Duration) (geoJSONFeature, []*SatellitePosition, error) {
+	positions, err := PropagateRange(tle, start, end, step)
+	if err != nil {
+		return geoJSONFeature{}, nil, err
+	}
+
+	var lines [][][2]float64
+	var line [][2]float64
+	for i, pos := range positions {
+		lat, lon, _ := SubSatellitePoint(pos)
+		if i > 0 {
+			prev := line[len(line)-1]
+			if math.Abs(lon-prev[0]) > 180 {
+				// Unwrap to find where the segment meets the antimeridian
+				edge := 180.0
+				unwrapped := lon + 360
+				if prev[0] < 0 {
+					edge, unwrapped = -180.0, lon-360
+				}
+				crossLat := prev[1] + (lat-prev[1])*(edge-prev[0])/(unwrapped-prev[0])
+
+				line = append(line, [2]float64{edge, crossLat})
+				lines = append(lines, line)
+				line = [][2]float64{{-edge, crossLat}}
+			}
+		}
+		line = append(line, [2]float64{lon, lat})
+	}
+	lines = append(lines, line)
+
+	feature := geoJSONFeature{
+		Type: "Feature",
+		Properties: map[string]any{
+			"name":  tle.Name,
+			"start": start.UTC().Format(time.RFC3339),
+			"end":   end.UTC().Format(time.RFC3339),
+			"step":  step.String(),
+		},
+		Geometry: geoJSONGeometry{Type: "MultiLineString", Coordinates: lines},
+	}
+	return feature, positions, nil
+}
+
+// GroundTrackGeoJSON returns the satellite's ground track every step from
+// start to end inclusive as a GeoJSON Feature (RFC 7946). The geometry is a
+// MultiLineString of [longitude, latitude] positions, split where the track
+// crosses the antimeridian so map renderers do not draw it across the whole
+// map; each crossing ends one line and starts the next at ±180° with the
+// interpolated latitude.
+func GroundTrackGeoJSON(tle *TLE, start, end time.Time, step time.Duration) ([]byte, error) {
+	feature, _, err := groundTrackFeature(tle, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(feature)
+}
+
+// GroundTrackFootprintGeoJSON returns the ground track of GroundTrackGeoJSON
+// together with the satellite's footprint at start, as a Polygon with points
+// vertices (see FootprintCircle). The result is still a single Feature, whose
+// geometry is a GeometryCollection of the track's MultiLineString and the
+// footprint, with the footprint radius in the "footprintRadiusKm" property.
+// Polygon longitudes are kept continuous, so may run past ±180°, and a
+// footprint around a pole is closed along it.
+func GroundTrackFootprintGeoJSON(tle *TLE, start, end time.Time, step time.Duration, points int) ([]byte, error) {
+	feature, positions, err := groundTrackFeature(tle, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	ring := footprintRing(positions[0], points)
+	if ring == nil {
+		return nil, fmt.Errorf("no footprint at %v", start)
+	}
+	feature.Properties["footprintRadiusKm"] = FootprintRadiusKm(positions[0])
+	feature.Geometry = geoJSONGeometry{
+		Type: "GeometryCollection",
+		Geometries: []geoJSONGeometry{
+			feature.Geometry,
+			{Type: "Polygon", Coordinates: [][][2]float64{ring}},
+		},
+	}
+	return json.Marshal(feature)
+}
+
+// footprintRing returns the footprint of FootprintCircle as a GeoJSON linear
+// ring of [longitude, latitude] positions with continuous longitudes. A ring
+// that encloses a pole, whose longitudes wind a full turn, is closed by
+// running along the pole's latitude.
+func footprintRing(pos *SatellitePosition, points int) [][2]float64 {
+	circle := FootprintCircle(pos, points)
+	if circle == nil {
+		return nil
+	}
+
+	ring := make([][2]float64, len(circle))
+	for i, p := range circle {
+		lon := p[1]
+		if i > 0 {
+			prev := ring[i-1][0]
+			lon += 360 * math.Round((prev-lon)/360)
+		}
+		ring[i] = [2]float64{lon, p[0]}
+	}
+
+	// Center the longitudes on [-180, 180]
+	lo, hi := ring[0][0], ring[0][0]
+	for _, p := range ring {
+		lo, hi = math.Min(lo, p[0]), math.Max(hi, p[0])
+	}
+	shift := -360 * math.Round((lo+hi)/2/360)
+	for i := range ring {
+		ring[i][0] += shift
+	}
+
+	first, last := ring[0], ring[len(ring)-1]
+	if math.Abs(last[0]-first[0]) > 180 {
+		pole := 90.0
+		if pos.Z < 0 {
+			pole = -90.0
+		}
+		ring = append(ring, [2]float64{last[0], pole}, [2]float64{first[0], pole}, first)
+	}
+	return ring
+}
@@ -0,0 +1,333 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// azElReference is the expected Gpredict-style listing of the start of an
+// ISS pass over Boulder, sampled every 30s with the LOS row clamped.
+const azElReference = `# ISS (ZARYA) (25544)
+# AOS 2024/03/01 10:59:20 LOS 2024/03/01 11:01:10 MaxEl 15.58
+#  Time (UTC)             Az       El   Range
+2024/03/01 10:59:20   168.19    10.25   1465.1
+2024/03/01 10:59:50   160.57    12.22   1351.6
+2024/03/01 11:00:20   151.60    13.93   1264.0
+2024/03/01 11:00:50   141.42    15.13   1208.3
+2024/03/01 11:01:10   134.19    15.52   1191.2
+`
+
+func TestWritePassAzElReference(t *testing.T) {
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1.6}
+	aos := time.Date(2024, 3, 1, 10, 59, 20, 0, time.UTC)
+	pass := &Pass{AOS: aos, LOS: aos.Add(110 * time.Second), MaxElevation: 15.58}
+
+	var b strings.Builder
+	if err := WritePassAzEl(&b, testSatellite("ISS (ZARYA)", issTLE()), observer, pass, 30*time.Second); err != nil {
+		t.Fatalf("WritePassAzEl: %v", err)
+	}
+	if got := b.String(); got != azElReference {
+		t.Errorf("az/el listing differs from the reference sample:\n%s\nwant:\n%s", got, azElReference)
+	}
+
+	// Every data row splits into date, time, azimuth, elevation, and range
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.HasPrefix(line, "#") && len(strings.Fields(line)) != 5 {
+			t.Errorf("data row %q does not have 5 fields", line)
+		}
+	}
+
+	if err := WritePassAzEl(&b, testSatellite("ISS (ZARYA)", issTLE()), observer, pass, 0); err == nil {
+		t.Error("WritePassAzEl accepted a zero step")
+	}
+}
+
+func TestGroundTrackGeoJSON(t *testing.T) {
+	tle := issTLE()
+	tle.Name = "ISS (ZARYA)"
+	start, end := testEpoch, testEpoch.Add(3*time.Hour)
+
+	data, err := GroundTrackGeoJSON(tle, start, end, time.Minute)
+	if err != nil {
+		t.Fatalf("GroundTrackGeoJSON: %v", err)
+	}
+	var feature struct {
+		Type       string         `json:"type"`
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string         `json:"type"`
+			Coordinates [][][2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+	}
+	if err := json.Unmarshal(data, &feature); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if feature.Type != "Feature" || feature.Geometry.Type != "MultiLineString" {
+		t.Fatalf("got a %s of %s, want a Feature of a MultiLineString", feature.Type, feature.Geometry.Type)
+	}
+	if feature.Properties["name"] != "ISS (ZARYA)" || feature.Properties["start"] != "2024-03-01T00:00:00Z" ||
+		feature.Properties["end"] != "2024-03-01T03:00:00Z" || feature.Properties["step"] != "1m0s" {
+		t.Errorf("properties = %v", feature.Properties)
+	}
+
+	// One line more than the track's antimeridian crossings
+	positions, err := PropagateRange(tle, start, end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossings, prevLon := 0, 0.0
+	for i, pos := range positions {
+		_, lon, _ := SubSatellitePoint(pos)
+		if i > 0 && math.Abs(lon-prevLon) > 180 {
+			crossings++
+		}
+		prevLon = lon
+	}
+	lines := feature.Geometry.Coordinates
+	if crossings == 0 || len(lines) != crossings+1 {
+		t.Fatalf("%d lines for %d antimeridian crossings", len(lines), crossings)
+	}
+
+	points := 0
+	for i, line := range lines {
+		if len(line) < 2 {
+			t.Errorf("line %d has %d positions", i, len(line))
+			continue
+		}
+		for j, p := range line {
+			if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+				t.Errorf("line %d position %d = %v, outside [lon, lat] bounds", i, j, p)
+			}
+			if j > 0 && math.Abs(p[0]-line[j-1][0]) > 180 {
+				t.Errorf("line %d jumps from %v to %v across the map", i, line[j-1], p)
+			}
+		}
+		// Each split ends at one edge of the map and resumes at the other,
+		// at the same latitude
+		if i > 0 {
+			last, first := lines[i-1][len(lines[i-1])-1], line[0]
+			if math.Abs(last[0]) != 180 || first[0] != -last[0] || first[1] != last[1] {
+				t.Errorf("line %d ends at %v and line %d starts at %v, want opposite edges at one latitude", i-1, last, i, first)
+			}
+			points -= 2
+		}
+		points += len(line)
+	}
+	if points != len(positions) {
+		t.Errorf("lines hold %d track positions, want %d", points, len(positions))
+	}
+}
+
+func TestGroundTrackKML(t *testing.T) {
+	tle := issTLE()
+	tle.Name = "ISS <ZARYA> & co"
+	start, end := testEpoch, testEpoch.Add(10*time.Minute)
+
+	data, err := GroundTrackKML(tle, start, end, time.Minute)
+	if err != nil {
+		t.Fatalf("GroundTrackKML: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("document does not start with an XML declaration:\n%s", data)
+	}
+
+	// Well-formed: the decoder reads every token to the end
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("malformed XML: %v\n%s", err, data)
+		}
+	}
+
+	var doc kmlRoot
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+	if doc.XMLName.Space != kmlNamespace || doc.Document.Name != tle.Name {
+		t.Errorf("document %v named %q, want a KML 2.2 document named %q", doc.XMLName, doc.Document.Name, tle.Name)
+	}
+	if len(doc.Document.Placemarks) != 1 || doc.Document.Placemarks[0].LineString == nil {
+		t.Fatalf("got %d placemarks, want one LineString", len(doc.Document.Placemarks))
+	}
+	line := doc.Document.Placemarks[0].LineString
+	if line.AltitudeMode != "absolute" || line.Extrude != 1 {
+		t.Errorf("altitudeMode %q extrude %d, want absolute and extruded", line.AltitudeMode, line.Extrude)
+	}
+
+	positions, err := PropagateRange(tle, start, end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords := strings.Fields(line.Coordinates)
+	if len(coords) != len(positions) {
+		t.Fatalf("%d coordinates, want %d", len(coords), len(positions))
+	}
+	for i, coord := range coords {
+		var lon, lat, alt float64
+		if _, err := fmt.Sscanf(coord, "%f,%f,%f", &lon, &lat, &alt); err != nil {
+			t.Fatalf("coordinate %q is not lon,lat,alt: %v", coord, err)
+		}
+		wantLat, wantLon, wantAltKm := SubSatellitePoint(positions[i])
+		assertNear(t, "longitude", lon, wantLon, 1e-6)
+		assertNear(t, "latitude", lat, wantLat, 1e-6)
+		assertNear(t, "altitude (m)", alt, wantAltKm*1000, 1)
+	}
+}
+
+func TestGroundTrackFootprint(t *testing.T) {
+	tle := issTLE()
+	tle.Name = "ISS (ZARYA)"
+	start, end := testEpoch, testEpoch.Add(30*time.Minute)
+	pos, err := PropagateSatellite(tle, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The footprint is centered on the geocentric subpoint
+	subLat := math.Atan2(pos.Z, math.Hypot(pos.X, pos.Y)) * 180 / math.Pi
+	subLon := math.Atan2(pos.Y, pos.X) * 180 / math.Pi
+	radius := FootprintRadiusKm(pos)
+
+	data, err := GroundTrackFootprintGeoJSON(tle, start, end, time.Minute, 36)
+	if err != nil {
+		t.Fatalf("GroundTrackFootprintGeoJSON: %v", err)
+	}
+	var feature struct {
+		Type       string         `json:"type"`
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type       string `json:"type"`
+			Geometries []struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometries"`
+		} `json:"geometry"`
+	}
+	if err := json.Unmarshal(data, &feature); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	// The footprint keeps the plain track's top-level type
+	if feature.Type != "Feature" || feature.Geometry.Type != "GeometryCollection" || len(feature.Geometry.Geometries) != 2 ||
+		feature.Geometry.Geometries[0].Type != "MultiLineString" || feature.Geometry.Geometries[1].Type != "Polygon" {
+		t.Fatalf("got %s", data)
+	}
+	assertNear(t, "footprintRadiusKm", feature.Properties["footprintRadiusKm"].(float64), radius, 1e-9)
+
+	var polygon [][][2]float64
+	if err := json.Unmarshal(feature.Geometry.Geometries[1].Coordinates, &polygon); err != nil {
+		t.Fatal(err)
+	}
+	if len(polygon) != 1 || len(polygon[0]) != 37 || polygon[0][0] != polygon[0][36] {
+		t.Fatalf("polygon %v, want one closed ring of 36 vertices", polygon)
+	}
+	for _, p := range polygon[0] {
+		got := earthRadiusKm * centralAngle(subLat, subLon, p[1], p[0]) * math.Pi / 180
+		assertNear(t, "GeoJSON footprint edge", got, radius, 0.01)
+	}
+
+	data, err = GroundTrackFootprintKML(tle, start, end, time.Minute, 36)
+	if err != nil {
+		t.Fatalf("GroundTrackFootprintKML: %v", err)
+	}
+	var doc kmlRoot
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+	if len(doc.Document.Placemarks) != 2 || doc.Document.Placemarks[1].Polygon == nil {
+		t.Fatalf("got %d placemarks, want the track and a footprint Polygon", len(doc.Document.Placemarks))
+	}
+	ring := strings.Fields(doc.Document.Placemarks[1].Polygon.Coordinates)
+	if len(ring) != 37 {
+		t.Fatalf("KML footprint has %d points, want 37", len(ring))
+	}
+	for _, coord := range ring {
+		var lon, lat float64
+		if _, err := fmt.Sscanf(coord, "%f,%f", &lon, &lat); err != nil {
+			t.Fatalf("coordinate %q is not lon,lat: %v", coord, err)
+		}
+		got := earthRadiusKm * centralAngle(subLat, subLon, lat, lon) * math.Pi / 180
+		assertNear(t, "KML footprint edge", got, radius, 0.1)
+	}
+
+	if _, err := GroundTrackFootprintGeoJSON(tle, start, end, time.Minute, 2); err == nil {
+		t.Error("GroundTrackFootprintGeoJSON accepted a 2-point footprint")
+	}
+}
+
+func TestExportCSVRoundTrip(t *testing.T) {
+	iss := testSatellite(`ISS "ZARYA", crew`, issTLE())
+	satcat := &SATCAT{NoradID: 25544, IntlID: "1998-067A", ObjectType: "PAYLOAD", Owner: "ISS",
+		LaunchDate: "1998-11-20", LaunchSite: "TYMSC", Period: 92.9, Inclination: 51.64,
+		Apogee: 422, Perigee: 418.5, RCSSize: "LARGE"}
+	iss.SATCAT = satcat
+	iss.IntlID, iss.ObjectType, iss.Owner = satcat.IntlID, satcat.ObjectType, satcat.Owner
+	iss.LaunchDate, iss.LaunchSite, iss.RCSSize = satcat.LaunchDate, satcat.LaunchSite, satcat.RCSSize
+	iss.Period, iss.Inclination, iss.Apogee, iss.Perigee = satcat.Period, satcat.Inclination, satcat.Apogee, satcat.Perigee
+	iss.OrbitRegime = "LEO"
+	satellites := []*Satellite{
+		iss,
+		testSatellite("TLE ONLY", geoTLE()),
+		{NoradID: 5, Name: "SATCAT ONLY", DecayDate: "2002-01-01", SATCAT: &SATCAT{NoradID: 5}},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, satellites); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	// The column order is part of the format
+	header := "norad_id,name,intl_id,object_type,owner,launch_date,decay_date,launch_site," +
+		"period_min,inclination_deg,apogee_km,perigee_km,rcs_size,orbit_regime,tle_epoch,tle_line1,tle_line2"
+	if got := strings.Join(rows[0], ","); got != header {
+		t.Fatalf("header = %s\nwant %s", got, header)
+	}
+	if len(rows) != len(satellites)+1 {
+		t.Fatalf("got %d rows, want a header and %d satellites", len(rows), len(satellites))
+	}
+
+	want := [][]string{
+		{"25544", `ISS "ZARYA", crew`, "1998-067A", "PAYLOAD", "ISS", "1998-11-20", "", "TYMSC",
+			"92.9", "51.64", "422", "418.5", "LARGE", "LEO", "2024-03-01T00:00:00Z", iss.TLE.Line1, iss.TLE.Line2},
+		{"40000", "TLE ONLY", "", "", "", "", "", "",
+			"", "", "", "", "", "", "2024-03-01T00:00:00Z", satellites[1].TLE.Line1, satellites[1].TLE.Line2},
+		{"5", "SATCAT ONLY", "", "", "", "", "2002-01-01", "",
+			"", "0", "0", "0", "", "", "", "", ""},
+	}
+	for i, row := range rows[1:] {
+		for j, col := range rows[0] {
+			if row[j] != want[i][j] {
+				t.Errorf("row %d %s = %q, want %q", i+1, col, row[j], want[i][j])
+			}
+		}
+	}
+
+	// Parsed back, the numeric and TLE columns reproduce the satellite
+	tle := &TLE{Line1: rows[1][15], Line2: rows[1][16]}
+	epoch, err := time.Parse(time.RFC3339, rows[1][14])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := tle.GetEpoch(); !got.Equal(epoch) || tle.GetNoradID() != 25544 {
+		t.Errorf("round-tripped TLE has epoch %v and ID %d", got, tle.GetNoradID())
+	}
+	for j, want := range []float64{iss.Period, iss.Inclination, iss.Apogee, iss.Perigee} {
+		got, err := strconv.ParseFloat(rows[1][8+j], 64)
+		if err != nil || got != want {
+			t.Errorf("%s = %q, want %v", rows[0][8+j], rows[1][8+j], want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// directionGlyphs holds the arrow glyphs for the eight compass sectors,
+// starting at North and proceeding clockwise.
+var directionGlyphs = [8]string{"↑", "↗", "→", "↘", "↓", "↙", "←", "↖"}
+
+// compassPoints holds the names of the eight compass sectors, starting at
+// North and proceeding clockwise.
+var compassPoints = [8]string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// compassSector returns the index (0-7) of the 45° compass sector containing
+// the azimuth, with sector 0 centered on North.
+func compassSector(azimuth float64) int {
+	az := math.Mod(azimuth, 360.0)
+	if az < 0 {
+		az += 360.0
+	}
+	return int(math.Floor((az+22.5)/45.0)) % 8
+}
+
+// DirectionGlyph returns an arrow glyph pointing toward the satellite's azimuth.
+// Azimuth is mapped to the nearest of the eight compass directions.
+// Returns an empty string if angles is nil.
+func DirectionGlyph(angles *ObservationAngles) string {
+	if angles == nil {
+		return ""
+	}
+	return directionGlyphs[compassSector(angles.Azimuth)]
+}
+
+// CompassPoint returns the nearest of the eight compass points to the
+// azimuth, e.g. "N" or "SW".
+func CompassPoint(azimuth float64) string {
+	return compassPoints[compassSector(azimuth)]
+}
+
+// PassDirection describes the direction a pass crosses the sky from its rise
+// and set azimuths, e.g. "SW → NE".
+func PassDirection(pass *Pass) string {
+	return CompassPoint(pass.AOSAzimuth) + " → " + CompassPoint(pass.LOSAzimuth)
+}
+
+// OrbitSense classifies an orbit by inclination in degrees as "prograde"
+// (moving eastward with the Earth's rotation, below 90°) or "retrograde"
+// (above 90°, as for sun-synchronous orbits). Exactly 90° is "polar".
+func OrbitSense(inclination float64) string {
+	switch {
+	case inclination < 90:
+		return "prograde"
+	case inclination > 90:
+		return "retrograde"
+	default:
+		return "polar"
+	}
+}
+
+// FormatRelativeDuration renders a duration compactly for live displays,
+// e.g. "45s", "14m", "2h14m", or "1d3h". Negative durations are rendered by magnitude.
+func FormatRelativeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// FormatPassRelative renders a pass's AOS and LOS relative to ref,
+// e.g. "AOS in 2h14m, LOS in 2h23m". Passes already in progress or over are
+// described as such. Passes whose AOS is more than absoluteAfter from ref are
+// rendered with absolute timestamps instead; absoluteAfter <= 0 disables the switch.
+func FormatPassRelative(pass *Pass, ref time.Time, absoluteAfter time.Duration) string {
+	untilAOS := pass.AOS.Sub(ref)
+	untilLOS := pass.LOS.Sub(ref)
+
+	switch {
+	case untilLOS < 0:
+		return fmt.Sprintf("ended %s ago", FormatRelativeDuration(untilLOS))
+	case untilAOS <= 0:
+		return fmt.Sprintf("in progress, LOS in %s", FormatRelativeDuration(untilLOS))
+	case absoluteAfter > 0 && untilAOS > absoluteAfter:
+		return fmt.Sprintf("AOS %s, LOS %s",
+			pass.AOS.Format("2006-01-02 15:04 MST"), pass.LOS.Format("15:04 MST"))
+	default:
+		return fmt.Sprintf("AOS in %s, LOS in %s",
+			FormatRelativeDuration(untilAOS), FormatRelativeDuration(untilLOS))
+	}
+}
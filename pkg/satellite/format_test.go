@@ -0,0 +1,126 @@
+package satellite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDirectionGlyph(t *testing.T) {
+	tests := []struct {
+		azimuth float64
+		want    string
+	}{
+		{0, "↑"},
+		{22.4, "↑"},
+		{22.5, "↗"},
+		{45, "↗"},
+		{90, "→"},
+		{135, "↘"},
+		{180, "↓"},
+		{225, "↙"},
+		{270, "←"},
+		{315, "↖"},
+		{337.5, "↑"},
+		{359.9, "↑"},
+		{360, "↑"},
+		{-90, "←"},
+		{450, "→"},
+	}
+	for _, tt := range tests {
+		if got := DirectionGlyph(&ObservationAngles{Azimuth: tt.azimuth}); got != tt.want {
+			t.Errorf("DirectionGlyph(az %v) = %q, want %q", tt.azimuth, got, tt.want)
+		}
+	}
+
+	if got := DirectionGlyph(nil); got != "" {
+		t.Errorf("DirectionGlyph(nil) = %q, want empty", got)
+	}
+}
+
+func TestPassDirection(t *testing.T) {
+	tests := []struct {
+		aos, los float64
+		want     string
+	}{
+		{225, 45, "SW → NE"},
+		{315, 135, "NW → SE"},
+		{0, 180, "N → S"},
+		{170, 10, "S → N"},
+		{350, 100, "N → E"},
+		{269, 91, "W → E"},
+		{22.4, 22.5, "N → NE"},
+		{-45, 405, "NW → NE"},
+	}
+	for _, tt := range tests {
+		if got := PassDirection(&Pass{AOSAzimuth: tt.aos, LOSAzimuth: tt.los}); got != tt.want {
+			t.Errorf("PassDirection(%v → %v) = %q, want %q", tt.aos, tt.los, got, tt.want)
+		}
+	}
+}
+
+func TestOrbitSense(t *testing.T) {
+	tests := []struct {
+		inclination float64
+		want        string
+	}{
+		{0, "prograde"},
+		{51.64, "prograde"},
+		{89.9, "prograde"},
+		{90, "polar"},
+		{97.4, "retrograde"},
+		{180, "retrograde"},
+	}
+	for _, tt := range tests {
+		if got := OrbitSense(tt.inclination); got != tt.want {
+			t.Errorf("OrbitSense(%v) = %q, want %q", tt.inclination, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRelativeDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{14*time.Minute + 59*time.Second, "14m"},
+		{2*time.Hour + 14*time.Minute, "2h14m"},
+		{2*time.Hour + 5*time.Minute, "2h05m"},
+		{27 * time.Hour, "1d3h"},
+		{-90 * time.Second, "1m"},
+	}
+	for _, tt := range tests {
+		if got := FormatRelativeDuration(tt.d); got != tt.want {
+			t.Errorf("FormatRelativeDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPassRelative(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	pass := func(aos, los time.Duration) *Pass { return &Pass{AOS: ref.Add(aos), LOS: ref.Add(los)} }
+	const threshold = 6 * time.Hour
+
+	tests := []struct {
+		name string
+		pass *Pass
+		want string
+	}{
+		{"near", pass(2*time.Hour+14*time.Minute, 2*time.Hour+23*time.Minute), "AOS in 2h14m, LOS in 2h23m"},
+		{"at threshold", pass(threshold, threshold+10*time.Minute), "AOS in 6h00m, LOS in 6h10m"},
+		{"past threshold", pass(threshold+time.Minute, threshold+11*time.Minute), "AOS 2024-03-01 18:01 UTC, LOS 18:11 UTC"},
+		{"far", pass(50*time.Hour, 50*time.Hour+8*time.Minute), "AOS 2024-03-03 14:00 UTC, LOS 14:08 UTC"},
+		{"in progress", pass(-2*time.Minute, 5*time.Minute), "in progress, LOS in 5m"},
+		{"ended", pass(-20*time.Minute, -10*time.Minute), "ended 10m ago"},
+	}
+	for _, tt := range tests {
+		if got := FormatPassRelative(tt.pass, ref, threshold); got != tt.want {
+			t.Errorf("%s: FormatPassRelative = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// A zero threshold never switches to absolute times
+	if got := FormatPassRelative(pass(50*time.Hour, 50*time.Hour+8*time.Minute), ref, 0); got != "AOS in 2d2h, LOS in 2d2h" {
+		t.Errorf("FormatPassRelative without threshold = %q", got)
+	}
+}
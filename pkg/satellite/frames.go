@@ -0,0 +1,152 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// arcsecToRad converts arcseconds to radians
+const arcsecToRad = math.Pi / (180.0 * 3600.0)
+
+// earthRotationRate is the Earth's mean angular velocity in rad/s
+const earthRotationRate = 7.292115146706979e-5
+
+// matrix3 is a 3x3 rotation matrix in row-major order
+type matrix3 [3][3]float64
+
+// rot1 returns the frame rotation about the X axis by angle a (radians)
+func rot1(a float64) matrix3 {
+	c, s := math.Cos(a), math.Sin(a)
+	return matrix3{{1, 0, 0}, {0, c, s}, {0, -s, c}}
+}
+
+// rot2 returns the frame rotation about the Y axis by angle a (radians)
+func rot2(a float64) matrix3 {
+	c, s := math.Cos(a), math.Sin(a)
+	return matrix3{{c, 0, -s}, {0, 1, 0}, {s, 0, c}}
+}
+
+// rot3 returns the frame rotation about the Z axis by angle a (radians)
+func rot3(a float64) matrix3 {
+	c, s := math.Cos(a), math.Sin(a)
+	return matrix3{{c, s, 0}, {-s, c, 0}, {0, 0, 1}}
+}
+
+// mul returns the matrix product m*n
+func (m matrix3) mul(n matrix3) matrix3 {
+	var r matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return r
+}
+
+// transpose returns the transpose (inverse, for rotations) of m
+func (m matrix3) transpose() matrix3 {
+	var r matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = m[j][i]
+		}
+	}
+	return r
+}
+
+// apply returns the vector m*(x, y, z)
+func (m matrix3) apply(x, y, z float64) (float64, float64, float64) {
+	return m[0][0]*x + m[0][1]*y + m[0][2]*z,
+		m[1][0]*x + m[1][1]*y + m[1][2]*z,
+		m[2][0]*x + m[2][1]*y + m[2][2]*z
+}
+
+// rotate returns a copy of pos with position and velocity rotated by m
+func (m matrix3) rotate(pos *SatellitePosition) *SatellitePosition {
+	out := &SatellitePosition{Time: pos.Time}
+	out.X, out.Y, out.Z = m.apply(pos.X, pos.Y, pos.Z)
+	out.Vx, out.Vy, out.Vz = m.apply(pos.Vx, pos.Vy, pos.Vz)
+	return out
+}
+
+// gmst returns the Greenwich Mean Sidereal Time in radians at t using the
+// IAU-82 expression, with UT1 approximated by UTC.
+func gmst(t time.Time) float64 {
+	tut1 := (julianDate(t) - 2451545.0) / 36525.0
+	seconds := 67310.54841 + (876600.0*3600.0+8640184.812866)*tut1 +
+		0.093104*tut1*tut1 - 6.2e-6*tut1*tut1*tut1
+
+	theta := math.Mod(seconds*2*math.Pi/86400.0, 2*math.Pi)
+	if theta < 0 {
+		theta += 2 * math.Pi
+	}
+	return theta
+}
+
+// TEMEToECEF rotates a TEME state vector into the Earth-fixed (ECEF) frame by
+// Greenwich Mean Sidereal Time at pos.Time, ignoring polar motion.
+// The velocity is made Earth-relative by removing the ω × r term of the
+// Earth's rotation, so it gives the correct range rate to a ground observer.
+func TEMEToECEF(pos *SatellitePosition) *SatellitePosition {
+	out := rot3(gmst(pos.Time)).rotate(pos)
+	out.Vx += earthRotationRate * out.Y
+	out.Vy -= earthRotationRate * out.X
+	return out
+}
+
+// temeToJ2000Matrix returns the rotation from TEME to J2000 at time t.
+// Uses IAU-1976 precession and the dominant terms of the IAU-1980 nutation series.
+func temeToJ2000Matrix(t time.Time) matrix3 {
+	// Julian centuries since J2000 (UTC is used in place of TT; the
+	// ~69 s difference is far below the precision of this reduction)
+	T := (julianDate(t) - 2451545.0) / 36525.0
+
+	// IAU-1976 precession angles
+	zeta := (2306.2181*T + 0.30188*T*T + 0.017998*T*T*T) * arcsecToRad
+	theta := (2004.3109*T - 0.42665*T*T - 0.041833*T*T*T) * arcsecToRad
+	z := (2306.2181*T + 1.09468*T*T + 0.018203*T*T*T) * arcsecToRad
+
+	// Truncated IAU-1980 nutation
+	deg := math.Pi / 180.0
+	omega := (125.04452 - 1934.136261*T) * deg
+	sunLon := (280.4665 + 36000.7698*T) * deg
+	moonLon := (218.3165 + 481267.8813*T) * deg
+
+	dPsi := (-17.20*math.Sin(omega) - 1.32*math.Sin(2*sunLon) -
+		0.23*math.Sin(2*moonLon) + 0.21*math.Sin(2*omega)) * arcsecToRad
+	dEps := (9.20*math.Cos(omega) + 0.57*math.Cos(2*sunLon) +
+		0.10*math.Cos(2*moonLon) - 0.09*math.Cos(2*omega)) * arcsecToRad
+
+	meanEps := (84381.448 - 46.8150*T - 0.00059*T*T + 0.001813*T*T*T) * arcsecToRad
+	trueEps := meanEps + dEps
+
+	// Equation of the equinoxes relates TEME to the true-of-date frame
+	eqEquinox := dPsi * math.Cos(meanEps)
+
+	precession := rot3(-z).mul(rot2(theta)).mul(rot3(-zeta))       // J2000 -> MOD
+	nutation := rot1(-trueEps).mul(rot3(-dPsi)).mul(rot1(meanEps)) // MOD -> TOD
+	temeToTOD := rot3(-eqEquinox)                                  // TEME -> TOD
+
+	return precession.transpose().mul(nutation.transpose()).mul(temeToTOD)
+}
+
+// TEMEToJ2000 rotates a TEME state vector into the J2000 (EME2000) inertial frame.
+// The reduction applies IAU-1976 precession and a truncated IAU-1980 nutation
+// series, accurate to roughly 1 arcsecond (~30 m at LEO, ~200 m at GEO).
+// Velocity is rotated without the negligible precession/nutation rate terms.
+func TEMEToJ2000(pos *SatellitePosition) *SatellitePosition {
+	return temeToJ2000Matrix(pos.Time).rotate(pos)
+}
+
+// PropagateSatelliteJ2000 propagates a satellite using SGP4 and returns its
+// position and velocity in the J2000 (EME2000) inertial frame.
+// See TEMEToJ2000 for the precision of the frame reduction.
+func PropagateSatelliteJ2000(tle *TLE, t time.Time) (*SatellitePosition, error) {
+	pos, err := propagateTEME(tle, t)
+	if err != nil {
+		return nil, err
+	}
+	return TEMEToJ2000(pos), nil
+}
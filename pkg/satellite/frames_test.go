@@ -0,0 +1,95 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func norm(x, y, z float64) float64 {
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+func TestTEMEToJ2000PreservesMagnitude(t *testing.T) {
+	for _, tle := range []*TLE{issTLE(), geoTLE()} {
+		for _, offset := range []time.Duration{0, 6 * time.Hour, 10 * 24 * time.Hour} {
+			at := testEpoch.Add(offset)
+			teme, err := propagateTEME(tle, at)
+			if err != nil {
+				t.Fatalf("propagateTEME: %v", err)
+			}
+			j2000, err := PropagateSatelliteJ2000(tle, at)
+			if err != nil {
+				t.Fatalf("PropagateSatelliteJ2000: %v", err)
+			}
+
+			assertNear(t, "|r| J2000", norm(j2000.X, j2000.Y, j2000.Z), norm(teme.X, teme.Y, teme.Z), 1e-6)
+			assertNear(t, "|v| J2000", norm(j2000.Vx, j2000.Vy, j2000.Vz), norm(teme.Vx, teme.Vy, teme.Vz), 1e-9)
+
+			// The frames differ only by ~24 years of precession and nutation,
+			// about a third of a degree at most
+			cos := (teme.X*j2000.X + teme.Y*j2000.Y + teme.Z*j2000.Z) /
+				(norm(teme.X, teme.Y, teme.Z) * norm(j2000.X, j2000.Y, j2000.Z))
+			angle := math.Acos(math.Min(1, cos)) * 180 / math.Pi
+			if angle > 0.5 {
+				t.Errorf("TEME-J2000 angle = %.4f°, want at most 0.5°", angle)
+			}
+		}
+	}
+}
+
+func TestTEMEToJ2000MatrixIsRotation(t *testing.T) {
+	m := temeToJ2000Matrix(testEpoch)
+	identity := m.mul(m.transpose())
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			assertNear(t, "M·Mᵀ", identity[i][j], want, 1e-12)
+		}
+	}
+
+	// Precession at 2024 moves the pole by ~0.13° and the equinox by ~0.33°
+	pole := math.Acos(m[2][2]) * 180 / math.Pi
+	if pole < 0.05 || pole > 0.2 {
+		t.Errorf("pole offset = %.4f°, want 0.05-0.2°", pole)
+	}
+}
+
+func TestTEMEToECEFHoldsGEOFixed(t *testing.T) {
+	// GMST at the J2000 epoch, 2000-01-01 12:00 UT, is 280.46061837°
+	assertNear(t, "GMST at J2000", gmst(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC))*180/math.Pi, 280.46061837, 1e-6)
+
+	tle := geoTLE()
+	longitude := func(x, y float64) float64 { return math.Atan2(y, x) * 180 / math.Pi }
+
+	teme0, err := propagateTEME(tle, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecef0 := TEMEToECEF(teme0)
+
+	for _, hours := range []int{1, 3, 6} {
+		at := testEpoch.Add(time.Duration(hours) * time.Hour)
+		teme, err := propagateTEME(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecef, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Inertially the satellite turns with the Earth, about 15.04° an hour;
+		// Earth-fixed it stays put and barely moves
+		drift := NormalizeLongitude(longitude(teme.X, teme.Y) - longitude(teme0.X, teme0.Y))
+		assertNear(t, "TEME longitude drift", drift, NormalizeLongitude(15.041*float64(hours)), 0.2)
+		assertNear(t, "ECEF longitude drift", NormalizeLongitude(longitude(ecef.X, ecef.Y)-longitude(ecef0.X, ecef0.Y)), 0, 0.1)
+		if v := norm(ecef.Vx, ecef.Vy, ecef.Vz); v > 0.01 {
+			t.Errorf("Earth-relative GEO speed %.4f km/s after %dh, want near 0", v, hours)
+		}
+		assertNear(t, "|r| ECEF", norm(ecef.X, ecef.Y, ecef.Z), norm(teme.X, teme.Y, teme.Z), 1e-6)
+	}
+}
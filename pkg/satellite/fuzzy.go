@@ -0,0 +1,70 @@
+package satellite
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// normalizeName uppercases a satellite name and drops everything but letters
+// and digits, so "Star Link", "STARLINK" and "starlink-" compare equal.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FuzzyNameDistance returns the Levenshtein distance between query and the
+// closest matching part of name, after both are normalized (uppercased,
+// punctuation and spaces stripped). Like the substring name search, the query
+// can match anywhere in the name, so "starlnk" is 1 from "STARLINK-1234".
+func FuzzyNameDistance(query, name string) int {
+	q := []rune(normalizeName(query))
+	n := []rune(normalizeName(name))
+
+	// Edit distance of the query against substrings of the name: the match
+	// may start at any position of the name for free and end at any position
+	prev := make([]int, len(n)+1)
+	curr := make([]int, len(n)+1)
+	for i := 1; i <= len(q); i++ {
+		curr[0] = i
+		for j := 1; j <= len(n); j++ {
+			cost := 1
+			if q[i-1] == n[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j-1]+cost, prev[j]+1, curr[j-1]+1)
+		}
+		prev, curr = curr, prev
+	}
+
+	best := prev[0]
+	for _, d := range prev[1:] {
+		best = min(best, d)
+	}
+	return best
+}
+
+// FuzzyMaxDistance returns the default FuzzyNameDistance accepted for query:
+// one edit for every four letters and digits. Short queries must match
+// exactly, as a fixed allowance of edits would let a three-letter query like
+// "ISS" match nearly every name.
+func FuzzyMaxDistance(query string) int {
+	return len([]rune(normalizeName(query))) / 4
+}
+
+// SortByFuzzyName orders satellites by FuzzyNameDistance to query, closest
+// first. Ties keep their existing order.
+func SortByFuzzyName(satellites []*Satellite, query string) {
+	distance := make(map[*Satellite]int, len(satellites))
+	for _, sat := range satellites {
+		distance[sat] = FuzzyNameDistance(query, sat.Name)
+	}
+	sort.SliceStable(satellites, func(i, j int) bool {
+		return distance[satellites[i]] < distance[satellites[j]]
+	})
+}
@@ -0,0 +1,218 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// WGS84 ellipsoid constants
+const (
+	wgs84A  = earthRadiusKm            // semi-major axis in km
+	wgs84F  = 1.0 / 298.257223563      // flattening
+	wgs84E2 = 2*wgs84F - wgs84F*wgs84F // first eccentricity squared
+
+	meanEarthRadiusKm = 6371.0 // mean Earth radius for great-circle distances and orbit altitudes
+)
+
+// NormalizeLongitude maps a longitude in degrees to the range [-180, 180),
+// so 0-360 inputs such as 300 become -60. All longitudes returned by this
+// package use the [-180, 180) convention.
+func NormalizeLongitude(lon float64) float64 {
+	lon = math.Mod(lon+180.0, 360.0)
+	if lon < 0 {
+		lon += 360.0
+	}
+	return lon - 180.0
+}
+
+// observerECEF converts the observer's WGS84 geodetic position to ECEF coordinates in km.
+func observerECEF(observer *ObserverPosition) (x, y, z float64) {
+	latRad := observer.Latitude * math.Pi / 180.0
+	lonRad := observer.Longitude * math.Pi / 180.0
+	altKm := observer.Altitude / 1000.0 // convert meters to km
+
+	sinLat, cosLat := math.Sin(latRad), math.Cos(latRad)
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+
+	x = (n + altKm) * cosLat * math.Cos(lonRad)
+	y = (n + altKm) * cosLat * math.Sin(lonRad)
+	z = (n*(1-wgs84E2) + altKm) * sinLat
+	return x, y, z
+}
+
+// ecefToGeodetic converts ECEF coordinates in km to WGS84 geodetic latitude and
+// longitude in degrees and altitude in km, using Bowring's method with one
+// iteration (sub-millimeter for near-Earth orbits).
+func ecefToGeodetic(x, y, z float64) (lat, lon, altKm float64) {
+	b := wgs84A * (1 - wgs84F)
+	ep2 := (wgs84A*wgs84A - b*b) / (b * b)
+
+	p := math.Sqrt(x*x + y*y)
+	lon = math.Atan2(y, x)
+
+	theta := math.Atan2(z*wgs84A, p*b)
+	sinT, cosT := math.Sin(theta), math.Cos(theta)
+	latRad := math.Atan2(z+ep2*b*sinT*sinT*sinT, p-wgs84E2*wgs84A*cosT*cosT*cosT)
+
+	sinLat := math.Sin(latRad)
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+	if math.Abs(math.Cos(latRad)) > 1e-10 {
+		altKm = p/math.Cos(latRad) - n
+	} else {
+		altKm = math.Abs(z) - b
+	}
+
+	return latRad * 180.0 / math.Pi, NormalizeLongitude(lon * 180.0 / math.Pi), altKm
+}
+
+// greatCircleDistanceKm returns the surface distance in km between two points
+// given in degrees, using the haversine formula on a spherical Earth.
+func greatCircleDistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const deg = math.Pi / 180.0
+	dLat := (lat2 - lat1) * deg
+	dLon := (lon2 - lon1) * deg
+
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*deg)*math.Cos(lat2*deg)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * meanEarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// groundDistanceAt returns the surface distance in km between the observer and
+// the satellite's sub-satellite point at t.
+func groundDistanceAt(tle *TLE, observer *ObserverPosition, t time.Time) (float64, error) {
+	pos, err := PropagateSatellite(tle, t)
+	if err != nil {
+		return 0, err
+	}
+	lat, lon, _ := ecefToGeodetic(pos.X, pos.Y, pos.Z)
+	return greatCircleDistanceKm(observer.Latitude, NormalizeLongitude(observer.Longitude), lat, lon), nil
+}
+
+// MinCrossTrackDistance returns the minimum surface distance in km between the
+// observer and the satellite's ground track over [start, end], and the time it occurs.
+// The track is sampled at step and the closest sample refined to one second.
+func MinCrossTrackDistance(tle *TLE, observer *ObserverPosition, start, end time.Time, step time.Duration) (float64, time.Time, error) {
+	if tle == nil {
+		return 0, time.Time{}, fmt.Errorf("TLE is nil")
+	}
+	if end.Before(start) {
+		return 0, time.Time{}, fmt.Errorf("end time must be after start time")
+	}
+	if step <= 0 {
+		return 0, time.Time{}, fmt.Errorf("step must be positive")
+	}
+
+	minDist := math.Inf(1)
+	var minTime time.Time
+	for t := start; !t.After(end); t = t.Add(step) {
+		d, err := groundDistanceAt(tle, observer, t)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		if d < minDist {
+			minDist, minTime = d, t
+		}
+	}
+
+	// Refine between the neighboring samples
+	lo := clampTime(minTime.Add(-step), start, end)
+	hi := clampTime(minTime.Add(step), start, end)
+	t, d, err := goldenSectionMin(lo, hi, func(t time.Time) (float64, error) {
+		return groundDistanceAt(tle, observer, t)
+	})
+	if err == nil && d < minDist {
+		minDist, minTime = d, t
+	}
+
+	return minDist, minTime, nil
+}
+
+// OffNadirAngle returns the angle in degrees at the satellite between the nadir
+// direction (along the WGS84 ellipsoid normal through the satellite) and the
+// direction to a ground target given by geodetic latitude and longitude in
+// degrees at sea level. A target at the sub-satellite point is at 0°.
+func OffNadirAngle(satPos *SatellitePosition, targetLat, targetLon float64) float64 {
+	tx, ty, tz := observerECEF(&ObserverPosition{Latitude: targetLat, Longitude: targetLon})
+
+	lat, lon, _ := ecefToGeodetic(satPos.X, satPos.Y, satPos.Z)
+	latRad, lonRad := lat*math.Pi/180.0, lon*math.Pi/180.0
+	nx := -math.Cos(latRad) * math.Cos(lonRad)
+	ny := -math.Cos(latRad) * math.Sin(lonRad)
+	nz := -math.Sin(latRad)
+
+	dx, dy, dz := tx-satPos.X, ty-satPos.Y, tz-satPos.Z
+	targetLen := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if targetLen == 0 {
+		return 0
+	}
+
+	cosAngle := (nx*dx + ny*dy + nz*dz) / targetLen
+	return math.Acos(math.Max(-1, math.Min(1, cosAngle))) * 180.0 / math.Pi
+}
+
+// offNadirReachKm returns the ground distance in km from the sub-satellite point
+// to the farthest point viewable within maxOffNadir degrees from altitude altKm,
+// on a spherical Earth. Beyond the horizon the reach is limited by the Earth's limb.
+func offNadirReachKm(altKm, maxOffNadir float64) float64 {
+	ratio := (meanEarthRadiusKm + altKm) / meanEarthRadiusKm
+	theta := maxOffNadir * math.Pi / 180.0
+
+	sinIncidence := ratio * math.Sin(theta)
+	if sinIncidence >= 1 {
+		return meanEarthRadiusKm * math.Acos(1/ratio) // limb
+	}
+	return meanEarthRadiusKm * (math.Asin(sinIncidence) - theta)
+}
+
+// CanImageTarget reports whether the satellite's off-nadir angle to a ground
+// target falls within maxOffNadir degrees, with the target above the satellite's
+// horizon, at any sample during the calendar day containing date (in date's
+// location), and returns the first such time. The day is sampled at step;
+// samples whose ground distance to the target exceeds the off-nadir reach are
+// rejected before the exact angle is computed.
+func CanImageTarget(tle *TLE, targetLat, targetLon, maxOffNadir float64, date time.Time, step time.Duration) (bool, time.Time, error) {
+	if tle == nil {
+		return false, time.Time{}, fmt.Errorf("TLE is nil")
+	}
+	if step <= 0 {
+		return false, time.Time{}, fmt.Errorf("step must be positive: %v", step)
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	target := &ObserverPosition{Latitude: targetLat, Longitude: NormalizeLongitude(targetLon)}
+
+	for t := dayStart; t.Before(dayEnd); t = t.Add(step) {
+		pos, err := PropagateSatellite(tle, t)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		lat, lon, altKm := ecefToGeodetic(pos.X, pos.Y, pos.Z)
+		// Small margin absorbs the spherical-Earth approximation
+		if greatCircleDistanceKm(target.Latitude, target.Longitude, lat, lon) > offNadirReachKm(altKm, maxOffNadir)*1.01+1 {
+			continue
+		}
+
+		if CalculateObservationAngles(pos, target).Elevation < 0 {
+			continue
+		}
+		if OffNadirAngle(pos, target.Latitude, target.Longitude) <= maxOffNadir {
+			return true, t, nil
+		}
+	}
+
+	return false, time.Time{}, nil
+}
+
+// EarthAngularRadius returns the angular radius in degrees of the Earth as seen
+// from the satellite, asin(R / r) for geocentric distance r, using the WGS84
+// equatorial radius. Returns 90° if the position is at or below the surface.
+func EarthAngularRadius(satPos *SatellitePosition) float64 {
+	r := math.Sqrt(satPos.X*satPos.X + satPos.Y*satPos.Y + satPos.Z*satPos.Z)
+	if r <= earthRadiusKm {
+		return 90.0
+	}
+	return math.Asin(earthRadiusKm/r) * 180.0 / math.Pi
+}
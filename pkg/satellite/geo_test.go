@@ -0,0 +1,195 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMinCrossTrackDistance(t *testing.T) {
+	tle := issTLE()
+	overhead := testEpoch.Add(30 * time.Minute)
+	pos, err := PropagateSatellite(tle, overhead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := SubSatellitePoint(pos)
+
+	// An observer on the track is passed over at the sampled instant
+	near := &ObserverPosition{Latitude: lat, Longitude: lon}
+	dist, at, err := MinCrossTrackDistance(tle, near, testEpoch, testEpoch.Add(time.Hour), 30*time.Second)
+	if err != nil {
+		t.Fatalf("MinCrossTrackDistance: %v", err)
+	}
+	if dist > 1 {
+		t.Errorf("observer on the track is %.2f km from it, want < 1 km", dist)
+	}
+	if d := at.Sub(overhead).Abs(); d > 2*time.Second {
+		t.Errorf("closest approach at %v, want %v", at, overhead)
+	}
+
+	// The track never goes beyond the inclination, 51.6°, so an observer at
+	// -80° stays over 28° of latitude (~3100 km) away all day
+	far := &ObserverPosition{Latitude: -80, Longitude: lon}
+	dist, at, err = MinCrossTrackDistance(tle, far, testEpoch, testEpoch.Add(24*time.Hour), time.Minute)
+	if err != nil {
+		t.Fatalf("MinCrossTrackDistance: %v", err)
+	}
+	if dist < 3000 || dist > 3300 {
+		t.Errorf("far observer is %.0f km from the track, want ~3100 km", dist)
+	}
+	if at.Before(testEpoch) || at.After(testEpoch.Add(24*time.Hour)) {
+		t.Errorf("closest approach at %v is outside the window", at)
+	}
+
+	if _, _, err := MinCrossTrackDistance(tle, near, testEpoch, testEpoch.Add(time.Hour), 0); err == nil {
+		t.Error("MinCrossTrackDistance accepted a zero step")
+	}
+}
+
+func TestNormalizeLongitude(t *testing.T) {
+	tests := []struct{ in, want float64 }{
+		{0, 0},
+		{-60, -60},
+		{300, -60},
+		{179.5, 179.5},
+		{180, -180},
+		{-180, -180},
+		{360, 0},
+		{540, -180},
+		{-190, 170},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLongitude(tt.in); got != tt.want {
+			t.Errorf("NormalizeLongitude(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestObserverLongitudeConventions(t *testing.T) {
+	for _, lon := range []float64{300, 360, -180} {
+		o := ObserverPosition{Latitude: 10, Longitude: lon}
+		if err := o.Validate(); err != nil {
+			t.Errorf("Validate(lon %v): %v", lon, err)
+		}
+		if o.Longitude != NormalizeLongitude(lon) {
+			t.Errorf("Validate(lon %v) left longitude %v, want %v", lon, o.Longitude, NormalizeLongitude(lon))
+		}
+	}
+	for _, lon := range []float64{-181, 361} {
+		o := ObserverPosition{Longitude: lon}
+		if err := o.Validate(); err == nil {
+			t.Errorf("Validate accepted longitude %v", lon)
+		}
+	}
+
+	// The same place given in either convention gives identical results
+	tle := issTLE()
+	east := &ObserverPosition{Latitude: 40, Longitude: 255}
+	west := &ObserverPosition{Latitude: 40, Longitude: -105}
+
+	pos, err := PropagateSatellite(tle, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := CalculateObservationAngles(pos, east), CalculateObservationAngles(pos, west)
+	assertNear(t, "azimuth", a.Azimuth, b.Azimuth, 1e-9)
+	assertNear(t, "elevation", a.Elevation, b.Elevation, 1e-9)
+
+	end := testEpoch.Add(6 * time.Hour)
+	distEast, atEast, err := MinCrossTrackDistance(tle, east, testEpoch, end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distWest, atWest, err := MinCrossTrackDistance(tle, west, testEpoch, end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "cross-track distance", distEast, distWest, 1e-6)
+	if !atEast.Equal(atWest) {
+		t.Errorf("closest approach at %v east-positive and %v west-negative", atEast, atWest)
+	}
+
+	lat, lon, _ := SubSatellitePoint(pos)
+	assertNear(t, "off-nadir angle", OffNadirAngle(pos, lat+5, lon+360), OffNadirAngle(pos, lat+5, lon), 1e-9)
+}
+
+func TestOffNadirAngle(t *testing.T) {
+	pos, err := PropagateSatellite(issTLE(), testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := SubSatellitePoint(pos)
+	r := norm(pos.X, pos.Y, pos.Z)
+
+	assertNear(t, "off-nadir to the subpoint", OffNadirAngle(pos, lat, lon), 0, 1e-6)
+
+	// Targets due north along the meridian, out to near the horizon at ~20°;
+	// on a sphere, tan(off-nadir) = sin λ / (r/R - cos λ) for central angle λ
+	previous := 0.0
+	for _, central := range []float64{2, 5, 10, 15, 19} {
+		got := OffNadirAngle(pos, lat+central, lon)
+		rad := central * math.Pi / 180
+		want := math.Atan2(math.Sin(rad), r/meanEarthRadiusKm-math.Cos(rad)) * 180 / math.Pi
+		assertNear(t, "off-nadir angle", got, want, 0.5)
+		if got <= previous {
+			t.Errorf("off-nadir %.2f° at %v° is not beyond %.2f°", got, central, previous)
+		}
+		previous = got
+	}
+	if previous < 60 || previous > EarthAngularRadius(pos) {
+		t.Errorf("off-nadir near the horizon = %.1f°, want large but within the Earth's disc (%.1f°)", previous, EarthAngularRadius(pos))
+	}
+}
+
+func TestCanImageTarget(t *testing.T) {
+	tle := issTLE()
+	pos, err := PropagateSatellite(tle, testEpoch.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := SubSatellitePoint(pos)
+
+	// A target on the ground track is reachable, at the latest when overflown
+	ok, at, err := CanImageTarget(tle, lat, lon, 30, testEpoch, time.Minute)
+	if err != nil {
+		t.Fatalf("CanImageTarget: %v", err)
+	}
+	if !ok || at.After(testEpoch.Add(2*time.Hour)) {
+		t.Fatalf("target under the track: reachable %v at %v, want by %v", ok, at, testEpoch.Add(2*time.Hour))
+	}
+	atPos, _ := PropagateSatellite(tle, at)
+	if angle := OffNadirAngle(atPos, lat, lon); angle > 30 {
+		t.Errorf("off-nadir at the reported time is %.1f°, beyond the 30° limit", angle)
+	}
+
+	// A 30° sensor on the ISS reaches ~230 km off track, nowhere near 75°N
+	ok, at, err = CanImageTarget(tle, 75, lon, 30, testEpoch, time.Minute)
+	if err != nil {
+		t.Fatalf("CanImageTarget: %v", err)
+	}
+	if ok || !at.IsZero() {
+		t.Errorf("target at 75°N reachable at %v", at)
+	}
+}
+
+func TestEarthAngularRadius(t *testing.T) {
+	assertNear(t, "GEO angular radius", EarthAngularRadius(&SatellitePosition{X: 42164}), 8.70, 0.01)
+	assertNear(t, "angular radius at 2R", EarthAngularRadius(&SatellitePosition{Y: 2 * earthRadiusKm}), 30, 1e-9)
+	assertNear(t, "angular radius below the surface", EarthAngularRadius(&SatellitePosition{Z: 6000}), 90, 0)
+
+	leo, err := PropagateSatellite(issTLE(), testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo, err := PropagateSatellite(geoTLE(), testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := EarthAngularRadius(leo); a < 65 || a > 75 {
+		t.Errorf("Earth from the ISS spans %.1f°, want ~70°", a)
+	}
+	if a := EarthAngularRadius(geo); a < 8.5 || a > 8.9 {
+		t.Errorf("Earth from GEO spans %.1f°, want ~8.7°", a)
+	}
+}
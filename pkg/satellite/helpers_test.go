@@ -0,0 +1,68 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// testEpoch is the epoch of the synthetic TLEs used throughout the tests.
+var testEpoch = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+// tleChecksum returns the modulo-10 checksum of a TLE line: the sum of its
+// digits, counting each minus sign as 1.
+func tleChecksum(line string) int {
+	sum := 0
+	for _, r := range line {
+		switch {
+		case r >= '0' && r <= '9':
+			sum += int(r - '0')
+		case r == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// makeTLE formats a drag-free TLE with the given epoch and mean elements:
+// inclination, RAAN, argument of perigee, and mean anomaly in degrees, and
+// mean motion in revolutions per day.
+func makeTLE(noradID int, epoch time.Time, incl, raan, ecc, argPerigee, meanAnomaly, meanMotion float64) *TLE {
+	epoch = epoch.UTC()
+	start := time.Date(epoch.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	day := 1 + epoch.Sub(start).Hours()/24
+
+	line1 := fmt.Sprintf("1 %05dU 24001A   %02d%012.8f  .00000000  00000-0  00000-0 0  999",
+		noradID, epoch.Year()%100, day)
+	line2 := fmt.Sprintf("2 %05d %8.4f %8.4f %07d %8.4f %8.4f %11.8f%5d",
+		noradID, incl, raan, int(math.Round(ecc*1e7)), argPerigee, meanAnomaly, meanMotion, 1)
+	return &TLE{
+		Line1: line1 + fmt.Sprint(tleChecksum(line1)),
+		Line2: line2 + fmt.Sprint(tleChecksum(line2)),
+	}
+}
+
+// issTLE returns a TLE for a near-circular ~420 km, 51.6° orbit like the ISS.
+func issTLE() *TLE {
+	return makeTLE(25544, testEpoch, 51.64, 200, 0.0005, 90, 270, 15.5)
+}
+
+// geoTLE returns a TLE for a geostationary satellite.
+func geoTLE() *TLE {
+	return makeTLE(40000, testEpoch, 0.02, 0, 0.0001, 0, 0, 1.00273791)
+}
+
+// testSatellite wraps a TLE in a Satellite with its NORAD ID and epoch set.
+func testSatellite(name string, tle *TLE) *Satellite {
+	epoch, _ := tle.GetEpoch()
+	return &Satellite{NoradID: tle.GetNoradID(), Name: name, TLE: tle, TLEEpoch: epoch}
+}
+
+// assertNear fails the test if got differs from want by more than tolerance.
+func assertNear(t *testing.T, what string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %v, want %v ± %v", what, got, want, tolerance)
+	}
+}
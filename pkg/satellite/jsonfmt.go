@@ -0,0 +1,307 @@
+package satellite
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// FullPrecision passed as a number of decimals disables rounding.
+const FullPrecision = -1
+
+// RoundTo rounds v to decimals decimal places. v is returned unchanged if
+// decimals is FullPrecision. Values that round to zero are returned as 0,
+// never -0.
+func RoundTo(v float64, decimals int) float64 {
+	if decimals < 0 {
+		return v
+	}
+	scale := math.Pow(10, float64(decimals))
+	r := math.Round(v*scale) / scale
+	if r == 0 {
+		return 0
+	}
+	return r
+}
+
+// Rounded returns a copy of the position with its coordinates and velocity
+// rounded to decimals places, for output.
+func (p *SatellitePosition) Rounded(decimals int) *SatellitePosition {
+	if p == nil {
+		return nil
+	}
+	r := *p
+	r.X, r.Y, r.Z = RoundTo(p.X, decimals), RoundTo(p.Y, decimals), RoundTo(p.Z, decimals)
+	r.Vx, r.Vy, r.Vz = RoundTo(p.Vx, decimals), RoundTo(p.Vy, decimals), RoundTo(p.Vz, decimals)
+	return &r
+}
+
+// Rounded returns a copy of the angles rounded to decimals places, for output.
+func (a *ObservationAngles) Rounded(decimals int) *ObservationAngles {
+	if a == nil {
+		return nil
+	}
+	r := *a
+	r.Azimuth, r.Elevation = RoundTo(a.Azimuth, decimals), RoundTo(a.Elevation, decimals)
+	r.Range, r.RangeRate = RoundTo(a.Range, decimals), RoundTo(a.RangeRate, decimals)
+	return &r
+}
+
+// Rounded returns a copy of the pass with its angles, distances, and
+// subpoint rounded to decimals places, for output.
+func (p Pass) Rounded(decimals int) Pass {
+	p.MaxElevation = RoundTo(p.MaxElevation, decimals)
+	p.AOSAzimuth = RoundTo(p.AOSAzimuth, decimals)
+	p.LOSAzimuth = RoundTo(p.LOSAzimuth, decimals)
+	p.MinRange = RoundTo(p.MinRange, decimals)
+	p.SubpointLatitude = RoundTo(p.SubpointLatitude, decimals)
+	p.SubpointLongitude = RoundTo(p.SubpointLongitude, decimals)
+	p.SubpointAltitude = RoundTo(p.SubpointAltitude, decimals)
+	return p
+}
+
+// MarshalJSON encodes the position with lowercase keys.
+func (p *SatellitePosition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Time time.Time `json:"time"`
+		X    float64   `json:"x"`
+		Y    float64   `json:"y"`
+		Z    float64   `json:"z"`
+		Vx   float64   `json:"vx"`
+		Vy   float64   `json:"vy"`
+		Vz   float64   `json:"vz"`
+	}{p.Time, p.X, p.Y, p.Z, p.Vx, p.Vy, p.Vz})
+}
+
+// JSONNaming is the casing of object keys in JSON output.
+type JSONNaming string
+
+const (
+	// CamelCase keys, e.g. "noradId". Types in this package marshal this way.
+	CamelCase JSONNaming = "camel"
+	// SnakeCase keys, e.g. "norad_id", from the JSONValue methods.
+	SnakeCase JSONNaming = "snake"
+)
+
+// ParseJSONNaming parses "camel" or "snake" (case-insensitive).
+func ParseJSONNaming(s string) (JSONNaming, error) {
+	switch naming := JSONNaming(strings.ToLower(strings.TrimSpace(s))); naming {
+	case CamelCase, SnakeCase:
+		return naming, nil
+	default:
+		return "", fmt.Errorf("unknown JSON naming %q (expected camel or snake)", s)
+	}
+}
+
+// The snake_case marshalling structs below have the same fields as the types
+// they encode, so a value converts to them directly and a field added to one
+// type but not the other fails to compile. A field holding another type with
+// its own snake_case form is left out ("-") and added alongside by JSONValue.
+// Map keys are data, such as orbit regimes, and are never renamed.
+
+// satelliteSnake is Satellite with snake_case keys.
+type satelliteSnake struct {
+	NoradID     int       `json:"norad_id"`
+	Name        string    `json:"name"`
+	IntlID      string    `json:"intl_id"`
+	ObjectType  string    `json:"object_type"`
+	Owner       string    `json:"owner"`
+	LaunchDate  string    `json:"launch_date"`
+	DecayDate   string    `json:"decay_date"`
+	LaunchSite  string    `json:"launch_site"`
+	Period      float64   `json:"period"`
+	Inclination float64   `json:"inclination"`
+	Apogee      float64   `json:"apogee"`
+	Perigee     float64   `json:"perigee"`
+	RCSSize     string    `json:"rcs_size"`
+	OrbitRegime string    `json:"orbit_regime"`
+	TLEEpoch    time.Time `json:"tle_epoch"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	TLE         *TLE      `json:"tle"`
+	SATCAT      *SATCAT   `json:"-"`
+
+	TLEHistory []TLE `json:"tle_history,omitempty"`
+
+	TLEUpdatedAt    time.Time `json:"tle_updated_at"`
+	MissingFromFeed bool      `json:"missing_from_feed,omitempty"`
+}
+
+// satcatSnake is SATCAT with snake_case keys.
+type satcatSnake struct {
+	ID          string  `json:"id"`
+	IntlID      string  `json:"intl_id"`
+	Name        string  `json:"name"`
+	NoradID     int     `json:"norad_id"`
+	LaunchDate  string  `json:"launch_date"`
+	DecayDate   string  `json:"decay_date"`
+	ObjectType  string  `json:"object_type"`
+	Owner       string  `json:"owner"`
+	LaunchSite  string  `json:"launch_site"`
+	Period      float64 `json:"period"`
+	Inclination float64 `json:"inclination"`
+	Apogee      float64 `json:"apogee"`
+	Perigee     float64 `json:"perigee"`
+	RCSSize     string  `json:"rcs_size"`
+}
+
+// setStatsSnake is SetStats with snake_case keys.
+type setStatsSnake struct {
+	Count   int                 `json:"count"`
+	Regimes map[OrbitRegime]int `json:"regimes"`
+	Types   map[string]int      `json:"types"`
+
+	OrbitCount      int     `json:"orbit_count"`
+	MeanInclination float64 `json:"mean_inclination"`
+	MinInclination  float64 `json:"min_inclination"`
+	MaxInclination  float64 `json:"max_inclination"`
+	MinPerigee      float64 `json:"min_perigee"`
+	MaxApogee       float64 `json:"max_apogee"`
+	MeanPeriod      float64 `json:"mean_period"`
+}
+
+// anglesJSON is the encoding of ObservationAngles, and anglesSnake the same
+// with snake_case keys.
+type anglesJSON struct {
+	Time      time.Time `json:"time"`
+	Azimuth   float64   `json:"azimuth"`
+	Elevation float64   `json:"elevation"`
+	Range     float64   `json:"range"`
+	RangeRate float64   `json:"rangeRate"`
+}
+
+type anglesSnake struct {
+	Time      time.Time `json:"time"`
+	Azimuth   float64   `json:"azimuth"`
+	Elevation float64   `json:"elevation"`
+	Range     float64   `json:"range"`
+	RangeRate float64   `json:"range_rate"`
+}
+
+// passJSON is the encoding of a Pass, with the duration in seconds, and
+// passSnake the same with snake_case keys.
+type passJSON struct {
+	AOS              time.Time `json:"aos"`
+	LOS              time.Time `json:"los"`
+	MaxElevation     float64   `json:"maxElevation"`
+	MaxElevationTime time.Time `json:"maxElevationTime"`
+	AOSAzimuth       float64   `json:"aosAzimuth"`
+	LOSAzimuth       float64   `json:"losAzimuth"`
+	Duration         float64   `json:"durationSeconds"`
+
+	MinRange          float64   `json:"minRange"`
+	MinRangeTime      time.Time `json:"minRangeTime"`
+	SubpointLatitude  float64   `json:"subpointLatitude"`
+	SubpointLongitude float64   `json:"subpointLongitude"`
+	SubpointAltitude  float64   `json:"subpointAltitude"`
+}
+
+type passSnake struct {
+	AOS              time.Time `json:"aos"`
+	LOS              time.Time `json:"los"`
+	MaxElevation     float64   `json:"max_elevation"`
+	MaxElevationTime time.Time `json:"max_elevation_time"`
+	AOSAzimuth       float64   `json:"aos_azimuth"`
+	LOSAzimuth       float64   `json:"los_azimuth"`
+	Duration         float64   `json:"duration_seconds"`
+
+	MinRange          float64   `json:"min_range"`
+	MinRangeTime      time.Time `json:"min_range_time"`
+	SubpointLatitude  float64   `json:"subpoint_latitude"`
+	SubpointLongitude float64   `json:"subpoint_longitude"`
+	SubpointAltitude  float64   `json:"subpoint_altitude"`
+}
+
+// JSONValue returns the satellite as a value to encode as JSON with naming.
+// Returns nil for a nil satellite.
+func (s *Satellite) JSONValue(naming JSONNaming) any {
+	if s == nil {
+		return nil
+	}
+	if naming != SnakeCase {
+		return s
+	}
+	return struct {
+		*satelliteSnake
+		SATCAT any `json:"satcat"`
+	}{(*satelliteSnake)(s), s.SATCAT.JSONValue(naming)}
+}
+
+// JSONValue returns the SATCAT entry as a value to encode as JSON with
+// naming. Returns nil for a nil entry.
+func (s *SATCAT) JSONValue(naming JSONNaming) any {
+	if s == nil {
+		return nil
+	}
+	if naming != SnakeCase {
+		return s
+	}
+	return (*satcatSnake)(s)
+}
+
+// JSONValue returns the statistics as a value to encode as JSON with naming.
+func (s *SetStats) JSONValue(naming JSONNaming) any {
+	if s == nil {
+		return nil
+	}
+	if naming != SnakeCase {
+		return s
+	}
+	return (*setStatsSnake)(s)
+}
+
+// JSONValue returns the visible satellite as a value to encode as JSON with
+// naming.
+func (v *VisibleSatellite) JSONValue(naming JSONNaming) any {
+	if v == nil {
+		return nil
+	}
+	return struct {
+		Satellite any `json:"satellite"`
+		Angles    any `json:"angles"`
+	}{v.Satellite.JSONValue(naming), v.Angles.JSONValue(naming)}
+}
+
+// JSONValue returns the angles as a value to encode as JSON with naming.
+// Returns nil for nil angles.
+func (a *ObservationAngles) JSONValue(naming JSONNaming) any {
+	if a == nil {
+		return nil
+	}
+	v := anglesJSON{a.Time, a.Azimuth, a.Elevation, a.Range, a.RangeRate}
+	if naming == SnakeCase {
+		return anglesSnake(v)
+	}
+	return v
+}
+
+// MarshalJSON encodes the angles with camelCase keys.
+func (a *ObservationAngles) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.JSONValue(CamelCase))
+}
+
+// JSONValue returns the pass as a value to encode as JSON with naming, with
+// the duration in seconds. Returns nil for a nil pass.
+func (p *Pass) JSONValue(naming JSONNaming) any {
+	if p == nil {
+		return nil
+	}
+	v := passJSON{
+		p.AOS, p.LOS,
+		p.MaxElevation, p.MaxElevationTime,
+		p.AOSAzimuth, p.LOSAzimuth,
+		p.Duration.Seconds(),
+		p.MinRange, p.MinRangeTime,
+		p.SubpointLatitude, p.SubpointLongitude, p.SubpointAltitude,
+	}
+	if naming == SnakeCase {
+		return passSnake(v)
+	}
+	return v
+}
+
+// MarshalJSON encodes the pass with camelCase keys and the duration in seconds.
+func (p Pass) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.JSONValue(CamelCase))
+}
@@ -0,0 +1,188 @@
+package satellite
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		v        float64
+		decimals int
+		want     float64
+	}{
+		{123.456789, 3, 123.457},
+		{-0.0004, 3, 0},
+		{-12.3455, 2, -12.35},
+		{2.5, 0, 3},
+		{123.456789, FullPrecision, 123.456789},
+	}
+	for _, tt := range tests {
+		if got := RoundTo(tt.v, tt.decimals); got != tt.want || math.Signbit(got) != math.Signbit(tt.want) {
+			t.Errorf("RoundTo(%v, %d) = %v, want %v", tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestRoundedJSON(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	angles := &ObservationAngles{Time: at, Azimuth: 123.456789, Elevation: 45.0004999, Range: 1234.56789, RangeRate: -6.54321}
+	pos := &SatellitePosition{Time: at, X: 6778.123456, Y: -1.0000001, Z: 0.5, Vx: 7.6543219, Vy: 0, Vz: -0.0001}
+	pass := Pass{AOS: at, LOS: at.Add(10 * time.Minute), MaxElevation: 67.891234, AOSAzimuth: 300.12345,
+		Duration: 10 * time.Minute, MinRange: 512.34567, SubpointLatitude: 39.999999, SubpointLongitude: -105.0004}
+
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"angles", angles.Rounded(3),
+			`{"time":"2024-03-01T12:00:00Z","azimuth":123.457,"elevation":45,"range":1234.568,"rangeRate":-6.543}`},
+		{"position", pos.Rounded(2),
+			`{"time":"2024-03-01T12:00:00Z","x":6778.12,"y":-1,"z":0.5,"vx":7.65,"vy":0,"vz":0}`},
+		{"pass", pass.Rounded(1),
+			`{"aos":"2024-03-01T12:00:00Z","los":"2024-03-01T12:10:00Z","maxElevation":67.9,` +
+				`"maxElevationTime":"0001-01-01T00:00:00Z","aosAzimuth":300.1,"losAzimuth":0,"durationSeconds":600,` +
+				`"minRange":512.3,"minRangeTime":"0001-01-01T00:00:00Z","subpointLatitude":40,"subpointLongitude":-105,"subpointAltitude":0}`},
+		{"full precision", angles.Rounded(FullPrecision),
+			`{"time":"2024-03-01T12:00:00Z","azimuth":123.456789,"elevation":45.0004999,"range":1234.56789,"rangeRate":-6.54321}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.v)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := string(data); got != tt.want {
+			t.Errorf("%s JSON:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+
+	// Rounding copies; the originals keep full precision
+	if angles.Azimuth != 123.456789 || pos.X != 6778.123456 || pass.MaxElevation != 67.891234 {
+		t.Error("Rounded modified the original value")
+	}
+	if data, _ := json.Marshal(angles); !strings.Contains(string(data), "123.456789") {
+		t.Errorf("unrounded angles marshal as %s", data)
+	}
+	if (*ObservationAngles)(nil).Rounded(3) != nil || (*SatellitePosition)(nil).Rounded(3) != nil {
+		t.Error("Rounded of nil is not nil")
+	}
+}
+
+func TestParseJSONNaming(t *testing.T) {
+	for in, want := range map[string]JSONNaming{"camel": CamelCase, " Snake ": SnakeCase, "SNAKE": SnakeCase} {
+		if got, err := ParseJSONNaming(in); err != nil || got != want {
+			t.Errorf("ParseJSONNaming(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseJSONNaming("kebab"); err == nil {
+		t.Error("ParseJSONNaming accepted kebab")
+	}
+}
+
+// jsonKeys returns the keys of the JSON object v encodes to, sorted.
+func jsonKeys(t *testing.T, v any) []string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		t.Fatalf("%s is not an object: %v", data, err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestJSONValueNaming(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tle := issTLE()
+	sat := &Satellite{NoradID: 25544, Name: "ISS (ZARYA)", TLE: tle, TLEHistory: []TLE{*tle}, MissingFromFeed: true,
+		SATCAT: &SATCAT{NoradID: 25544, Name: "ISS (ZARYA)"}}
+	angles := &ObservationAngles{Time: at, Azimuth: 180, Elevation: 45}
+	pass := &Pass{AOS: at, LOS: at.Add(10 * time.Minute), Duration: 10 * time.Minute}
+	stats := &SetStats{Count: 2, Regimes: map[OrbitRegime]int{RegimeLEO: 2}, Types: map[string]int{"ROCKET BODY": 1, "payloadType": 1}}
+
+	tests := []struct {
+		name         string
+		value        func(JSONNaming) any
+		camel, snake string
+	}{
+		{"satellite", sat.JSONValue,
+			"apogee decayDate fetchedAt inclination intlId launchDate launchSite missingFromFeed name noradId objectType orbitRegime owner " +
+				"perigee period rcsSize satcat tle tleEpoch tleHistory tleUpdatedAt",
+			"apogee decay_date fetched_at inclination intl_id launch_date launch_site missing_from_feed name norad_id object_type orbit_regime owner " +
+				"perigee period rcs_size satcat tle tle_epoch tle_history tle_updated_at"},
+		{"SATCAT", sat.SATCAT.JSONValue,
+			"apogee decayDate id inclination intlId launchDate launchSite name noradId objectType owner perigee period rcsSize",
+			"apogee decay_date id inclination intl_id launch_date launch_site name norad_id object_type owner perigee period rcs_size"},
+		{"angles", angles.JSONValue,
+			"azimuth elevation range rangeRate time",
+			"azimuth elevation range range_rate time"},
+		{"pass", pass.JSONValue,
+			"aos aosAzimuth durationSeconds los losAzimuth maxElevation maxElevationTime minRange minRangeTime " +
+				"subpointAltitude subpointLatitude subpointLongitude",
+			"aos aos_azimuth duration_seconds los los_azimuth max_elevation max_elevation_time min_range min_range_time " +
+				"subpoint_altitude subpoint_latitude subpoint_longitude"},
+		{"set statistics", stats.JSONValue,
+			"count maxApogee maxInclination meanInclination meanPeriod minInclination minPerigee orbitCount regimes types",
+			"count max_apogee max_inclination mean_inclination mean_period min_inclination min_perigee orbit_count regimes types"},
+	}
+	for _, tt := range tests {
+		for naming, want := range map[JSONNaming]string{CamelCase: tt.camel, SnakeCase: tt.snake} {
+			if got := strings.Join(jsonKeys(t, tt.value(naming)), " "); got != want {
+				t.Errorf("%s %s keys:\n got %s\nwant %s", tt.name, naming, got, want)
+			}
+		}
+	}
+
+	// Camel case is the types' own encoding
+	for _, v := range []interface{ JSONValue(JSONNaming) any }{sat, sat.SATCAT, angles, pass, stats} {
+		own, _ := json.Marshal(v)
+		camel, _ := json.Marshal(v.JSONValue(CamelCase))
+		if string(own) != string(camel) {
+			t.Errorf("camelCase JSONValue encodes as\n%s\nnot as the type does\n%s", camel, own)
+		}
+	}
+
+	// Nested values take the naming too, and map keys are data, kept as they are
+	visible := &VisibleSatellite{Satellite: sat, Angles: angles}
+	data, err := json.Marshal(visible.JSONValue(SnakeCase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"satellite":{"norad_id":25544`, `"satcat":{"id":"","intl_id":""`, `"range_rate":0`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("snake_case visible satellite %s does not contain %s", data, want)
+		}
+	}
+	data, err = json.Marshal(stats.JSONValue(SnakeCase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"regimes":{"LEO":2}`, `"types":{"ROCKET BODY":1,"payloadType":1}`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("snake_case statistics %s do not keep the map keys %s", data, want)
+		}
+	}
+
+	// Nil values stay nil, so they encode as null
+	if sat.JSONValue(SnakeCase) == nil || (*Satellite)(nil).JSONValue(SnakeCase) != nil ||
+		(*SATCAT)(nil).JSONValue(SnakeCase) != nil || (*ObservationAngles)(nil).JSONValue(SnakeCase) != nil ||
+		(*Pass)(nil).JSONValue(CamelCase) != nil {
+		t.Error("JSONValue of nil is not nil")
+	}
+	data, err = json.Marshal((&Satellite{NoradID: 1}).JSONValue(SnakeCase))
+	if err != nil || !strings.Contains(string(data), `"satcat":null`) {
+		t.Errorf("snake_case satellite without SATCAT = %s, %v; want a null satcat", data, err)
+	}
+}
@@ -0,0 +1,182 @@
+package satellite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kmlNamespace is the KML 2.2 namespace
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name"`
+	Description string         `xml:"description,omitempty"`
+	TimeStamp   *kmlTimeStamp  `xml:"TimeStamp,omitempty"`
+	Point       *kmlGeometry   `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+	Polygon     *kmlPolygon    `xml:"Polygon,omitempty"`
+}
+
+type kmlTimeStamp struct {
+	When string `xml:"when"`
+}
+
+type kmlGeometry struct {
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Extrude int `xml:"extrude"`
+	kmlGeometry
+}
+
+type kmlPolygon struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+}
+
+// kmlCoordinate formats a position as KML's "lon,lat,alt" with the altitude
+// in meters.
+func kmlCoordinate(pos *SatellitePosition) string {
+	lat, lon, altKm := SubSatellitePoint(pos)
+	return strconv.FormatFloat(lon, 'f', 6, 64) + "," +
+		strconv.FormatFloat(lat, 'f', 6, 64) + "," +
+		strconv.FormatFloat(altKm*1000, 'f', 0, 64)
+}
+
+// marshalKML encodes a KML document with an XML declaration.
+func marshalKML(doc kmlDocument) ([]byte, error) {
+	data, err := xml.MarshalIndent(kmlRoot{Xmlns: kmlNamespace, Document: doc}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// groundTrackKML returns the document of GroundTrackKML and the positions
+// its path was drawn from.
+func groundTrackKML(tle *TLE, start, end time.Time, step time.Duration) (kmlDocument, []*SatellitePosition, error) {
+	positions, err := PropagateRange(tle, start, end, step)
+	if err != nil {
+		return kmlDocument{}, nil, err
+	}
+
+	coords := make([]string, len(positions))
+	for i, pos := range positions {
+		coords[i] = kmlCoordinate(pos)
+	}
+
+	name := tle.Name
+	if name == "" {
+		name = fmt.Sprintf("%d", tle.GetNoradID())
+	}
+
+	doc := kmlDocument{
+		Name: name,
+		Placemarks: []kmlPlacemark{{
+			Name: name,
+			Description: fmt.Sprintf("%s to %s", start.UTC().Format(time.RFC3339),
+				end.UTC().Format(time.RFC3339)),
+			LineString: &kmlLineString{
+				Extrude: 1,
+				kmlGeometry: kmlGeometry{
+					AltitudeMode: "absolute",
+					Coordinates:  strings.Join(coords, " "),
+				},
+			},
+		}},
+	}
+	return doc, positions, nil
+}
+
+// GroundTrackKML returns the satellite's path every step from start to end
+// inclusive as a KML document for Google Earth. The path is a LineString at
+// the satellite's altitude (absolute mode), extruded to the ground so the
+// ground track shows beneath it.
+func GroundTrackKML(tle *TLE, start, end time.Time, step time.Duration) ([]byte, error) {
+	doc, _, err := groundTrackKML(tle, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	return marshalKML(doc)
+}
+
+// GroundTrackFootprintKML returns the document of GroundTrackKML with a second
+// placemark: a Polygon on the ground of the satellite's footprint at start,
+// with points vertices (see FootprintCircle).
+func GroundTrackFootprintKML(tle *TLE, start, end time.Time, step time.Duration, points int) ([]byte, error) {
+	doc, positions, err := groundTrackKML(tle, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	circle := FootprintCircle(positions[0], points)
+	if circle == nil {
+		return nil, fmt.Errorf("no footprint at %v", start)
+	}
+	ring := make([]string, len(circle))
+	for i, p := range circle {
+		ring[i] = strconv.FormatFloat(p[1], 'f', 6, 64) + "," + strconv.FormatFloat(p[0], 'f', 6, 64)
+	}
+	doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+		Name:        doc.Name + " footprint",
+		Description: fmt.Sprintf("Radius %.0f km", FootprintRadiusKm(positions[0])),
+		TimeStamp:   &kmlTimeStamp{When: start.UTC().Format(time.RFC3339)},
+		Polygon:     &kmlPolygon{Tessellate: 1, Coordinates: strings.Join(ring, " ")},
+	})
+	return marshalKML(doc)
+}
+
+// PassKML returns a KML document with placemarks at the satellite's position
+// at AOS, culmination, and LOS of a pass, each time-stamped and at the
+// satellite's altitude (absolute mode). The pass must have been predicted
+// from tle.
+func PassKML(pass Pass, tle *TLE) ([]byte, error) {
+	events := []struct {
+		name string
+		t    time.Time
+		desc string
+	}{
+		{"AOS", pass.AOS, fmt.Sprintf("Azimuth %.1f°", pass.AOSAzimuth)},
+		{"Culmination", pass.MaxElevationTime, fmt.Sprintf("Elevation %.1f°", pass.MaxElevation)},
+		{"LOS", pass.LOS, fmt.Sprintf("Azimuth %.1f°", pass.LOSAzimuth)},
+	}
+
+	doc := kmlDocument{Name: fmt.Sprintf("Pass %s", pass.AOS.UTC().Format(time.RFC3339))}
+	if tle.Name != "" {
+		doc.Name = tle.Name + " " + doc.Name
+	}
+
+	for _, e := range events {
+		pos, err := PropagateSatellite(tle, e.t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", e.t, err)
+		}
+		doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+			Name:        e.name,
+			Description: e.desc,
+			TimeStamp:   &kmlTimeStamp{When: e.t.UTC().Format(time.RFC3339)},
+			Point: &kmlGeometry{
+				AltitudeMode: "absolute",
+				Coordinates:  kmlCoordinate(pos),
+			},
+		})
+	}
+
+	return marshalKML(doc)
+}
@@ -0,0 +1,205 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	moonRadiusKm = 1737.4 // mean lunar radius in km
+
+	// transitFineStep is the resolution used to time a transit across the
+	// Moon's disk, which takes well under a second for a LEO satellite.
+	transitFineStep = 10 * time.Millisecond
+)
+
+// moonPositionECI returns the Moon's geocentric position in km in an
+// Earth-centered inertial frame (mean equator and equinox of date).
+// Longitude and latitude use the truncated lunar theory of Montenbruck and
+// Pfleger (accurate to ~1 arcminute); distance uses the largest terms of the
+// Meeus series (accurate to ~10 km).
+func moonPositionECI(t time.Time) (x, y, z float64) {
+	const arc = 206264.8062 // arcseconds per radian
+	T := (julianDate(t) - 2451545.0) / 36525.0
+
+	frac := func(v float64) float64 { return v - math.Floor(v) }
+
+	// Mean arguments in radians (L0 in revolutions)
+	L0 := frac(0.606433 + 1336.855225*T)            // mean longitude of the Moon
+	l := 2 * math.Pi * frac(0.374897+1325.552410*T) // Moon's mean anomaly
+	ls := 2 * math.Pi * frac(0.993133+99.997361*T)  // Sun's mean anomaly
+	D := 2 * math.Pi * frac(0.827361+1236.853086*T) // mean elongation of the Moon
+	F := 2 * math.Pi * frac(0.259086+1342.227825*T) // argument of latitude
+
+	// Perturbations in longitude (arcseconds)
+	dL := 22640*math.Sin(l) - 4586*math.Sin(l-2*D) + 2370*math.Sin(2*D) +
+		769*math.Sin(2*l) - 668*math.Sin(ls) - 412*math.Sin(2*F) -
+		212*math.Sin(2*l-2*D) - 206*math.Sin(l+ls-2*D) + 192*math.Sin(l+2*D) -
+		165*math.Sin(ls-2*D) - 125*math.Sin(D) - 110*math.Sin(l+ls) +
+		148*math.Sin(l-ls) - 55*math.Sin(2*F-2*D)
+
+	// Perturbations in latitude
+	S := F + (dL+412*math.Sin(2*F)+541*math.Sin(ls))/arc
+	h := F - 2*D
+	N := -526*math.Sin(h) + 44*math.Sin(l+h) - 31*math.Sin(-l+h) -
+		23*math.Sin(ls+h) + 11*math.Sin(-ls+h) - 25*math.Sin(-2*l+F) +
+		21*math.Sin(-l+F)
+
+	lon := 2 * math.Pi * frac(L0+dL/1296.0e3)
+	lat := (18520.0*math.Sin(S) + N) / arc
+
+	distance := 385000.56 - 20905.355*math.Cos(l) - 3699.111*math.Cos(2*D-l) -
+		2955.968*math.Cos(2*D) - 569.925*math.Cos(2*l) + 48.888*math.Cos(ls) -
+		3.149*math.Cos(2*F) + 246.158*math.Cos(2*D-2*l) - 152.138*math.Cos(2*D-ls-l) -
+		170.733*math.Cos(2*D+l) - 204.586*math.Cos(2*D-ls) - 129.620*math.Cos(ls-l) +
+		108.743*math.Cos(D) + 104.755*math.Cos(ls+l)
+
+	// Ecliptic to equatorial coordinates
+	obliquity := (23.43929111 - 0.0130042*T) * math.Pi / 180.0
+	ex := distance * math.Cos(lat) * math.Cos(lon)
+	ey := distance * math.Cos(lat) * math.Sin(lon)
+	ez := distance * math.Sin(lat)
+
+	return ex,
+		ey*math.Cos(obliquity) - ez*math.Sin(obliquity),
+		ey*math.Sin(obliquity) + ez*math.Cos(obliquity)
+}
+
+// moonPositionECEF returns the Moon's position in km in the Earth-fixed frame,
+// rotating the inertial position by Greenwich Mean Sidereal Time.
+func moonPositionECEF(t time.Time) (x, y, z float64) {
+	return rot3(gmst(t)).apply(moonPositionECI(t))
+}
+
+// MoonAngles returns the topocentric azimuth, elevation, and range of the
+// Moon's center as seen by the observer at t. Parallax is included, which
+// shifts the Moon by up to ~1° from its geocentric position.
+func MoonAngles(observer *ObserverPosition, t time.Time) *ObservationAngles {
+	x, y, z := moonPositionECEF(t)
+	return CalculateObservationAngles(&SatellitePosition{Time: t, X: x, Y: y, Z: z}, observer)
+}
+
+// MoonAngularRadius returns the apparent angular radius of the Moon in degrees
+// at the given topocentric range in km.
+func MoonAngularRadius(rangeKm float64) float64 {
+	return math.Asin(moonRadiusKm/rangeKm) * 180.0 / math.Pi
+}
+
+// LunarTransitEvent describes a satellite crossing the Moon's disk as seen by an observer.
+type LunarTransitEvent struct {
+	Time       time.Time     // time of closest approach to the Moon's center
+	Separation float64       // angular distance from the Moon's center at Time, degrees
+	MoonRadius float64       // apparent angular radius of the Moon, degrees
+	Duration   time.Duration // time spent in front of the disk
+	Satellite  *ObservationAngles
+	Moon       *ObservationAngles
+}
+
+// LunarTransit returns the times between start and end when the satellite's
+// apparent position falls within the Moon's disk as seen by the observer.
+// Both the satellite and the Moon must be above the horizon.
+//
+// The separation is sampled every step; each local minimum is refined to the
+// second and then timed at 10 ms resolution by extrapolating the satellite's
+// state along its velocity, since a LEO transit lasts well under a second.
+func LunarTransit(tle *TLE, observer *ObserverPosition, start, end time.Time, step time.Duration) ([]LunarTransitEvent, error) {
+	if tle == nil {
+		return nil, fmt.Errorf("TLE is nil")
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	// separation is +Inf while either body is below the horizon
+	separation := func(t time.Time) (float64, error) {
+		sat, err := observe(tle, observer, t)
+		if err != nil {
+			return 0, err
+		}
+		moon := MoonAngles(observer, t)
+		if sat.Elevation < 0 || moon.Elevation < 0 {
+			return math.Inf(1), nil
+		}
+		return AngularSeparation(sat, moon), nil
+	}
+
+	var events []LunarTransitEvent
+	var prevSep, curSep float64
+	var cur time.Time
+	n := 0
+
+	for t := start; !t.After(end); t = t.Add(step) {
+		sep, err := separation(t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		// A local minimum at cur brackets the closest approach in [cur-step, cur+step]
+		if n >= 2 && !math.IsInf(curSep, 1) && curSep <= prevSep && curSep < sep {
+			event, err := refineLunarTransit(tle, observer, cur.Add(-step), cur.Add(step), separation)
+			if err != nil {
+				return nil, err
+			}
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+
+		prevSep, curSep, cur = curSep, sep, t
+		n++
+	}
+
+	return events, nil
+}
+
+// refineLunarTransit locates the closest approach to the Moon within [lo, hi]
+// and returns the transit, or nil if the satellite misses the disk.
+func refineLunarTransit(tle *TLE, observer *ObserverPosition, lo, hi time.Time, separation func(time.Time) (float64, error)) (*LunarTransitEvent, error) {
+	coarse, _, err := goldenSectionMin(lo, hi, separation)
+	if err != nil {
+		return nil, err
+	}
+
+	// SGP4 is evaluated at whole seconds; extrapolate linearly in between
+	base := coarse.Truncate(time.Second)
+	pos, err := PropagateSatellite(tle, base)
+	if err != nil {
+		return nil, err
+	}
+	moon := MoonAngles(observer, base)
+	radius := MoonAngularRadius(moon.Range)
+
+	best := &LunarTransitEvent{Separation: math.Inf(1), MoonRadius: radius, Moon: moon}
+	for dt := -time.Second; dt <= 2*time.Second; dt += transitFineStep {
+		s := dt.Seconds()
+		sat := CalculateObservationAngles(&SatellitePosition{
+			Time: base.Add(dt),
+			X:    pos.X + pos.Vx*s,
+			Y:    pos.Y + pos.Vy*s,
+			Z:    pos.Z + pos.Vz*s,
+			Vx:   pos.Vx,
+			Vy:   pos.Vy,
+			Vz:   pos.Vz,
+		}, observer)
+		if sat.Elevation < 0 {
+			continue
+		}
+
+		sep := AngularSeparation(sat, moon)
+		if sep < radius {
+			best.Duration += transitFineStep
+		}
+		if sep < best.Separation {
+			best.Time, best.Separation, best.Satellite = sat.Time, sep, sat
+		}
+	}
+
+	if best.Separation >= radius {
+		return nil, nil
+	}
+	return best, nil
+}
@@ -0,0 +1,86 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// transitObserver returns an observer on the ground for whom the satellite
+// lies exactly in front of the Moon's center at t, by following the line from
+// the Moon through the satellite down to the Earth's surface. ok is false if
+// that line misses the Earth or meets it at a low elevation.
+func transitObserver(t *testing.T, tle *TLE, at time.Time) (observer *ObserverPosition, ok bool) {
+	t.Helper()
+	pos, err := PropagateSatellite(tle, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mx, my, mz := moonPositionECEF(at)
+
+	// Unit vector from the Moon to the satellite
+	dx, dy, dz := pos.X-mx, pos.Y-my, pos.Z-mz
+	d := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	dx, dy, dz = dx/d, dy/d, dz/d
+
+	// Nearest root k of |S + k·d| = R, the ray continuing past the satellite
+	b := pos.X*dx + pos.Y*dy + pos.Z*dz
+	c := pos.X*pos.X + pos.Y*pos.Y + pos.Z*pos.Z - meanEarthRadiusKm*meanEarthRadiusKm
+	disc := b*b - c
+	if disc <= 0 {
+		return nil, false
+	}
+	k := -b - math.Sqrt(disc)
+	lat, lon, altKm := ecefToGeodetic(pos.X+k*dx, pos.Y+k*dy, pos.Z+k*dz)
+	observer = &ObserverPosition{Latitude: lat, Longitude: lon, Altitude: altKm * 1000}
+
+	if CalculateObservationAngles(pos, observer).Elevation < 30 {
+		return nil, false
+	}
+	return observer, true
+}
+
+func TestLunarTransitSynthetic(t *testing.T) {
+	tle := issTLE()
+
+	// Find an instant at which the ISS can be placed in front of the Moon
+	var at time.Time
+	var observer *ObserverPosition
+	for minutes := 0; observer == nil; minutes++ {
+		if minutes > 24*60 {
+			t.Fatal("no instant in a day lines the ISS up with the Moon above 30° elevation")
+		}
+		at = testEpoch.Add(time.Duration(minutes) * time.Minute)
+		observer, _ = transitObserver(t, tle, at)
+	}
+
+	events, err := LunarTransit(tle, observer, at.Add(-5*time.Minute), at.Add(5*time.Minute), 10*time.Second)
+	if err != nil {
+		t.Fatalf("LunarTransit: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("found %d transits around %v, want 1", len(events), at)
+	}
+	event := events[0]
+	if d := event.Time.Sub(at).Abs(); d > 100*time.Millisecond {
+		t.Errorf("transit at %v, want %v", event.Time, at)
+	}
+	if event.Separation > 0.05 {
+		t.Errorf("closest approach %.3f° from the Moon's center, want a central transit", event.Separation)
+	}
+	assertNear(t, "Moon radius", event.MoonRadius, 0.26, 0.02)
+	if event.Duration <= 0 || event.Duration > 2*time.Second {
+		t.Errorf("transit lasts %v, want under a second or two", event.Duration)
+	}
+
+	// 100 km to the side the ISS misses the Moon by many degrees
+	aside := *observer
+	aside.Latitude += 1
+	events, err = LunarTransit(tle, &aside, at.Add(-5*time.Minute), at.Add(5*time.Minute), 10*time.Second)
+	if err != nil {
+		t.Fatalf("LunarTransit: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("observer 1° away sees %d transits, want none", len(events))
+	}
+}
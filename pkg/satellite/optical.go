@@ -0,0 +1,162 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// darkSunElevation is the Sun elevation in degrees below which the sky is
+	// dark enough to see satellites (end of civil twilight)
+	darkSunElevation = -6.0
+
+	// defaultStandardMagnitude is the assumed visual magnitude of a satellite at
+	// 1000 km range and 90° phase angle, used when the actual value is unknown
+	defaultStandardMagnitude = 4.0
+
+	// bestPassMinElevation is the minimum elevation in degrees for passes
+	// considered by BestPass
+	bestPassMinElevation = 10.0
+)
+
+// opticalSample describes the naked-eye view of a satellite at one instant.
+type opticalSample struct {
+	visible   bool    // observer in darkness and satellite sunlit
+	magnitude float64 // estimated visual magnitude; meaningful only when visible
+}
+
+// estimateMagnitude estimates the visual magnitude of a satellite with the given
+// standard magnitude at rangeKm and phase angle (radians, Sun-satellite-observer),
+// modeling the satellite as a diffuse sphere.
+func estimateMagnitude(standardMagnitude, rangeKm, phaseAngle float64) float64 {
+	// Diffuse-sphere phase function, normalized to 1 at 90°
+	phase := math.Sin(phaseAngle) + (math.Pi-phaseAngle)*math.Cos(phaseAngle)
+	if phase <= 0 {
+		return math.Inf(1)
+	}
+	return standardMagnitude + 5*math.Log10(rangeKm/1000.0) - 2.5*math.Log10(phase)
+}
+
+// IsDark reports whether the Sun is at or below sunElevThreshold degrees at the
+// observer, e.g. -6 for the end of civil twilight or -18 for full darkness.
+func IsDark(observer *ObserverPosition, t time.Time, sunElevThreshold float64) bool {
+	return SunElevation(observer, t) <= sunElevThreshold
+}
+
+// ObserverInDarkness reports whether the sky is dark enough at the observer for
+// sunlit satellites to be seen by eye, i.e. the Sun is below civil twilight.
+func ObserverInDarkness(observer *ObserverPosition, t time.Time) bool {
+	return IsDark(observer, t, darkSunElevation)
+}
+
+// observeOptical determines whether the satellite can be seen by eye at t and
+// estimates its brightness.
+func observeOptical(tle *TLE, observer *ObserverPosition, t time.Time) (*opticalSample, error) {
+	if !ObserverInDarkness(observer, t) {
+		return &opticalSample{}, nil
+	}
+
+	pos, err := PropagateSatellite(tle, t)
+	if err != nil {
+		return nil, err
+	}
+
+	sx, sy, sz := sunPositionECEF(t)
+	if illumination(pos, sx, sy, sz) == IlluminationUmbra {
+		return &opticalSample{}, nil
+	}
+
+	// Phase angle at the satellite between the Sun and the observer
+	ox, oy, oz := observerECEF(observer)
+	toSun := [3]float64{sx - pos.X, sy - pos.Y, sz - pos.Z}
+	toObs := [3]float64{ox - pos.X, oy - pos.Y, oz - pos.Z}
+	sunDist := math.Sqrt(toSun[0]*toSun[0] + toSun[1]*toSun[1] + toSun[2]*toSun[2])
+	obsDist := math.Sqrt(toObs[0]*toObs[0] + toObs[1]*toObs[1] + toObs[2]*toObs[2])
+	cosPhase := (toSun[0]*toObs[0] + toSun[1]*toObs[1] + toSun[2]*toObs[2]) / (sunDist * obsDist)
+	phaseAngle := math.Acos(math.Max(-1, math.Min(1, cosPhase)))
+
+	return &opticalSample{
+		visible:   true,
+		magnitude: estimateMagnitude(defaultStandardMagnitude, obsDist, phaseAngle),
+	}, nil
+}
+
+// PassScore is a pass with the score breakdown used to rank it for naked-eye viewing.
+// Component scores range from 0 to 1.
+type PassScore struct {
+	Pass            Pass
+	VisibleDuration time.Duration // time the satellite is sunlit while the observer is in darkness
+	PeakMagnitude   float64       // brightest estimated magnitude while visible; +Inf if never visible
+	SunAzimuth      float64       // Sun's azimuth at the observer at culmination, degrees
+	SunElevation    float64       // Sun's elevation at the observer at culmination, degrees
+
+	ElevationScore  float64 // peak elevation relative to zenith
+	DarknessScore   float64 // visible duration, saturating at five minutes
+	BrightnessScore float64 // peak magnitude, from 0 at magnitude 6 to 1 at magnitude -2
+	Total           float64 // weighted sum of the component scores
+}
+
+// BestPass finds the pass between nightStart and nightEnd best suited to naked-eye
+// viewing, scoring each pass above 10° by peak elevation, time spent sunlit while
+// the observer is in darkness, and estimated brightness. Passes are found and
+// sampled at step. Brightness assumes a typical standard magnitude of 4.
+// Returns ErrNoPassFound if there are no passes in the window.
+func BestPass(tle *TLE, observer *ObserverPosition, nightStart, nightEnd time.Time, step time.Duration) (*PassScore, error) {
+	passes, err := PredictPasses(tle, observer, nightStart, nightEnd, step, bestPassMinElevation)
+	if err != nil {
+		return nil, err
+	}
+	if len(passes) == 0 {
+		return nil, fmt.Errorf("%w between %v and %v", ErrNoPassFound, nightStart, nightEnd)
+	}
+
+	var best *PassScore
+	for _, pass := range passes {
+		score, err := scorePass(tle, observer, pass, step)
+		if err != nil {
+			return nil, err
+		}
+		if best == nil || score.Total > best.Total {
+			best = score
+		}
+	}
+
+	return best, nil
+}
+
+// scorePass samples a pass at step and computes its viewing score.
+func scorePass(tle *TLE, observer *ObserverPosition, pass Pass, step time.Duration) (*PassScore, error) {
+	sun := SunObservationAngles(observer, pass.MaxElevationTime)
+	score := &PassScore{
+		Pass:          pass,
+		PeakMagnitude: math.Inf(1),
+		SunAzimuth:    sun.Azimuth,
+		SunElevation:  sun.Elevation,
+	}
+
+	for t := pass.AOS; !t.After(pass.LOS); t = t.Add(step) {
+		sample, err := observeOptical(tle, observer, t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		if !sample.visible {
+			continue
+		}
+		score.VisibleDuration += step
+		score.PeakMagnitude = math.Min(score.PeakMagnitude, sample.magnitude)
+	}
+	if score.VisibleDuration > pass.Duration {
+		score.VisibleDuration = pass.Duration
+	}
+
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	score.ElevationScore = clamp(pass.MaxElevation / 90.0)
+	score.DarknessScore = clamp(score.VisibleDuration.Minutes() / 5.0)
+	if !math.IsInf(score.PeakMagnitude, 1) {
+		score.BrightnessScore = clamp((6.0 - score.PeakMagnitude) / 8.0)
+	}
+	score.Total = 0.4*score.ElevationScore + 0.3*score.DarknessScore + 0.3*score.BrightnessScore
+
+	return score, nil
+}
@@ -0,0 +1,58 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBestPassPrefersHighDarkPass(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+
+	// 12:34 UTC is a 55° pass in nautical twilight; the two after it are
+	// low passes in daylight
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 1, 16, 30, 0, 0, time.UTC)
+	passes, err := PredictPasses(tle, observer, start, end, 30*time.Second, bestPassMinElevation)
+	if err != nil || len(passes) != 3 {
+		t.Fatalf("PredictPasses = %d passes, %v; want 3", len(passes), err)
+	}
+
+	dark, err := scorePass(tle, observer, passes[0], 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dark.Pass.MaxElevation < 50 || dark.SunElevation > -6 || dark.VisibleDuration == 0 || dark.PeakMagnitude > 3 {
+		t.Fatalf("first pass is not high, dark, and bright: %+v", dark)
+	}
+
+	for _, pass := range passes[1:] {
+		day, err := scorePass(tle, observer, pass, 30*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if day.SunElevation < 0 || day.VisibleDuration != 0 || !math.IsInf(day.PeakMagnitude, 1) {
+			t.Errorf("pass at %v is not a daytime pass: %+v", pass.AOS, day)
+		}
+		if day.DarknessScore != 0 || day.BrightnessScore != 0 || day.Total >= dark.Total {
+			t.Errorf("daytime pass at %v scores %.2f (%+v), want below the dark pass's %.2f", pass.AOS, day.Total, day, dark.Total)
+		}
+	}
+
+	best, err := BestPass(tle, observer, start, end, 30*time.Second)
+	if err != nil {
+		t.Fatalf("BestPass: %v", err)
+	}
+	if !best.Pass.AOS.Equal(passes[0].AOS) || best.Total != dark.Total {
+		t.Errorf("BestPass chose the pass at %v scoring %.2f, want the dark pass at %v", best.Pass.AOS, best.Total, passes[0].AOS)
+	}
+	sun := SunObservationAngles(observer, best.Pass.MaxElevationTime)
+	if best.SunAzimuth != sun.Azimuth || best.SunElevation != sun.Elevation {
+		t.Errorf("Sun at culmination = %.1f°/%.1f°, want %.1f°/%.1f°", best.SunAzimuth, best.SunElevation, sun.Azimuth, sun.Elevation)
+	}
+
+	if _, err := BestPass(tle, observer, start, start.Add(10*time.Minute), 30*time.Second); err == nil {
+		t.Error("BestPass found a pass in a window with none")
+	}
+}
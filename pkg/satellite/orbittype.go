@@ -0,0 +1,117 @@
+package satellite
+
+import (
+	"math"
+	"strings"
+)
+
+// OrbitType is a finer classification than OrbitRegime for orbits with a
+// particular purpose or shape. An orbit has one type and one regime, e.g. a
+// sun-synchronous orbit is also LEO.
+type OrbitType string
+
+const (
+	OrbitTypeSunSynchronous OrbitType = "SSO"       // node precesses with the Sun, ~97-99° inclination in LEO
+	OrbitTypeMolniya        OrbitType = "MOLNIYA"   // ~12h highly elliptical orbit at the critical inclination
+	OrbitTypeTundra         OrbitType = "TUNDRA"    // ~24h elliptical orbit at the critical inclination
+	OrbitTypePolar          OrbitType = "POLAR"     // 80-100° inclination, not sun-synchronous
+	OrbitTypeGraveyard      OrbitType = "GRAVEYARD" // near-circular disposal orbit just above GEO
+	OrbitTypeOther          OrbitType = "OTHER"     // none of the above
+	OrbitTypeUnknown        OrbitType = "UNKNOWN"   // insufficient data
+)
+
+// OrbitTypes lists every OrbitType.
+var OrbitTypes = []OrbitType{
+	OrbitTypeSunSynchronous, OrbitTypeMolniya, OrbitTypeTundra, OrbitTypePolar,
+	OrbitTypeGraveyard, OrbitTypeOther, OrbitTypeUnknown,
+}
+
+// OrbitTypeSpecial matches every type except OTHER and UNKNOWN in
+// SearchCriteria.OrbitType.
+const OrbitTypeSpecial = "SPECIAL"
+
+// Special reports whether t is one of the named orbit types rather than
+// OTHER or UNKNOWN.
+func (t OrbitType) Special() bool {
+	return t != OrbitTypeOther && t != OrbitTypeUnknown && t != ""
+}
+
+const (
+	criticalInclination = 63.4          // degrees, where apsidal precession from J2 vanishes
+	geoAltitudeKm       = 35786.0       // geostationary altitude
+	earthJ2             = 1.08262668e-3 // Earth's oblateness coefficient
+	earthMu             = 398600.4418   // km^3/s^2
+
+	// Nodal precession of a sun-synchronous orbit, one turn per tropical year
+	sunSynchronousRate = 2 * math.Pi / (365.2422 * 86400.0) // rad/s
+)
+
+// SunSynchronousInclination returns the inclination in degrees at which an
+// orbit with the given apogee and perigee altitudes (km) is sun-synchronous,
+// from the J2 nodal precession. The altitudes are taken above the equatorial
+// radius, which J2 is referenced to. ok is false if no inclination works,
+// which is the case above roughly 6,000 km.
+func SunSynchronousInclination(apogee, perigee float64) (inclination float64, ok bool) {
+	a := (apogee+perigee)/2.0 + earthRadiusKm
+	e := (apogee - perigee) / (2 * a)
+	p := a * (1 - e*e)
+	n := math.Sqrt(earthMu / (a * a * a))
+
+	// dΩ/dt = -3/2 n J2 (Re/p)^2 cos i
+	cosI := -sunSynchronousRate / (1.5 * n * earthJ2 * math.Pow(earthRadiusKm/p, 2))
+	if cosI < -1 {
+		return 0, false
+	}
+	return math.Acos(cosI) * 180.0 / math.Pi, true
+}
+
+// DetermineOrbitType classifies an orbit as one of the special OrbitTypes
+// from its apogee and perigee altitude (km), period (minutes), and
+// inclination (degrees), the same inputs as DetermineOrbitRegime.
+func DetermineOrbitType(apogee, perigee, period, inclination float64) OrbitType {
+	if apogee <= 0 || perigee <= 0 || period <= 0 {
+		return OrbitTypeUnknown
+	}
+
+	eccentricity := orbitEccentricity(apogee, perigee)
+	critical := math.Abs(inclination-criticalInclination) <= 5.0
+
+	switch {
+	case critical && eccentricity > 0.5 && math.Abs(period-718.0) <= 30.0:
+		return OrbitTypeMolniya
+	case critical && eccentricity >= 0.15 && math.Abs(period-1436.0) <= 30.0:
+		return OrbitTypeTundra
+	case eccentricity < 0.05 && perigee > geoAltitudeKm+150.0 && apogee < geoAltitudeKm+2000.0:
+		return OrbitTypeGraveyard
+	}
+
+	if eccentricity < 0.1 {
+		if sso, ok := SunSynchronousInclination(apogee, perigee); ok && math.Abs(inclination-sso) <= 1.0 {
+			return OrbitTypeSunSynchronous
+		}
+	}
+	if inclination >= 80.0 && inclination <= 100.0 {
+		return OrbitTypePolar
+	}
+	return OrbitTypeOther
+}
+
+// ClassifyOrbitType classifies a satellite with DetermineOrbitType using its
+// SATCAT orbital parameters, or the TLE mean elements when SATCAT has none.
+func ClassifyOrbitType(sat *Satellite) OrbitType {
+	apogee, perigee, period, inclination, ok := satelliteOrbit(sat)
+	if !ok {
+		return OrbitTypeUnknown
+	}
+	return DetermineOrbitType(apogee, perigee, period, inclination)
+}
+
+// matchesOrbitType reports whether sat's orbit type is want, compared
+// case-insensitively, where OrbitTypeSpecial matches any special type.
+func matchesOrbitType(sat *Satellite, want string) bool {
+	orbitType := ClassifyOrbitType(sat)
+	if strings.EqualFold(want, OrbitTypeSpecial) {
+		return orbitType.Special()
+	}
+	return strings.EqualFold(want, string(orbitType))
+}
@@ -0,0 +1,97 @@
+package satellite
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSunSynchronousInclination(t *testing.T) {
+	// Published sun-synchronous inclinations for circular orbits
+	tests := []struct {
+		altitude float64
+		want     float64
+	}{
+		{500, 97.40},
+		{700, 98.19},
+		{800, 98.60},
+		{1000, 99.48},
+	}
+	for _, tt := range tests {
+		got, ok := SunSynchronousInclination(tt.altitude, tt.altitude)
+		if !ok {
+			t.Errorf("no sun-synchronous inclination at %.0f km", tt.altitude)
+			continue
+		}
+		assertNear(t, "sun-synchronous inclination", got, tt.want, 0.02)
+	}
+
+	// At the same semi-major axis eccentricity speeds up the precession, so
+	// the orbit needs an inclination closer to polar
+	circular, _ := SunSynchronousInclination(800, 800)
+	elliptical, _ := SunSynchronousInclination(1400, 200)
+	if elliptical >= circular || elliptical <= 90 {
+		t.Errorf("elliptical orbit needs %.2f°, circular %.2f°, want between 90° and the circular one", elliptical, circular)
+	}
+
+	if got, ok := SunSynchronousInclination(8000, 8000); ok {
+		t.Errorf("sun-synchronous inclination %.1f° at 8000 km, want none", got)
+	}
+}
+
+func TestDetermineOrbitType(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		apogee, perigee, period, inclination float64
+		want                                 OrbitType
+	}{
+		{"Landsat 9", 705, 703, 98.8, 98.2, OrbitTypeSunSynchronous},
+		{"Sentinel-2A", 789, 787, 100.6, 98.6, OrbitTypeSunSynchronous},
+		{"Molniya 1-93", 39700, 1300, 717.8, 62.9, OrbitTypeMolniya},
+		{"Sirius FM-1 (Tundra)", 47000, 24500, 1436.1, 63.4, OrbitTypeTundra},
+		{"Iridium (polar)", 781, 779, 100.4, 86.4, OrbitTypePolar},
+		{"retired GEO in the graveyard", 36150, 36100, 1447.3, 2.1, OrbitTypeGraveyard},
+		{"ISS", 422, 418, 92.9, 51.6, OrbitTypeOther},
+		{"GPS", 20250, 20110, 717.9, 55.0, OrbitTypeOther},
+		{"operational GEO", 35800, 35770, 1436.1, 0.05, OrbitTypeOther},
+		{"98° but far from sun-synchronous altitude", 3000, 2990, 150.5, 98.0, OrbitTypePolar},
+		{"no data", 0, 0, 0, 0, OrbitTypeUnknown},
+	}
+	for _, tt := range tests {
+		if got := DetermineOrbitType(tt.apogee, tt.perigee, tt.period, tt.inclination); got != tt.want {
+			t.Errorf("%s: DetermineOrbitType = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	for _, orbitType := range OrbitTypes {
+		want := orbitType != OrbitTypeOther && orbitType != OrbitTypeUnknown
+		if orbitType.Special() != want {
+			t.Errorf("%s.Special() = %v, want %v", orbitType, orbitType.Special(), want)
+		}
+	}
+}
+
+func TestSearchSatellitesOrbitType(t *testing.T) {
+	satellites := []*Satellite{
+		{NoradID: 1, Apogee: 705, Perigee: 703, Period: 98.8, Inclination: 98.2},
+		{NoradID: 2, Apogee: 39700, Perigee: 1300, Period: 717.8, Inclination: 62.9},
+		{NoradID: 3, Apogee: 422, Perigee: 418, Period: 92.9, Inclination: 51.6},
+		{NoradID: 4},
+		// Classified from the TLE: 98.2° at 14.57 rev/day is ~700 km
+		testSatellite("SSO TLE", makeTLE(5, testEpoch, 98.2, 0, 0.0001, 0, 0, 14.57)),
+	}
+
+	for _, tt := range []struct {
+		orbitType string
+		want      []int
+	}{
+		{"sso", []int{1, 5}},
+		{"MOLNIYA", []int{2}},
+		{"other", []int{3}},
+		{"unknown", []int{4}},
+		{"special", []int{1, 2, 5}},
+	} {
+		if got := searchIDs(t, satellites, SearchCriteria{OrbitType: tt.orbitType}); !slices.Equal(got, tt.want) {
+			t.Errorf("orbit type %s matched %v, want %v", tt.orbitType, got, tt.want)
+		}
+	}
+}
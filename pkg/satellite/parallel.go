@@ -0,0 +1,36 @@
+package satellite
+
+import (
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls fn(i) for every i in [0, n) on a pool of workers
+// goroutines (GOMAXPROCS if workers <= 0) and returns once every call has
+// returned. fn runs concurrently for different i, so it should write its
+// result to slot i of a preallocated slice rather than share state; callers
+// then collect the slice in order, which keeps results deterministic.
+func forEachParallel(n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
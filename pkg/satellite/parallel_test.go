@@ -0,0 +1,28 @@
+package satellite
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachParallel(t *testing.T) {
+	for _, tt := range []struct{ n, workers int }{{0, 0}, {1, 4}, {100, 0}, {100, 1}, {1000, 7}} {
+		calls := make([]int32, tt.n)
+		var running, peak atomic.Int32
+		forEachParallel(tt.n, tt.workers, func(i int) {
+			now := running.Add(1)
+			for p := peak.Load(); now > p && !peak.CompareAndSwap(p, now); p = peak.Load() {
+			}
+			atomic.AddInt32(&calls[i], 1)
+			running.Add(-1)
+		})
+		for i, c := range calls {
+			if c != 1 {
+				t.Errorf("n %d, workers %d: fn(%d) called %d times, want once", tt.n, tt.workers, i, c)
+			}
+		}
+		if tt.workers > 0 && int(peak.Load()) > tt.workers {
+			t.Errorf("n %d: %d calls ran at once, want at most %d workers", tt.n, peak.Load(), tt.workers)
+		}
+	}
+}
@@ -0,0 +1,662 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// nextPassWindow is how far ahead next-pass searches look
+	nextPassWindow = 48 * time.Hour
+	// nextPassStep is the coarse sampling interval for next-pass searches
+	nextPassStep = 60 * time.Second
+	// geoSampleStep is the sampling interval used to locate the culmination
+	// of a geostationary satellite that stays above the horizon
+	geoSampleStep = time.Hour
+	// zenithPassElevation is the maximum elevation in degrees above which a
+	// pass counts as a zenith pass
+	zenithPassElevation = 80.0
+)
+
+// ErrNoPassFound is returned when no pass occurs within the search window.
+var ErrNoPassFound = errors.New("no pass found")
+
+// Pass represents a single pass of a satellite above an observer's minimum elevation.
+type Pass struct {
+	AOS              time.Time     // acquisition of signal (rise above minimum elevation)
+	LOS              time.Time     // loss of signal (set below minimum elevation)
+	MaxElevation     float64       // degrees
+	MaxElevationTime time.Time     // time of maximum elevation (culmination)
+	AOSAzimuth       float64       // azimuth at rise in degrees
+	LOSAzimuth       float64       // azimuth at set in degrees
+	Duration         time.Duration // LOS - AOS
+
+	MinRange          float64   // closest slant range to the observer in km
+	MinRangeTime      time.Time // time of closest approach
+	SubpointLatitude  float64   // sub-satellite latitude at MinRangeTime in degrees
+	SubpointLongitude float64   // sub-satellite longitude at MinRangeTime in degrees
+	SubpointAltitude  float64   // satellite altitude at MinRangeTime in km
+}
+
+// PredictPasses predicts passes of a satellite over an observer within a time range.
+// AOS and LOS are refined to one second between the samples either side of the
+// minElevation crossing, as is the culmination. Passes in progress at
+// startTime or endTime are cut off there.
+// Geostationary satellites whose visibility cannot change over the window are
+// resolved without scanning at stepSize; one that stays up is sampled hourly
+// only to locate its culmination and closest approach.
+func PredictPasses(tle *TLE, observer *ObserverPosition, startTime, endTime time.Time, stepSize time.Duration, minElevation float64) ([]Pass, error) {
+	if passes, ok, err := staticGEOPasses(tle, observer, startTime, endTime, minElevation); ok || err != nil {
+		return passes, err
+	}
+	return samplePasses(tle, observer, startTime, endTime, stepSize, minElevation)
+}
+
+// samplePasses finds passes by sampling elevation at stepSize and refining
+// the boundaries, culmination, and closest approach of each.
+func samplePasses(tle *TLE, observer *ObserverPosition, startTime, endTime time.Time, stepSize time.Duration, minElevation float64) ([]Pass, error) {
+	rawPasses, err := FindPasses(tle, observer, startTime, endTime, stepSize, minElevation)
+	if err != nil {
+		return nil, err
+	}
+
+	passes := make([]Pass, 0, len(rawPasses))
+	for _, obs := range rawPasses {
+		pass := newPass(obs)
+		if err := refinePassBoundaries(tle, observer, &pass, startTime, endTime, stepSize, minElevation); err != nil {
+			return nil, err
+		}
+		if err := refinePass(tle, observer, &pass, stepSize); err != nil {
+			return nil, err
+		}
+		passes = append(passes, pass)
+	}
+
+	return passes, nil
+}
+
+// refinePassBoundaries moves AOS and LOS from the first and last samples above
+// minElevation to the crossings, found by bisection against the neighboring
+// samples below it. Boundaries at the edge of [startTime, endTime] are kept.
+func refinePassBoundaries(tle *TLE, observer *ObserverPosition, pass *Pass, startTime, endTime time.Time, stepSize time.Duration, minElevation float64) error {
+	if pass.AOS.After(startTime) {
+		lo := pass.AOS.Add(-stepSize)
+		if lo.Before(startTime) {
+			lo = startTime
+		}
+		aos, err := refineCrossing(tle, observer, lo, pass.AOS, minElevation, true)
+		if err != nil {
+			return err
+		}
+		pass.AOS, pass.AOSAzimuth = aos.Time, aos.Azimuth
+	}
+
+	if pass.LOS.Before(endTime) {
+		hi := pass.LOS.Add(stepSize)
+		if hi.After(endTime) {
+			hi = endTime
+		}
+		los, err := refineCrossing(tle, observer, pass.LOS, hi, minElevation, false)
+		if err != nil {
+			return err
+		}
+		pass.LOS, pass.LOSAzimuth = los.Time, los.Azimuth
+	}
+
+	pass.Duration = pass.LOS.Sub(pass.AOS)
+	return nil
+}
+
+// refineCrossing bisects [lo, hi] to one second for the time the elevation
+// crosses minElevation, rising or setting. Returns the observation at the
+// crossing time on the side above minElevation.
+func refineCrossing(tle *TLE, observer *ObserverPosition, lo, hi time.Time, minElevation float64, rising bool) (*ObservationAngles, error) {
+	// above is the observation at whichever end is above minElevation
+	var above *ObservationAngles
+	var err error
+	if rising {
+		above, err = observe(tle, observer, hi)
+	} else {
+		above, err = observe(tle, observer, lo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		obs, err := observe(tle, observer, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if (obs.Elevation >= minElevation) == rising {
+			hi = mid
+		} else {
+			lo = mid
+		}
+		if obs.Elevation >= minElevation {
+			above = obs
+		}
+	}
+
+	return above, nil
+}
+
+// refinePass refines the culmination and closest approach of a pass built from
+// samples stepSize apart, and fills in the sub-satellite point at closest approach.
+func refinePass(tle *TLE, observer *ObserverPosition, pass *Pass, stepSize time.Duration) error {
+	lo := clampTime(pass.MaxElevationTime.Add(-stepSize), pass.AOS, pass.LOS)
+	hi := clampTime(pass.MaxElevationTime.Add(stepSize), pass.AOS, pass.LOS)
+	if peak, err := refineCulmination(tle, observer, lo, hi); err == nil && peak.Elevation > pass.MaxElevation {
+		pass.MaxElevation = peak.Elevation
+		pass.MaxElevationTime = peak.Time
+	}
+
+	lo = clampTime(pass.MinRangeTime.Add(-stepSize), pass.AOS, pass.LOS)
+	hi = clampTime(pass.MinRangeTime.Add(stepSize), pass.AOS, pass.LOS)
+	t, r, err := goldenSectionMin(lo, hi, func(t time.Time) (float64, error) {
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return 0, err
+		}
+		return obs.Range, nil
+	})
+	if err == nil && r < pass.MinRange {
+		pass.MinRange = r
+		pass.MinRangeTime = t
+	}
+
+	return setPassSubpoint(tle, pass)
+}
+
+// setPassSubpoint fills in the sub-satellite point and altitude at the pass's MinRangeTime.
+func setPassSubpoint(tle *TLE, pass *Pass) error {
+	pos, err := PropagateSatellite(tle, pass.MinRangeTime)
+	if err != nil {
+		return fmt.Errorf("propagation failed at %v: %w", pass.MinRangeTime, err)
+	}
+	pass.SubpointLatitude, pass.SubpointLongitude, pass.SubpointAltitude = ecefToGeodetic(pos.X, pos.Y, pos.Z)
+	return nil
+}
+
+// geoMotionBound returns a conservative bound in degrees on how far a
+// near-geostationary satellite's apparent position can move over window,
+// or false if the elements are not near-geostationary.
+func geoMotionBound(el *tleElements, window time.Duration) (float64, bool) {
+	const siderealRevsPerDay = 1.00273791
+	if math.Abs(el.MeanMotion-siderealRevsPerDay) > 0.01 || el.Eccentricity > 0.01 {
+		return 0, false
+	}
+
+	// Daily north-south oscillation from inclination, east-west oscillation
+	// from eccentricity, and secular longitude drift from the mean motion offset
+	drift := math.Abs(el.MeanMotion-siderealRevsPerDay) * 360.0 * window.Hours() / 24.0
+	bound := el.Inclination + 2*el.Eccentricity*180.0/math.Pi + drift
+
+	// Geocentric motion appears up to ~1.2x larger from the ground; pad for model error
+	return bound*1.2 + 0.5, true
+}
+
+// staticGEOPasses resolves passes for a near-geostationary satellite whose
+// elevation stays clearly above or below minElevation for the whole window.
+// Returns ok=false when the satellite is not geostationary or visibility is
+// marginal, in which case the caller should sample normally.
+func staticGEOPasses(tle *TLE, observer *ObserverPosition, startTime, endTime time.Time, minElevation float64) ([]Pass, bool, error) {
+	if tle == nil || endTime.Before(startTime) {
+		return nil, false, nil
+	}
+	el, err := tle.elements()
+	if err != nil {
+		return nil, false, nil
+	}
+	bound, ok := geoMotionBound(el, endTime.Sub(startTime))
+	if !ok {
+		return nil, false, nil
+	}
+
+	first, err := observe(tle, observer, startTime)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case first.Elevation < minElevation-bound:
+		// Never rises over the window
+		return []Pass{}, true, nil
+
+	case first.Elevation > minElevation+bound:
+		// Visible for the whole window; the culmination and closest approach
+		// follow the daily cycle, so hourly samples bracket them for refinement
+		pass := Pass{
+			AOS:              startTime,
+			LOS:              endTime,
+			MaxElevation:     first.Elevation,
+			MaxElevationTime: first.Time,
+			AOSAzimuth:       first.Azimuth,
+			LOSAzimuth:       first.Azimuth,
+			Duration:         endTime.Sub(startTime),
+			MinRange:         first.Range,
+			MinRangeTime:     first.Time,
+		}
+		for t := startTime; t.Before(endTime); {
+			t = clampTime(t.Add(geoSampleStep), startTime, endTime)
+			obs, err := observe(tle, observer, t)
+			if err != nil {
+				return nil, false, err
+			}
+			if obs.Elevation > pass.MaxElevation {
+				pass.MaxElevation, pass.MaxElevationTime = obs.Elevation, obs.Time
+			}
+			if obs.Range < pass.MinRange {
+				pass.MinRange, pass.MinRangeTime = obs.Range, obs.Time
+			}
+			pass.LOSAzimuth = obs.Azimuth
+		}
+		if err := refinePass(tle, observer, &pass, geoSampleStep); err != nil {
+			return nil, false, err
+		}
+		return []Pass{pass}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// clampTime limits t to the interval [lo, hi].
+func clampTime(t, lo, hi time.Time) time.Time {
+	if t.Before(lo) {
+		return lo
+	}
+	if t.After(hi) {
+		return hi
+	}
+	return t
+}
+
+// observe propagates a satellite and returns its observation angles at t.
+func observe(tle *TLE, observer *ObserverPosition, t time.Time) (*ObservationAngles, error) {
+	pos, err := PropagateSatellite(tle, t)
+	if err != nil {
+		return nil, err
+	}
+	return CalculateObservationAngles(pos, observer), nil
+}
+
+// refineCulmination finds the time of maximum elevation within [lo, hi]
+// to a resolution of one second. Assumes elevation is unimodal over the interval.
+func refineCulmination(tle *TLE, observer *ObserverPosition, lo, hi time.Time) (*ObservationAngles, error) {
+	var last *ObservationAngles
+	t, _, err := goldenSectionMin(lo, hi, func(t time.Time) (float64, error) {
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return 0, err
+		}
+		last = obs
+		return -obs.Elevation, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if last == nil || !last.Time.Equal(t) {
+		return observe(tle, observer, t)
+	}
+	return last, nil
+}
+
+// goldenSectionMin minimizes f over [lo, hi] using a golden-section search,
+// to a resolution of one second. Returns the time and value of the minimum.
+// Assumes f is unimodal over the interval.
+func goldenSectionMin(lo, hi time.Time, f func(time.Time) (float64, error)) (time.Time, float64, error) {
+	invPhi := (math.Sqrt(5) - 1) / 2
+
+	a, b := lo, hi
+	c := b.Add(-time.Duration(float64(b.Sub(a)) * invPhi))
+	d := a.Add(time.Duration(float64(b.Sub(a)) * invPhi))
+
+	fc, err := f(c)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	fd, err := f(d)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	for b.Sub(a) > time.Second {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b.Add(-time.Duration(float64(b.Sub(a)) * invPhi))
+			if fc, err = f(c); err != nil {
+				return time.Time{}, 0, err
+			}
+		} else {
+			a, c, fc = c, d, fd
+			d = a.Add(time.Duration(float64(b.Sub(a)) * invPhi))
+			if fd, err = f(d); err != nil {
+				return time.Time{}, 0, err
+			}
+		}
+	}
+
+	if fc < fd {
+		return c, fc, nil
+	}
+	return d, fd, nil
+}
+
+// NextPass returns the pass in progress at after, or the next pass above
+// minElevation within 48 hours. Returns ErrNoPassFound if there is none.
+func NextPass(tle *TLE, observer *ObserverPosition, after time.Time, minElevation float64) (*Pass, error) {
+	passes, err := PredictPasses(tle, observer, after, after.Add(nextPassWindow), nextPassStep, minElevation)
+	if err != nil {
+		return nil, err
+	}
+	if len(passes) == 0 {
+		return nil, fmt.Errorf("%w within %v of %v", ErrNoPassFound, nextPassWindow, after)
+	}
+
+	return &passes[0], nil
+}
+
+// DailyContactBudget returns the total time a satellite spends above minElevation
+// and the number of passes for an observer over the calendar day containing date,
+// in date's location. Passes that span midnight are clipped to the day.
+func DailyContactBudget(tle *TLE, observer *ObserverPosition, date time.Time, minElevation float64, step time.Duration) (time.Duration, int, error) {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	passes, err := PredictPasses(tle, observer, dayStart, dayEnd, step, minElevation)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total time.Duration
+	for _, pass := range passes {
+		total += pass.Duration
+	}
+
+	return total, len(passes), nil
+}
+
+// ElevationBandDurations returns how long the satellite spends at or above
+// each multiple of bandDeg (0, bandDeg, 2*bandDeg, ... up to 90°) during the
+// pass, keyed by threshold in degrees. Only the time between AOS and LOS is
+// counted, so thresholds below the pass's minimum elevation all get the full
+// pass duration. The pass is sampled every second and threshold crossings
+// between samples are interpolated.
+func ElevationBandDurations(pass *Pass, tle *TLE, observer *ObserverPosition, bandDeg float64) (map[float64]time.Duration, error) {
+	if bandDeg <= 0 {
+		return nil, fmt.Errorf("band must be positive: %v", bandDeg)
+	}
+	const step = time.Second
+
+	var thresholds []float64
+	for k := 0; float64(k)*bandDeg <= 90; k++ {
+		thresholds = append(thresholds, float64(k)*bandDeg)
+	}
+	above := make([]float64, len(thresholds)) // seconds
+
+	var prev *ObservationAngles
+	for t := pass.AOS; ; t = t.Add(step) {
+		if t.After(pass.LOS) {
+			t = pass.LOS
+		}
+
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		if prev != nil {
+			dt := obs.Time.Sub(prev.Time).Seconds()
+			for i, threshold := range thresholds {
+				above[i] += dt * fractionAbove(prev.Elevation, obs.Elevation, threshold)
+			}
+		}
+		prev = obs
+
+		if !t.Before(pass.LOS) {
+			break
+		}
+	}
+
+	durations := make(map[float64]time.Duration, len(thresholds))
+	for i, threshold := range thresholds {
+		durations[threshold] = time.Duration(above[i] * float64(time.Second)).Round(time.Millisecond)
+	}
+	return durations, nil
+}
+
+// fractionAbove returns the fraction of a linear segment from e0 to e1 that is
+// at or above threshold.
+func fractionAbove(e0, e1, threshold float64) float64 {
+	switch {
+	case e0 >= threshold && e1 >= threshold:
+		return 1
+	case e0 < threshold && e1 < threshold:
+		return 0
+	case e0 >= threshold:
+		return (e0 - threshold) / (e0 - e1)
+	default:
+		return (e1 - threshold) / (e1 - e0)
+	}
+}
+
+// SlantRangeExtremes returns the closest and farthest the satellite gets to
+// the observer between start and end, whether or not it is above the horizon,
+// with the times they occur. The window is sampled every step and each extreme
+// is then refined to one second between its neighbouring samples.
+func SlantRangeExtremes(tle *TLE, observer *ObserverPosition, start, end time.Time, step time.Duration) (minRange, maxRange float64, minTime, maxTime time.Time, err error) {
+	prop, err := NewPropagator(tle)
+	if err != nil {
+		return 0, 0, time.Time{}, time.Time{}, err
+	}
+
+	slant := func(t time.Time) (float64, error) {
+		pos, err := prop.At(t)
+		if err != nil {
+			return 0, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		return CalculateObservationAngles(pos, observer).Range, nil
+	}
+
+	positions, err := prop.Range(start, end, step)
+	if err != nil {
+		return 0, 0, time.Time{}, time.Time{}, err
+	}
+	minIdx, maxIdx := 0, 0
+	ranges := make([]float64, len(positions))
+	for i, pos := range positions {
+		ranges[i] = CalculateObservationAngles(pos, observer).Range
+		if ranges[i] < ranges[minIdx] {
+			minIdx = i
+		}
+		if ranges[i] > ranges[maxIdx] {
+			maxIdx = i
+		}
+	}
+
+	// Refine each extreme between the samples either side of it
+	bracket := func(i int) (time.Time, time.Time) {
+		lo, hi := positions[i].Time, positions[i].Time
+		if i > 0 {
+			lo = positions[i-1].Time
+		}
+		if i < len(positions)-1 {
+			hi = positions[i+1].Time
+		}
+		return lo, hi
+	}
+
+	minTime, minRange = positions[minIdx].Time, ranges[minIdx]
+	if lo, hi := bracket(minIdx); hi.Sub(lo) > time.Second {
+		t, r, err := goldenSectionMin(lo, hi, slant)
+		if err != nil {
+			return 0, 0, time.Time{}, time.Time{}, err
+		}
+		if r < minRange {
+			minTime, minRange = t, r
+		}
+	}
+
+	maxTime, maxRange = positions[maxIdx].Time, ranges[maxIdx]
+	if lo, hi := bracket(maxIdx); hi.Sub(lo) > time.Second {
+		t, r, err := goldenSectionMin(lo, hi, func(t time.Time) (float64, error) {
+			r, err := slant(t)
+			return -r, err
+		})
+		if err != nil {
+			return 0, 0, time.Time{}, time.Time{}, err
+		}
+		if -r > maxRange {
+			maxTime, maxRange = t, -r
+		}
+	}
+
+	return minRange, maxRange, minTime, maxTime, nil
+}
+
+// NextPassPeakElevation returns the peak elevation in degrees of the pass in
+// progress at after, or of the next pass above the horizon within 48 hours.
+// The culmination is located by coarse stepping and golden-section refinement
+// rather than sampling the whole pass.
+func NextPassPeakElevation(tle *TLE, observer *ObserverPosition, after time.Time) (float64, error) {
+	end := after.Add(nextPassWindow)
+
+	var best *ObservationAngles
+	for t := after; !t.After(end); t = t.Add(nextPassStep) {
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return 0, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		if obs.Elevation < 0 {
+			if best != nil {
+				break // pass has ended
+			}
+			continue
+		}
+
+		if best == nil || obs.Elevation > best.Elevation {
+			best = obs
+		} else if obs.Elevation < best.Elevation {
+			break // past culmination; the bracket is complete
+		}
+	}
+
+	if best == nil {
+		return 0, fmt.Errorf("no pass found within %v of %v", nextPassWindow, after)
+	}
+
+	lo := best.Time.Add(-nextPassStep)
+	if lo.Before(after) {
+		lo = after
+	}
+	peak, err := refineCulmination(tle, observer, lo, best.Time.Add(nextPassStep))
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Max(peak.Elevation, best.Elevation), nil
+}
+
+// MinZenithAngle returns the closest the satellite gets to the observer's
+// zenith during the pass, in degrees (90 - MaxElevation).
+func (p *Pass) MinZenithAngle() float64 {
+	return 90.0 - p.MaxElevation
+}
+
+// IsZenithPass reports whether the pass culminates above 80° elevation.
+func (p *Pass) IsZenithPass() bool {
+	return p.MaxElevation > zenithPassElevation
+}
+
+// newPass builds a Pass from the observations of a single pass.
+func newPass(obs []*ObservationAngles) Pass {
+	first, last := obs[0], obs[len(obs)-1]
+
+	peak, closest := first, first
+	for _, o := range obs {
+		if o.Elevation > peak.Elevation {
+			peak = o
+		}
+		if o.Range < closest.Range {
+			closest = o
+		}
+	}
+
+	return Pass{
+		AOS:              first.Time,
+		LOS:              last.Time,
+		MaxElevation:     peak.Elevation,
+		MaxElevationTime: peak.Time,
+		AOSAzimuth:       first.Azimuth,
+		LOSAzimuth:       last.Azimuth,
+		Duration:         last.Time.Sub(first.Time),
+		MinRange:         closest.Range,
+		MinRangeTime:     closest.Time,
+	}
+}
+
+// PassesOverlap reports whether two passes are concurrent, and if so the
+// window during which both satellites are above the horizon.
+// Passes that only touch (one sets exactly as the other rises) do not overlap.
+func PassesOverlap(a, b *Pass) (bool, time.Time, time.Time) {
+	start := a.AOS
+	if b.AOS.After(start) {
+		start = b.AOS
+	}
+	end := a.LOS
+	if b.LOS.Before(end) {
+		end = b.LOS
+	}
+
+	if !start.Before(end) {
+		return false, time.Time{}, time.Time{}
+	}
+	return true, start, end
+}
+
+// OrbitVisibleFraction returns the fraction (0-1) of one orbital period, starting
+// at the TLE epoch, during which the satellite is above minElevation from the observer.
+// The period is derived from the TLE mean motion and sampled at step.
+func OrbitVisibleFraction(tle *TLE, observer *ObserverPosition, minElevation float64, step time.Duration) (float64, error) {
+	if tle == nil {
+		return 0, fmt.Errorf("TLE is nil")
+	}
+	if step <= 0 {
+		return 0, fmt.Errorf("step must be positive")
+	}
+
+	el, err := tle.elements()
+	if err != nil {
+		return 0, err
+	}
+	if el.MeanMotion <= 0 {
+		return 0, fmt.Errorf("invalid mean motion: %f", el.MeanMotion)
+	}
+	start, err := tle.GetEpoch()
+	if err != nil {
+		return 0, err
+	}
+
+	period := time.Duration(1440.0 / el.MeanMotion * float64(time.Minute))
+	end := start.Add(period)
+
+	samples, visible := 0, 0
+	for t := start; t.Before(end); t = t.Add(step) {
+		obs, err := observe(tle, observer, t)
+		if err != nil {
+			return 0, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+		samples++
+		if IsVisible(obs, minElevation) {
+			visible++
+		}
+	}
+
+	return float64(visible) / float64(samples), nil
+}
@@ -0,0 +1,419 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPassesOverlap(t *testing.T) {
+	at := func(minutes int) time.Time { return testEpoch.Add(time.Duration(minutes) * time.Minute) }
+	pass := func(aos, los int) *Pass { return &Pass{AOS: at(aos), LOS: at(los)} }
+
+	tests := []struct {
+		name       string
+		a, b       *Pass
+		overlap    bool
+		start, end time.Time
+	}{
+		{"overlapping", pass(0, 10), pass(5, 15), true, at(5), at(10)},
+		{"overlapping reversed", pass(5, 15), pass(0, 10), true, at(5), at(10)},
+		{"contained", pass(0, 20), pass(5, 8), true, at(5), at(8)},
+		{"identical", pass(0, 10), pass(0, 10), true, at(0), at(10)},
+		{"adjacent", pass(0, 10), pass(10, 20), false, time.Time{}, time.Time{}},
+		{"disjoint", pass(0, 10), pass(30, 40), false, time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		overlap, start, end := PassesOverlap(tt.a, tt.b)
+		if overlap != tt.overlap || !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%s: PassesOverlap = %v, %v, %v; want %v, %v, %v",
+				tt.name, overlap, start, end, tt.overlap, tt.start, tt.end)
+		}
+	}
+}
+
+func TestNextPassPeakElevationMatchesPredictPasses(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1.6}
+
+	passes, err := PredictPasses(tle, observer, testEpoch, testEpoch.Add(24*time.Hour), 30*time.Second, 0)
+	if err != nil {
+		t.Fatalf("PredictPasses: %v", err)
+	}
+	if len(passes) < 3 {
+		t.Fatalf("got %d passes in a day, want at least 3", len(passes))
+	}
+
+	// Search from just after each of the first passes' LOS for the one after it
+	for i := 0; i < 3; i++ {
+		after := testEpoch
+		if i > 0 {
+			after = passes[i-1].LOS.Add(time.Minute)
+		}
+		peak, err := NextPassPeakElevation(tle, observer, after)
+		if err != nil {
+			t.Fatalf("NextPassPeakElevation after %v: %v", after, err)
+		}
+		assertNear(t, "peak elevation", peak, passes[i].MaxElevation, 0.01)
+	}
+}
+
+func TestOrbitVisibleFraction(t *testing.T) {
+	// A sun-synchronous LEO starting at its ascending node
+	tle := makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5)
+	pos, err := PropagateSatellite(tle, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, nodeLon, alt := SubSatellitePoint(pos)
+
+	// From a pole every orbit crosses the horizon circle, whose half-angle
+	// at the center of the Earth is acos(R / (R + h))
+	horizon := math.Acos(meanEarthRadiusKm/(meanEarthRadiusKm+alt)) * 180 / math.Pi
+	for _, lat := range []float64{89.9, -89.9} {
+		polar, err := OrbitVisibleFraction(tle, &ObserverPosition{Latitude: lat}, 0, 10*time.Second)
+		if err != nil {
+			t.Fatalf("OrbitVisibleFraction: %v", err)
+		}
+		assertNear(t, "polar visible fraction", polar, 2*horizon/360, 0.01)
+	}
+
+	// An equatorial observer a quarter turn from the node never sees it
+	equatorial, err := OrbitVisibleFraction(tle, &ObserverPosition{Longitude: nodeLon + 90}, 0, 10*time.Second)
+	if err != nil {
+		t.Fatalf("OrbitVisibleFraction: %v", err)
+	}
+	if equatorial != 0 {
+		t.Errorf("equatorial observer 90° from the node sees %.3f of the orbit, want 0", equatorial)
+	}
+
+	if _, err := OrbitVisibleFraction(tle, &ObserverPosition{}, 0, 0); err == nil {
+		t.Error("OrbitVisibleFraction accepted a zero step")
+	}
+}
+
+func TestStaticGEOPassesMatchSampledScan(t *testing.T) {
+	// An inclined GEO nods a degree north and south each day, so its peak
+	// elevation differs from its elevation at either end of the window
+	tle := makeTLE(40001, testEpoch, 1.0, 90, 0.0002, 0, 0, 1.00273791)
+	pos, err := PropagateSatellite(tle, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, subLon, _ := SubSatellitePoint(pos)
+	start, end := testEpoch, testEpoch.Add(36*time.Hour)
+
+	tests := []struct {
+		name     string
+		observer *ObserverPosition
+		visible  bool
+	}{
+		{"visible", &ObserverPosition{Latitude: 35, Longitude: subLon + 10}, true},
+		{"below horizon", &ObserverPosition{Latitude: 35, Longitude: subLon + 180}, false},
+	}
+	for _, tt := range tests {
+		fast, ok, err := staticGEOPasses(tle, tt.observer, start, end, 10)
+		if err != nil || !ok {
+			t.Fatalf("%s: fast path not taken (ok %v, err %v)", tt.name, ok, err)
+		}
+		sampled, err := samplePasses(tle, tt.observer, start, end, time.Minute, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(fast) != len(sampled) || (len(fast) == 1) != tt.visible {
+			t.Fatalf("%s: fast path found %d passes, sampled scan %d", tt.name, len(fast), len(sampled))
+		}
+		if !tt.visible {
+			continue
+		}
+
+		f, s := fast[0], sampled[0]
+		if !f.AOS.Equal(s.AOS) || !f.LOS.Equal(s.LOS) {
+			t.Errorf("%s: fast path spans %v-%v, sampled scan %v-%v", tt.name, f.AOS, f.LOS, s.AOS, s.LOS)
+		}
+		assertNear(t, tt.name+" max elevation", f.MaxElevation, s.MaxElevation, 1e-3)
+		assertNear(t, tt.name+" min range", f.MinRange, s.MinRange, 0.01)
+		assertNear(t, tt.name+" LOS azimuth", f.LOSAzimuth, s.LOSAzimuth, 1e-6)
+		if d := f.MaxElevationTime.Sub(s.MaxElevationTime).Abs(); d > 5*time.Minute {
+			t.Errorf("%s: culmination at %v, sampled scan %v", tt.name, f.MaxElevationTime, s.MaxElevationTime)
+		}
+
+		first, _ := observe(tle, tt.observer, start)
+		last, _ := observe(tle, tt.observer, end)
+		if f.MaxElevation <= math.Max(first.Elevation, last.Elevation)+0.1 {
+			t.Errorf("%s: peak %.3f° is not above the window ends (%.3f°, %.3f°)",
+				tt.name, f.MaxElevation, first.Elevation, last.Elevation)
+		}
+	}
+}
+
+func TestPassSubpointAtMinRange(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1.6}
+	passes, err := PredictPasses(tle, observer, testEpoch, testEpoch.Add(24*time.Hour), 30*time.Second, 0)
+	if err != nil || len(passes) == 0 {
+		t.Fatalf("PredictPasses = %d passes, %v", len(passes), err)
+	}
+
+	for _, pass := range passes {
+		if pass.MinRangeTime.Before(pass.AOS) || pass.MinRangeTime.After(pass.LOS) {
+			t.Errorf("closest approach at %v is outside the pass %v-%v", pass.MinRangeTime, pass.AOS, pass.LOS)
+		}
+
+		pos, err := PropagateSatellite(tle, pass.MinRangeTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lat, lon, alt := SubSatellitePoint(pos)
+		assertNear(t, "subpoint latitude", pass.SubpointLatitude, lat, 1e-9)
+		assertNear(t, "subpoint longitude", pass.SubpointLongitude, lon, 1e-9)
+		assertNear(t, "subpoint altitude", pass.SubpointAltitude, alt, 1e-9)
+		if alt < 300 || alt > 500 {
+			t.Errorf("ISS altitude at closest approach %.0f km, want LEO", alt)
+		}
+
+		// The satellite is closer to the observer at MinRangeTime than a
+		// minute either side, and so is its subpoint
+		obs := CalculateObservationAngles(pos, observer)
+		assertNear(t, "range at closest approach", obs.Range, pass.MinRange, 1e-6)
+		ground := greatCircleDistanceKm(observer.Latitude, observer.Longitude, lat, lon)
+		for _, offset := range []time.Duration{-time.Minute, time.Minute} {
+			at := pass.MinRangeTime.Add(offset)
+			if at.Before(pass.AOS) || at.After(pass.LOS) {
+				continue
+			}
+			other, _ := observe(tle, observer, at)
+			if other.Range < pass.MinRange {
+				t.Errorf("range %.1f km at %v is below MinRange %.1f km", other.Range, at, pass.MinRange)
+			}
+			otherPos, _ := PropagateSatellite(tle, at)
+			otherLat, otherLon, _ := SubSatellitePoint(otherPos)
+			if d := greatCircleDistanceKm(observer.Latitude, observer.Longitude, otherLat, otherLon); d < ground {
+				t.Errorf("subpoint %v from closest approach is nearer the observer (%.0f km vs %.0f km)", offset, d, ground)
+			}
+		}
+	}
+}
+
+func TestDailyContactBudgetPolarOrbit(t *testing.T) {
+	// A sun-synchronous LEO crosses the polar cap on nearly every one of its
+	// 14.5 daily revolutions, so Svalbard sees it far more than the equator
+	tle := makeTLE(30000, testEpoch, 98, 0, 0.001, 0, 0, 14.5)
+	svalbard := &ObserverPosition{Latitude: 78.2, Longitude: 15.6}
+	equator := &ObserverPosition{}
+
+	total, count, err := DailyContactBudget(tle, svalbard, testEpoch, 5, 30*time.Second)
+	if err != nil {
+		t.Fatalf("DailyContactBudget: %v", err)
+	}
+	if count < 12 || count > 15 {
+		t.Errorf("Svalbard gets %d passes, want 12-15", count)
+	}
+	if total < 2*time.Hour || total > 3*time.Hour {
+		t.Errorf("Svalbard contact time %v, want 2-3h", total)
+	}
+
+	// The budget is the day's passes summed
+	passes, err := PredictPasses(tle, svalbard, testEpoch, testEpoch.AddDate(0, 0, 1), 30*time.Second, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum time.Duration
+	for _, pass := range passes {
+		if pass.Duration < 5*time.Minute || pass.Duration > 15*time.Minute {
+			t.Errorf("pass at %v lasts %v, want 5-15 min", pass.AOS, pass.Duration)
+		}
+		sum += pass.Duration
+	}
+	if sum != total || len(passes) != count {
+		t.Errorf("budget %v over %d passes, PredictPasses sums to %v over %d", total, count, sum, len(passes))
+	}
+
+	eqTotal, eqCount, err := DailyContactBudget(tle, equator, testEpoch, 5, 30*time.Second)
+	if err != nil {
+		t.Fatalf("DailyContactBudget: %v", err)
+	}
+	if eqCount >= count/2 || eqTotal >= total/2 {
+		t.Errorf("equator gets %d passes and %v, want well under Svalbard's %d and %v", eqCount, eqTotal, count, total)
+	}
+}
+
+func TestZenithPass(t *testing.T) {
+	tle := issTLE()
+	at := testEpoch.Add(3 * time.Hour)
+	pos, err := PropagateSatellite(tle, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, _ := SubSatellitePoint(pos)
+
+	tests := []struct {
+		name     string
+		observer *ObserverPosition
+		zenith   bool
+	}{
+		// Directly beneath the ground track, and ~1100 km off to the side
+		{"overhead", &ObserverPosition{Latitude: lat, Longitude: lon}, true},
+		{"low", &ObserverPosition{Latitude: lat + 10, Longitude: lon}, false},
+	}
+	for _, tt := range tests {
+		passes, err := PredictPasses(tle, tt.observer, at.Add(-10*time.Minute), at.Add(10*time.Minute), 10*time.Second, 0)
+		if err != nil || len(passes) != 1 {
+			t.Fatalf("%s: PredictPasses = %d passes, %v; want 1", tt.name, len(passes), err)
+		}
+		pass := &passes[0]
+		if pass.IsZenithPass() != tt.zenith {
+			t.Errorf("%s: IsZenithPass = %v at max elevation %.1f°", tt.name, pass.IsZenithPass(), pass.MaxElevation)
+		}
+		assertNear(t, tt.name+" zenith angle", pass.MinZenithAngle(), 90-pass.MaxElevation, 1e-12)
+		if tt.zenith && pass.MinZenithAngle() > 1 {
+			t.Errorf("overhead pass comes within %.2f° of zenith, want under 1°", pass.MinZenithAngle())
+		}
+	}
+
+	// The threshold itself is not a zenith pass
+	for _, tt := range []struct {
+		maxElevation float64
+		zenith       bool
+	}{{80, false}, {80.01, true}, {90, true}, {45, false}} {
+		pass := &Pass{MaxElevation: tt.maxElevation}
+		if pass.IsZenithPass() != tt.zenith {
+			t.Errorf("IsZenithPass at %v° = %v, want %v", tt.maxElevation, pass.IsZenithPass(), tt.zenith)
+		}
+	}
+}
+
+func TestPredictPassesLEOMidLatitude(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	const minElevation = 10.0
+	start, end := testEpoch, testEpoch.Add(24*time.Hour)
+
+	passes, err := PredictPasses(tle, observer, start, end, 30*time.Second, minElevation)
+	if err != nil {
+		t.Fatalf("PredictPasses: %v", err)
+	}
+	// A 51.6° orbit passes over 40° N several times a day
+	if len(passes) < 3 || len(passes) > 8 {
+		t.Fatalf("got %d passes in a day, want 3-8", len(passes))
+	}
+
+	for i, pass := range passes {
+		if pass.Duration != pass.LOS.Sub(pass.AOS) || pass.Duration < time.Minute || pass.Duration > 12*time.Minute {
+			t.Errorf("pass %d: %v-%v lasts %v", i, pass.AOS, pass.LOS, pass.Duration)
+		}
+		if i > 0 && !pass.AOS.After(passes[i-1].LOS) {
+			t.Errorf("pass %d starts at %v, before the previous LOS %v", i, pass.AOS, passes[i-1].LOS)
+		}
+		if pass.MaxElevationTime.Before(pass.AOS) || pass.MaxElevationTime.After(pass.LOS) || pass.MaxElevation < minElevation {
+			t.Errorf("pass %d: culminates at %.1f° at %v outside %v-%v", i, pass.MaxElevation, pass.MaxElevationTime, pass.AOS, pass.LOS)
+		}
+
+		// AOS and LOS are refined to the minimum elevation crossing rather
+		// than snapped to the 30 s step
+		aos, _ := observe(tle, observer, pass.AOS)
+		los, _ := observe(tle, observer, pass.LOS)
+		assertNear(t, "elevation at AOS", aos.Elevation, minElevation, 0.1)
+		assertNear(t, "elevation at LOS", los.Elevation, minElevation, 0.1)
+		assertNear(t, "AOS azimuth", pass.AOSAzimuth, aos.Azimuth, 1e-6)
+		assertNear(t, "LOS azimuth", pass.LOSAzimuth, los.Azimuth, 1e-6)
+
+		// The satellite is below the minimum a little either side
+		before, _ := observe(tle, observer, pass.AOS.Add(-5*time.Second))
+		after, _ := observe(tle, observer, pass.LOS.Add(5*time.Second))
+		if before.Elevation >= minElevation || after.Elevation >= minElevation {
+			t.Errorf("pass %d: %.2f° 5 s before AOS and %.2f° 5 s after LOS", i, before.Elevation, after.Elevation)
+		}
+
+		peak, _ := observe(tle, observer, pass.MaxElevationTime)
+		assertNear(t, "elevation at culmination", peak.Elevation, pass.MaxElevation, 1e-6)
+	}
+}
+
+func TestElevationBandDurationsMonotonic(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	passes, err := PredictPasses(tle, observer, testEpoch, testEpoch.Add(24*time.Hour), 30*time.Second, 0)
+	if err != nil || len(passes) == 0 {
+		t.Fatalf("PredictPasses = %d passes, %v", len(passes), err)
+	}
+
+	for _, pass := range passes {
+		durations, err := ElevationBandDurations(&pass, tle, observer, 10)
+		if err != nil {
+			t.Fatalf("ElevationBandDurations: %v", err)
+		}
+		if len(durations) != 10 {
+			t.Fatalf("pass at %v: %d bands, want 0° to 90° in 10° steps", pass.AOS, len(durations))
+		}
+		if d := durations[0] - pass.Duration; d.Abs() > time.Second {
+			t.Errorf("pass at %v: %v above 0°, want the pass duration %v", pass.AOS, durations[0], pass.Duration)
+		}
+		for band := 10.0; band <= 90; band += 10 {
+			if durations[band] > durations[band-10] {
+				t.Errorf("pass at %v: %v above %v° but %v above %v°", pass.AOS, durations[band], band, durations[band-10], band-10)
+			}
+			if band > pass.MaxElevation+0.5 && durations[band] != 0 {
+				t.Errorf("pass at %v peaking at %.1f°: %v above %v°", pass.AOS, pass.MaxElevation, durations[band], band)
+			}
+			if band < pass.MaxElevation-0.5 && durations[band] == 0 {
+				t.Errorf("pass at %v peaking at %.1f°: no time above %v°", pass.AOS, pass.MaxElevation, band)
+			}
+		}
+	}
+
+	if _, err := ElevationBandDurations(&passes[0], tle, observer, 0); err == nil {
+		t.Error("ElevationBandDurations accepted a zero band")
+	}
+}
+
+func TestSlantRangeExtremesHighPass(t *testing.T) {
+	// Stand beneath the ISS three hours in and scan most of an orbit around it
+	tle := issTLE()
+	at := testEpoch.Add(3 * time.Hour)
+	pos, err := PropagateSatellite(tle, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lon, altKm := SubSatellitePoint(pos)
+	observer := &ObserverPosition{Latitude: lat, Longitude: lon}
+	start, end := at.Add(-40*time.Minute), at.Add(40*time.Minute)
+
+	minRange, maxRange, minTime, maxTime, err := SlantRangeExtremes(tle, observer, start, end, time.Minute)
+	if err != nil {
+		t.Fatalf("SlantRangeExtremes: %v", err)
+	}
+
+	// Closest when overhead, at about the satellite's altitude
+	if d := minTime.Sub(at).Abs(); d > 5*time.Second {
+		t.Errorf("closest approach at %v, %v from the overhead pass", minTime, d)
+	}
+	assertNear(t, "minimum range", minRange, altKm, 1)
+	passes, err := PredictPasses(tle, observer, start, end, 10*time.Second, 0)
+	if err != nil || len(passes) != 1 {
+		t.Fatalf("PredictPasses = %d passes, %v; want 1", len(passes), err)
+	}
+	if passes[0].MaxElevation < 85 || minTime.Before(passes[0].AOS) || minTime.After(passes[0].LOS) {
+		t.Errorf("closest approach at %v is not within the %.1f° pass %v-%v",
+			minTime, passes[0].MaxElevation, passes[0].AOS, passes[0].LOS)
+	}
+
+	// Farthest nearly on the other side of the Earth, at the end of the
+	// window as the satellite is still receding
+	if maxRange < 12000 || !maxTime.Equal(end) {
+		t.Errorf("farthest %.0f km at %v, want over 12000 km at %v", maxRange, maxTime, end)
+	}
+
+	// No sample of the window is closer or farther than the refined extremes
+	for s := start; !s.After(end); s = s.Add(5 * time.Second) {
+		p, err := PropagateSatellite(tle, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := CalculateObservationAngles(p, observer).Range
+		if r < minRange-1e-6 || r > maxRange+1e-6 {
+			t.Fatalf("range %.3f km at %v is outside [%.3f, %.3f]", r, s, minRange, maxRange)
+		}
+	}
+}
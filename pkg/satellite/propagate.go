@@ -1,6 +1,7 @@
 package satellite
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -19,18 +20,53 @@ const (
 	RegimeUnknown OrbitRegime = "UNKNOWN" // Unknown or insufficient data
 )
 
+// Errors returned by propagation, for use with errors.Is.
+var (
+	ErrTLEParse  = errors.New("TLE could not be parsed")
+	ErrDecayed   = errors.New("satellite has decayed")
+	ErrNaNState  = errors.New("propagation produced a non-finite state")
+	ErrTLETooOld = errors.New("TLE epoch is too far from the propagation time")
+)
+
 // ObserverPosition represents the observer's location on Earth
 type ObserverPosition struct {
 	Latitude  float64 // degrees
-	Longitude float64 // degrees
+	Longitude float64 // degrees, east positive; 0-360 is accepted and normalized by Validate
 	Altitude  float64 // meters above sea level
 }
 
-// SatellitePosition represents a satellite's position at a specific time
+// IsZero reports whether the observer is the zero value, latitude, longitude,
+// and altitude all 0 - usually a location that was never set rather than
+// someone standing at null island in the Gulf of Guinea.
+func (o *ObserverPosition) IsZero() bool {
+	return o.Latitude == 0 && o.Longitude == 0 && o.Altitude == 0
+}
+
+// Validate checks that the observer's coordinates are in range and normalizes
+// the longitude to [-180, 180), so 300° east becomes -60°.
+func (o *ObserverPosition) Validate() error {
+	if math.IsNaN(o.Latitude) || o.Latitude < -90.0 || o.Latitude > 90.0 {
+		return fmt.Errorf("observer latitude must be between -90 and 90 degrees: %v", o.Latitude)
+	}
+	if math.IsNaN(o.Longitude) || o.Longitude < -180.0 || o.Longitude > 360.0 {
+		return fmt.Errorf("observer longitude must be between -180 and 360 degrees: %v", o.Longitude)
+	}
+	if math.IsNaN(o.Altitude) || math.IsInf(o.Altitude, 0) {
+		return fmt.Errorf("observer altitude must be finite: %v", o.Altitude)
+	}
+
+	o.Longitude = NormalizeLongitude(o.Longitude)
+	return nil
+}
+
+// SatellitePosition represents a satellite's position at a specific time.
+// Positions from PropagateSatellite are Earth-fixed (ECEF), which is the frame
+// every observer and ground-track function expects. PropagateSatelliteJ2000
+// returns the same type in the J2000 inertial frame.
 type SatellitePosition struct {
 	Time       time.Time
-	X, Y, Z    float64 // ECEF coordinates in km
-	Vx, Vy, Vz float64 // ECEF velocity in km/s
+	X, Y, Z    float64 // coordinates in km
+	Vx, Vy, Vz float64 // velocity in km/s, Earth-relative when ECEF
 }
 
 // ObservationAngles represents the satellite's position relative to the observer
@@ -43,53 +79,131 @@ type ObservationAngles struct {
 }
 
 // PropagateSatellite propagates a satellite's position using SGP4.
-// Returns the satellite's ECEF position at the given time.
+// Returns the satellite's ECEF position and Earth-relative velocity at the
+// given time; SGP4's TEME output is rotated with TEMEToECEF.
 func PropagateSatellite(tle *TLE, t time.Time) (*SatellitePosition, error) {
+	pos, err := propagateTEME(tle, t)
+	if err != nil {
+		return nil, err
+	}
+	return TEMEToECEF(pos), nil
+}
+
+// PropagateSatelliteChecked is PropagateSatellite with a staleness guard: it
+// returns ErrTLETooOld instead of a position when t is more than maxAge before
+// or after the TLE epoch, where SGP4 predictions are no longer trustworthy.
+// A maxAge of 0 disables the check.
+func PropagateSatelliteChecked(tle *TLE, t time.Time, maxAge time.Duration) (*SatellitePosition, error) {
+	if err := checkTLEAge(tle, t, maxAge); err != nil {
+		return nil, err
+	}
+	return PropagateSatellite(tle, t)
+}
+
+// checkTLEAge returns ErrTLETooOld if t is more than maxAge from the TLE
+// epoch, or nil if it isn't or maxAge is 0.
+func checkTLEAge(tle *TLE, t time.Time, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return nil
+	}
 	if tle == nil {
-		return nil, fmt.Errorf("TLE is nil")
+		return fmt.Errorf("TLE is nil")
+	}
+	epoch, err := tle.GetEpoch()
+	if err != nil {
+		return err
+	}
+	if age := t.Sub(epoch).Abs(); age > maxAge {
+		return fmt.Errorf("%w: %s from epoch, limit %s", ErrTLETooOld, FormatRelativeDuration(age), FormatRelativeDuration(maxAge))
 	}
+	return nil
+}
 
-	// Parse the TLE using go-satellite library
-	satrec := satellite.TLEToSat(tle.Line1, tle.Line2, "wgs72")
+// ExcludeStaleTLEs splits satellites into those whose TLE epoch is within
+// maxAge of t and the number left out because it is not (see
+// PropagateSatelliteChecked). Satellites without a readable epoch are kept for
+// propagation to report. A maxAge of 0 keeps every satellite.
+func ExcludeStaleTLEs(satellites []*Satellite, t time.Time, maxAge time.Duration) (current []*Satellite, excluded int) {
+	if maxAge <= 0 {
+		return satellites, 0
+	}
+	current = make([]*Satellite, 0, len(satellites))
+	for _, sat := range satellites {
+		if err := checkTLEAge(sat.TLE, t, maxAge); errors.Is(err, ErrTLETooOld) {
+			excluded++
+			continue
+		}
+		current = append(current, sat)
+	}
+	return current, excluded
+}
+
+// SubSatellitePoint returns the WGS84 geodetic latitude and longitude in degrees
+// of the point directly beneath the satellite, and its altitude in km.
+// pos must be Earth-fixed, as returned by PropagateSatellite, so that the
+// longitude follows the ground track. Longitude is normalized to [-180, 180).
+func SubSatellitePoint(pos *SatellitePosition) (lat, lon, altKm float64) {
+	return ecefToGeodetic(pos.X, pos.Y, pos.Z)
+}
 
-	// Get time components
-	year, month, day := t.Date()
-	hour, min, sec := t.Clock()
+// sgp4ErrDecayed is the SGP4 error code for an orbit that has decayed.
+const sgp4ErrDecayed = 6
 
-	// Propagate the satellite position
-	position, velocity := satellite.Propagate(satrec, year, int(month), day, hour, min, sec)
+// Propagator propagates a single TLE with SGP4. The TLE is parsed once, so
+// repeated propagation avoids re-initializing SGP4 at every step.
+// A Propagator is safe for concurrent use.
+type Propagator struct {
+	satrec satellite.Satellite
+}
 
-	// Check for propagation errors
+// NewPropagator parses tle and initializes SGP4 for it.
+func NewPropagator(tle *TLE) (*Propagator, error) {
+	if tle == nil {
+		return nil, fmt.Errorf("TLE is nil")
+	}
+
+	// Validate first; the go-satellite parser exits the process on bad input
+	line1, line2, err := tle.sgp4Lines()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTLEParse, err)
+	}
+
+	// Parse the TLE using go-satellite library
+	satrec := satellite.TLEToSat(line1, line2, "wgs72")
+	if satrec.Error == sgp4ErrDecayed {
+		return nil, fmt.Errorf("%w: perigee below the surface at epoch", ErrDecayed)
+	}
 	if satrec.Error != 0 {
 		return nil, fmt.Errorf("SGP4 propagation error: %d", satrec.Error)
 	}
 
-	return &SatellitePosition{
-		Time: t,
-		X:    position.X,
-		Y:    position.Y,
-		Z:    position.Z,
-		Vx:   velocity.X,
-		Vy:   velocity.Y,
-		Vz:   velocity.Z,
-	}, nil
+	return &Propagator{satrec: satrec}, nil
 }
 
-// PropagateRange propagates a satellite over a time range with a given step size.
-// Returns a slice of satellite positions.
-func PropagateRange(tle *TLE, startTime, endTime time.Time, stepSize time.Duration) ([]*SatellitePosition, error) {
-	if tle == nil {
-		return nil, fmt.Errorf("TLE is nil")
+// At returns the satellite's ECEF position and Earth-relative velocity at t,
+// as PropagateSatellite.
+func (p *Propagator) At(t time.Time) (*SatellitePosition, error) {
+	pos, err := p.teme(t)
+	if err != nil {
+		return nil, err
 	}
+	return TEMEToECEF(pos), nil
+}
 
-	if endTime.Before(startTime) {
+// Range returns ECEF positions every step from start to end inclusive, as
+// PropagateRange.
+func (p *Propagator) Range(start, end time.Time, step time.Duration) ([]*SatellitePosition, error) {
+	if end.Before(start) {
 		return nil, fmt.Errorf("end time must be after start time")
 	}
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive: %v", step)
+	}
 
-	positions := make([]*SatellitePosition, 0)
+	positions := make([]*SatellitePosition, 0, int(end.Sub(start)/step)+1)
 
-	for t := startTime; t.Before(endTime) || t.Equal(endTime); t = t.Add(stepSize) {
-		pos, err := PropagateSatellite(tle, t)
+	for t := start; !t.After(end); t = t.Add(step) {
+		pos, err := p.At(t)
 		if err != nil {
 			return nil, fmt.Errorf("propagation failed at %v: %w", t, err)
 		}
@@ -99,48 +213,91 @@ func PropagateRange(tle *TLE, startTime, endTime time.Time, stepSize time.Durati
 	return positions, nil
 }
 
-// ECEFToTopocentric converts ECEF coordinates to topocentric (ENU) coordinates
-// relative to an observer's position
-func ECEFToTopocentric(satPos *SatellitePosition, observer *ObserverPosition) (east, north, up float64) {
-	// Convert observer geodetic coordinates to radians
-	obsLatRad := observer.Latitude * math.Pi / 180.0
-	obsLonRad := observer.Longitude * math.Pi / 180.0
-	obsAltKm := observer.Altitude / 1000.0 // convert meters to km
-
-	// For observer position in ECEF, use geodetic to ECEF conversion
-	// Using WGS84 constants
-	const (
-		a  = 6378.137            // Earth semi-major axis in km
-		f  = 1.0 / 298.257223563 // Earth flattening
-		e2 = 2*f - f*f           // First eccentricity squared
-	)
+// teme runs SGP4 and returns the raw state vector, which is expressed in the
+// TEME (True Equator, Mean Equinox) inertial frame.
+func (p *Propagator) teme(t time.Time) (*SatellitePosition, error) {
+	// Get time components (SGP4 expects UTC)
+	utc := t.UTC()
+	year, month, day := utc.Date()
+	hour, min, sec := utc.Clock()
 
-	sinLat := math.Sin(obsLatRad)
-	cosLat := math.Cos(obsLatRad)
-	sinLon := math.Sin(obsLonRad)
-	cosLon := math.Cos(obsLonRad)
+	// Propagate the satellite position
+	position, velocity := satellite.Propagate(p.satrec, year, int(month), day, hour, min, sec)
 
-	N := a / math.Sqrt(1-e2*sinLat*sinLat)
+	// Propagate works on a copy of satrec, so runtime failures only show in the state
+	for _, v := range []float64{position.X, position.Y, position.Z, velocity.X, velocity.Y, velocity.Z} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("%w at %v", ErrNaNState, t)
+		}
+	}
+	if math.Sqrt(position.X*position.X+position.Y*position.Y+position.Z*position.Z) < earthRadiusKm {
+		return nil, fmt.Errorf("%w by %v", ErrDecayed, t)
+	}
 
-	obsX := (N + obsAltKm) * cosLat * cosLon
-	obsY := (N + obsAltKm) * cosLat * sinLon
-	obsZ := (N*(1-e2) + obsAltKm) * sinLat
+	return &SatellitePosition{
+		Time: t,
+		X:    position.X,
+		Y:    position.Y,
+		Z:    position.Z,
+		Vx:   velocity.X,
+		Vy:   velocity.Y,
+		Vz:   velocity.Z,
+	}, nil
+}
+
+// propagateTEME runs SGP4 for a single time and returns the TEME state vector.
+func propagateTEME(tle *TLE, t time.Time) (*SatellitePosition, error) {
+	p, err := NewPropagator(tle)
+	if err != nil {
+		return nil, err
+	}
+	return p.teme(t)
+}
 
-	// Calculate difference vector (satellite - observer) in ECEF
-	dx := satPos.X - obsX
-	dy := satPos.Y - obsY
-	dz := satPos.Z - obsZ
+// julianDate converts a time to a Julian date (UTC).
+func julianDate(t time.Time) float64 {
+	return float64(t.UnixNano())/86400e9 + 2440587.5
+}
+
+// PropagateRange propagates a satellite over a time range with a given step size.
+// Returns a slice of ECEF satellite positions, as PropagateSatellite.
+// The TLE is parsed once for the whole range; see Propagator.
+func PropagateRange(tle *TLE, startTime, endTime time.Time, stepSize time.Duration) ([]*SatellitePosition, error) {
+	p, err := NewPropagator(tle)
+	if err != nil {
+		return nil, err
+	}
+	return p.Range(startTime, endTime, stepSize)
+}
+
+// ecefToENU rotates an ECEF vector into the local east-north-up frame at the
+// given geodetic latitude and longitude in radians. It applies to both
+// relative positions and velocities.
+func ecefToENU(dx, dy, dz, latRad, lonRad float64) (east, north, up float64) {
+	sinLat, cosLat := math.Sin(latRad), math.Cos(latRad)
+	sinLon, cosLon := math.Sin(lonRad), math.Cos(lonRad)
 
-	// Rotation matrix from ECEF to topocentric (ENU)
 	east = -sinLon*dx + cosLon*dy
 	north = -sinLat*cosLon*dx - sinLat*sinLon*dy + cosLat*dz
 	up = cosLat*cosLon*dx + cosLat*sinLon*dy + sinLat*dz
-
 	return east, north, up
 }
 
+// ECEFToTopocentric converts ECEF coordinates to topocentric (ENU) coordinates
+// relative to an observer's position. satPos must be Earth-fixed, as returned
+// by PropagateSatellite; inertial positions must be converted first.
+func ECEFToTopocentric(satPos *SatellitePosition, observer *ObserverPosition) (east, north, up float64) {
+	obsX, obsY, obsZ := observerECEF(observer)
+
+	// Difference vector (satellite - observer) in ECEF, rotated to ENU
+	return ecefToENU(
+		satPos.X-obsX, satPos.Y-obsY, satPos.Z-obsZ,
+		observer.Latitude*math.Pi/180.0, observer.Longitude*math.Pi/180.0,
+	)
+}
+
 // CalculateObservationAngles calculates azimuth, elevation, range, and range rate
-// for a satellite position relative to an observer.
+// for an ECEF satellite position relative to an observer.
 func CalculateObservationAngles(satPos *SatellitePosition, observer *ObserverPosition) *ObservationAngles {
 	// Convert to topocentric coordinates
 	east, north, up := ECEFToTopocentric(satPos, observer)
@@ -161,17 +318,10 @@ func CalculateObservationAngles(satPos *SatellitePosition, observer *ObserverPos
 
 	// Calculate range rate (requires velocity)
 	// Transform velocity to topocentric frame
-	obsLatRad := observer.Latitude * math.Pi / 180.0
-	obsLonRad := observer.Longitude * math.Pi / 180.0
-
-	sinLat := math.Sin(obsLatRad)
-	cosLat := math.Cos(obsLatRad)
-	sinLon := math.Sin(obsLonRad)
-	cosLon := math.Cos(obsLonRad)
-
-	vEast := -sinLon*satPos.Vx + cosLon*satPos.Vy
-	vNorth := -sinLat*cosLon*satPos.Vx - sinLat*sinLon*satPos.Vy + cosLat*satPos.Vz
-	vUp := cosLat*cosLon*satPos.Vx + cosLat*sinLon*satPos.Vy + sinLat*satPos.Vz
+	vEast, vNorth, vUp := ecefToENU(
+		satPos.Vx, satPos.Vy, satPos.Vz,
+		observer.Latitude*math.Pi/180.0, observer.Longitude*math.Pi/180.0,
+	)
 
 	// Range rate is the dot product of velocity and range unit vector
 	rangeRate := (east*vEast + north*vNorth + up*vUp) / rangeKm
@@ -243,13 +393,8 @@ func DetermineOrbitRegime(apogee, perigee, period, inclination float64) OrbitReg
 		return RegimeUnknown
 	}
 
-	// Calculate semi-major axis (average altitude)
-	const earthRadius = 6371.0 // km
-	semiMajorAxis := ((apogee + earthRadius) + (perigee + earthRadius)) / 2.0
-	avgAltitude := semiMajorAxis - earthRadius
-
-	// Calculate eccentricity
-	eccentricity := (apogee - perigee) / (apogee + perigee + 2*earthRadius)
+	avgAltitude := orbitSemiMajorAxis(apogee, perigee) - meanEarthRadiusKm
+	eccentricity := orbitEccentricity(apogee, perigee)
 
 	// HEO: Highly Elliptical Orbit (eccentricity > 0.25)
 	if eccentricity > 0.25 {
@@ -259,9 +404,9 @@ func DetermineOrbitRegime(apogee, perigee, period, inclination float64) OrbitReg
 	// GEO: Geostationary orbit
 	// Period ~1436 minutes (23.93 hours), altitude ~35,786 km, low inclination
 	// Allow some tolerance for period and altitude
-	periodTolerance := 30.0        // minutes
-	altitudeTolerance := 500.0     // km
-	inclinationTolerance := 5.0    // degrees
+	periodTolerance := 30.0     // minutes
+	altitudeTolerance := 500.0  // km
+	inclinationTolerance := 5.0 // degrees
 
 	geoAltitude := 35786.0
 	geoPeriod := 1436.0
@@ -289,3 +434,42 @@ func DetermineOrbitRegime(apogee, perigee, period, inclination float64) OrbitReg
 
 	return RegimeUnknown
 }
+
+// ClassifyOrbitRegime classifies a satellite using its SATCAT orbital parameters,
+// falling back to the TLE mean motion, eccentricity, and inclination when the
+// SATCAT data is missing or insufficient.
+func ClassifyOrbitRegime(sat *Satellite) OrbitRegime {
+	regime := DetermineOrbitRegime(sat.Apogee, sat.Perigee, sat.Period, sat.Inclination)
+	if regime != RegimeUnknown || sat.TLE == nil {
+		return regime
+	}
+
+	apogee, perigee, period, inclination, ok := tleOrbit(sat.TLE)
+	if !ok {
+		return RegimeUnknown
+	}
+	return DetermineOrbitRegime(apogee, perigee, period, inclination)
+}
+
+// tleOrbit derives apogee and perigee altitude (km), period (minutes), and
+// inclination (degrees) from the TLE mean elements. ok is false if tle is nil
+// or its elements cannot be parsed.
+func tleOrbit(tle *TLE) (apogee, perigee, period, inclination float64, ok bool) {
+	if tle == nil {
+		return 0, 0, 0, 0, false
+	}
+	el, err := tle.elements()
+	if err != nil || el.MeanMotion <= 0 {
+		return 0, 0, 0, 0, false
+	}
+
+	// Semi-major axis from mean motion via Kepler's third law
+	n := el.MeanMotion * 2 * math.Pi / 86400.0
+	a := math.Cbrt(earthMu / (n * n))
+
+	return a*(1+el.Eccentricity) - meanEarthRadiusKm,
+		a*(1-el.Eccentricity) - meanEarthRadiusKm,
+		1440.0 / el.MeanMotion,
+		el.Inclination,
+		true
+}
@@ -0,0 +1,199 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// inlineTopocentric is the topocentric rotation as ECEFToTopocentric and
+// CalculateObservationAngles each wrote it out before sharing ecefToENU.
+func inlineTopocentric(satPos *SatellitePosition, observer *ObserverPosition) (east, north, up, vEast, vNorth, vUp float64) {
+	obsLatRad := observer.Latitude * math.Pi / 180.0
+	obsLonRad := observer.Longitude * math.Pi / 180.0
+
+	sinLat := math.Sin(obsLatRad)
+	cosLat := math.Cos(obsLatRad)
+	sinLon := math.Sin(obsLonRad)
+	cosLon := math.Cos(obsLonRad)
+
+	obsX, obsY, obsZ := observerECEF(observer)
+	dx := satPos.X - obsX
+	dy := satPos.Y - obsY
+	dz := satPos.Z - obsZ
+
+	east = -sinLon*dx + cosLon*dy
+	north = -sinLat*cosLon*dx - sinLat*sinLon*dy + cosLat*dz
+	up = cosLat*cosLon*dx + cosLat*sinLon*dy + sinLat*dz
+
+	vEast = -sinLon*satPos.Vx + cosLon*satPos.Vy
+	vNorth = -sinLat*cosLon*satPos.Vx - sinLat*sinLon*satPos.Vy + cosLat*satPos.Vz
+	vUp = cosLat*cosLon*satPos.Vx + cosLat*sinLon*satPos.Vy + sinLat*satPos.Vz
+	return east, north, up, vEast, vNorth, vUp
+}
+
+func TestECEFToENUMatchesInlineRotation(t *testing.T) {
+	observers := []*ObserverPosition{
+		{Latitude: 40, Longitude: -105, Altitude: 1600},
+		{Latitude: -33.9, Longitude: 151.2},
+		{Latitude: 89.9, Longitude: 0},
+		{Latitude: 0, Longitude: 179.9, Altitude: 10},
+	}
+	for _, tle := range []*TLE{issTLE(), geoTLE()} {
+		for minutes := 0; minutes < 120; minutes += 7 {
+			pos, err := PropagateSatellite(tle, testEpoch.Add(time.Duration(minutes)*time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, observer := range observers {
+				east, north, up, vEast, vNorth, vUp := inlineTopocentric(pos, observer)
+
+				e, n, u := ECEFToTopocentric(pos, observer)
+				if e != east || n != north || u != up {
+					t.Fatalf("ECEFToTopocentric = %v, %v, %v; inline rotation %v, %v, %v", e, n, u, east, north, up)
+				}
+
+				// The angles and range rate built on the rotation are unchanged
+				rangeKm := math.Sqrt(east*east + north*north + up*up)
+				angles := CalculateObservationAngles(pos, observer)
+				assertNear(t, "range", angles.Range, rangeKm, 1e-9)
+				assertNear(t, "elevation", angles.Elevation, math.Asin(up/rangeKm)*180/math.Pi, 1e-9)
+				assertNear(t, "range rate", angles.RangeRate, (east*vEast+north*vNorth+up*vUp)/rangeKm, 1e-12)
+			}
+		}
+	}
+}
+
+func TestSubSatellitePointGEO(t *testing.T) {
+	tle := geoTLE()
+	first, err := PropagateSatellite(tle, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lon0, _ := SubSatellitePoint(first)
+
+	// A geostationary satellite hangs over one longitude as the Earth turns
+	for hours := 0; hours <= 48; hours += 3 {
+		pos, err := PropagateSatellite(tle, testEpoch.Add(time.Duration(hours)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lat, lon, alt := SubSatellitePoint(pos)
+		assertNear(t, "GEO latitude", lat, 0, 0.1)
+		assertNear(t, "GEO longitude", NormalizeLongitude(lon-lon0), 0, 0.2)
+		assertNear(t, "GEO altitude", alt, 35786, 10)
+		if lon < -180 || lon >= 180 {
+			t.Errorf("longitude %v outside [-180, 180)", lon)
+		}
+	}
+}
+
+func TestSubSatellitePointRoundTrip(t *testing.T) {
+	// Points above the WGS84 ellipsoid, converted to ECEF and back
+	for _, p := range []ObserverPosition{
+		{Latitude: 0, Longitude: 0, Altitude: 0},
+		{Latitude: 40, Longitude: -105, Altitude: 400e3},
+		{Latitude: -33.9, Longitude: 151.2, Altitude: 35786e3},
+		{Latitude: 89.5, Longitude: 179.9, Altitude: 800e3},
+		{Latitude: -60, Longitude: -179.9, Altitude: 20200e3},
+	} {
+		x, y, z := observerECEF(&p)
+		lat, lon, alt := SubSatellitePoint(&SatellitePosition{X: x, Y: y, Z: z})
+		// Bowring's method is good to a few centimeters at these heights
+		assertNear(t, "latitude", lat, p.Latitude, 1e-6)
+		assertNear(t, "longitude", lon, p.Longitude, 1e-9)
+		assertNear(t, "altitude", alt, p.Altitude/1000, 1e-3)
+	}
+}
+
+func TestPropagatorMatchesPropagateSatellite(t *testing.T) {
+	tle := issTLE()
+	p, err := NewPropagator(tle)
+	if err != nil {
+		t.Fatalf("NewPropagator: %v", err)
+	}
+
+	start, end := testEpoch, testEpoch.Add(2*time.Hour)
+	positions, err := p.Range(start, end, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if len(positions) != 13 {
+		t.Fatalf("Range returned %d positions, want 13 from start to end inclusive", len(positions))
+	}
+	for i, pos := range positions {
+		at := start.Add(time.Duration(i) * 10 * time.Minute)
+		want, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := p.At(at); *got != *want || *pos != *want {
+			t.Errorf("at %v: At %+v, Range %+v; want %+v", at, got, pos, want)
+		}
+	}
+
+	if _, err := p.Range(end, start, time.Minute); err == nil {
+		t.Error("Range accepted an end before the start")
+	}
+	if _, err := p.Range(start, end, 0); err == nil {
+		t.Error("Range accepted a zero step")
+	}
+	if _, err := NewPropagator(nil); err == nil {
+		t.Error("NewPropagator accepted a nil TLE")
+	}
+}
+
+// BenchmarkPropagateDay compares a day of one-minute steps propagated with a
+// cached Propagator against re-parsing the TLE at every step.
+func BenchmarkPropagateDay(b *testing.B) {
+	tle := issTLE()
+	end := testEpoch.Add(24 * time.Hour)
+
+	b.Run("Propagator", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := PropagateRange(tle, testEpoch, end, time.Minute); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("PropagateSatellite", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for t := testEpoch; !t.After(end); t = t.Add(time.Minute) {
+				if _, err := PropagateSatellite(tle, t); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestPropagateBlankAndMalformedLines(t *testing.T) {
+	iss := issTLE()
+	tests := []struct {
+		name         string
+		line1, line2 string
+		want         string
+	}{
+		{"empty line 1", "", iss.Line2, "TLE line 1 is empty"},
+		{"blank line 2", iss.Line1, "  \t ", "TLE line 2 is empty"},
+		{"swapped lines", iss.Line2, iss.Line1, `TLE line 1 must start with "1 "`},
+		{"name in line 2", iss.Line1, "ISS (ZARYA)", `TLE line 2 must start with "2 "`},
+		{"truncated line 1", iss.Line1[:40], iss.Line2, "TLE line 1 is too short (40 characters, need at least 61)"},
+		{"garbled field", iss.Line1, iss.Line2[:8] + "5X" + iss.Line2[10:], "inclination"},
+	}
+	for _, tt := range tests {
+		_, err := PropagateSatellite(&TLE{Line1: tt.line1, Line2: tt.line2}, testEpoch)
+		if !errors.Is(err, ErrTLEParse) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: %v, want a TLE parse error mentioning %q", tt.name, err, tt.want)
+		}
+	}
+
+	// Surrounding whitespace, such as CRLF line endings, is not an error
+	if _, err := PropagateSatellite(&TLE{Line1: " " + iss.Line1 + "\r", Line2: iss.Line2 + "\r\n"}, testEpoch); err != nil {
+		t.Errorf("TLE with surrounding whitespace: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package satellite
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// radarReferenceRangeKm is the range at which a 1 m² target scores 0 dB
+	radarReferenceRangeKm = 1000.0
+	// radarMinElevation is the elevation in degrees below which ground clutter
+	// and atmospheric losses make tracking impractical
+	radarMinElevation = 5.0
+	// radarLowElevationLossDB is the extra loss applied below radarLowElevation
+	radarLowElevationLossDB = 3.0
+	// radarLowElevation is the elevation in degrees below which
+	// radarLowElevationLossDB applies
+	radarLowElevation = 15.0
+)
+
+// rcsCategoryArea maps SATCAT RCS size categories to a representative radar
+// cross section in m². SATCAT bins are SMALL (< 0.1 m²), MEDIUM (0.1-1 m²),
+// and LARGE (> 1 m²); the values are rough geometric midpoints.
+var rcsCategoryArea = map[string]float64{
+	"SMALL":  0.03,
+	"MEDIUM": 0.3,
+	"LARGE":  3.0,
+}
+
+// DetectabilityLevel is a qualitative radar detectability rating.
+type DetectabilityLevel string
+
+const (
+	DetectabilityHigh     DetectabilityLevel = "high"     // relative SNR >= 10 dB
+	DetectabilityModerate DetectabilityLevel = "moderate" // relative SNR >= 0 dB
+	DetectabilityLow      DetectabilityLevel = "low"      // relative SNR >= -10 dB
+	DetectabilityNone     DetectabilityLevel = "none"     // weaker, or below radarMinElevation
+)
+
+// Detectability is a rough estimate of how easily a ground radar can detect
+// a satellite. It is approximate: it only scales the radar equation by the
+// SATCAT RCS category and range and ignores the radar itself, aspect, and
+// propagation effects beyond a fixed low-elevation loss.
+type Detectability struct {
+	RCS         float64            // assumed radar cross section in m²
+	RCSKnown    bool               // false if the satellite has no RCS category and MEDIUM was assumed
+	RelativeSNR float64            // dB relative to a 1 m² target at 1000 km
+	Level       DetectabilityLevel // qualitative rating of RelativeSNR
+}
+
+// RadarDetectability estimates how easily a satellite at the given angles can
+// be detected by radar. Received power falls with the fourth power of range,
+// so RelativeSNR = 10·log10(RCS) − 40·log10(range / 1000 km), less 3 dB below
+// 15° elevation. Satellites below 5° elevation rate DetectabilityNone.
+func RadarDetectability(sat *Satellite, angles *ObservationAngles) Detectability {
+	rcs, known := rcsCategoryArea[strings.ToUpper(strings.TrimSpace(sat.RCSSize))]
+	if !known {
+		rcs = rcsCategoryArea["MEDIUM"]
+	}
+
+	snr := 10*math.Log10(rcs) - 40*math.Log10(angles.Range/radarReferenceRangeKm)
+	if angles.Elevation < radarLowElevation {
+		snr -= radarLowElevationLossDB
+	}
+
+	d := Detectability{RCS: rcs, RCSKnown: known, RelativeSNR: snr}
+	switch {
+	case angles.Elevation < radarMinElevation:
+		d.Level = DetectabilityNone
+	case snr >= 10:
+		d.Level = DetectabilityHigh
+	case snr >= 0:
+		d.Level = DetectabilityModerate
+	case snr >= -10:
+		d.Level = DetectabilityLow
+	default:
+		d.Level = DetectabilityNone
+	}
+	return d
+}
@@ -0,0 +1,47 @@
+package satellite
+
+import "testing"
+
+func TestRadarDetectability(t *testing.T) {
+	large := &Satellite{Name: "LARGE", RCSSize: "LARGE"}
+	small := &Satellite{Name: "SMALL", RCSSize: " small "}
+	unknown := &Satellite{Name: "UNKNOWN"}
+
+	near := &ObservationAngles{Elevation: 60, Range: 500}
+	far := &ObservationAngles{Elevation: 20, Range: 2000}
+	low := &ObservationAngles{Elevation: 10, Range: 500}
+
+	largeNear := RadarDetectability(large, near)
+	smallFar := RadarDetectability(small, far)
+	if largeNear.RelativeSNR <= smallFar.RelativeSNR {
+		t.Errorf("LARGE at 500 km: %.1f dB, SMALL at 2000 km: %.1f dB; want LARGE near higher", largeNear.RelativeSNR, smallFar.RelativeSNR)
+	}
+	if largeNear.Level != DetectabilityHigh || smallFar.Level != DetectabilityNone {
+		t.Errorf("levels %s and %s, want high and none", largeNear.Level, smallFar.Level)
+	}
+
+	tests := []struct {
+		name   string
+		sat    *Satellite
+		angles *ObservationAngles
+		snr    float64
+		known  bool
+		level  DetectabilityLevel
+	}{
+		// 10·log10(3) + 40·log10(2)
+		{"LARGE near", large, near, 16.81, true, DetectabilityHigh},
+		// 10·log10(0.03) − 40·log10(2)
+		{"SMALL far", small, far, -27.27, true, DetectabilityNone},
+		// MEDIUM is assumed: 10·log10(0.3) − 0
+		{"unknown at the reference range", unknown, &ObservationAngles{Elevation: 45, Range: 1000}, -5.23, false, DetectabilityLow},
+		{"low elevation loss", large, low, 13.81, true, DetectabilityHigh},
+		{"below the minimum elevation", large, &ObservationAngles{Elevation: 2, Range: 500}, 13.81, true, DetectabilityNone},
+	}
+	for _, tt := range tests {
+		d := RadarDetectability(tt.sat, tt.angles)
+		assertNear(t, tt.name+" relative SNR", d.RelativeSNR, tt.snr, 0.01)
+		if d.RCSKnown != tt.known || d.Level != tt.level {
+			t.Errorf("%s: known %v, level %s; want %v, %s", tt.name, d.RCSKnown, d.Level, tt.known, tt.level)
+		}
+	}
+}
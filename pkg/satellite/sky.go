@@ -0,0 +1,137 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// AngularSeparation returns the angle in degrees between two directions in
+// the observer's sky, given as azimuth/elevation pairs.
+func AngularSeparation(a, b *ObservationAngles) float64 {
+	const deg = math.Pi / 180.0
+
+	el1, el2 := a.Elevation*deg, b.Elevation*deg
+	dAz := (b.Azimuth - a.Azimuth) * deg
+
+	// Haversine form is well-conditioned for small separations
+	h := math.Pow(math.Sin((el2-el1)/2), 2) +
+		math.Cos(el1)*math.Cos(el2)*math.Pow(math.Sin(dAz/2), 2)
+	return 2 * math.Asin(math.Min(1, math.Sqrt(h))) / deg
+}
+
+// separationAt returns the angular separation of two satellites as seen by the observer at t.
+func separationAt(tleA, tleB *TLE, observer *ObserverPosition, t time.Time) (float64, error) {
+	posA, err := PropagateSatellite(tleA, t)
+	if err != nil {
+		return 0, fmt.Errorf("satellite A: %w", err)
+	}
+	posB, err := PropagateSatellite(tleB, t)
+	if err != nil {
+		return 0, fmt.Errorf("satellite B: %w", err)
+	}
+
+	return AngularSeparation(
+		CalculateObservationAngles(posA, observer),
+		CalculateObservationAngles(posB, observer),
+	), nil
+}
+
+// AngularSeparationRate returns how fast the apparent separation of two satellites
+// is changing in degrees per second, as seen by the observer at time t.
+// Negative values mean the satellites are closing; positive values mean they are separating.
+// The rate is a central finite difference over ±1 second.
+func AngularSeparationRate(tleA, tleB *TLE, observer *ObserverPosition, t time.Time) (float64, error) {
+	const dt = time.Second
+
+	before, err := separationAt(tleA, tleB, observer, t.Add(-dt))
+	if err != nil {
+		return 0, err
+	}
+	after, err := separationAt(tleA, tleB, observer, t.Add(dt))
+	if err != nil {
+		return 0, err
+	}
+
+	return (after - before) / (2 * dt.Seconds()), nil
+}
+
+// AngleRates returns the rates of change of azimuth and elevation in degrees per
+// second as seen by the observer at t, using a central difference over ±1 second.
+// The azimuth rate accounts for wrap-around at north.
+func AngleRates(tle *TLE, observer *ObserverPosition, t time.Time) (azRate, elRate float64, err error) {
+	const dt = time.Second
+
+	before, err := observe(tle, observer, t.Add(-dt))
+	if err != nil {
+		return 0, 0, err
+	}
+	after, err := observe(tle, observer, t.Add(dt))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dAz := math.Mod(after.Azimuth-before.Azimuth+540.0, 360.0) - 180.0
+	return dAz / (2 * dt.Seconds()), (after.Elevation - before.Elevation) / (2 * dt.Seconds()), nil
+}
+
+// TopocentricRADec returns the J2000 right ascension (0-360) and declination
+// in degrees of an ECEF satellite position as seen by the observer, for
+// comparison with star catalogs. Aberration and refraction are ignored.
+func TopocentricRADec(pos *SatellitePosition, observer *ObserverPosition) (ra, dec float64) {
+	ox, oy, oz := observerECEF(observer)
+
+	// Earth-fixed -> TEME -> J2000; directions need no translation between them
+	toJ2000 := temeToJ2000Matrix(pos.Time).mul(rot3(gmst(pos.Time)).transpose())
+	x, y, z := toJ2000.apply(pos.X-ox, pos.Y-oy, pos.Z-oz)
+
+	ra = math.Atan2(y, x) * 180.0 / math.Pi
+	if ra < 0 {
+		ra += 360.0
+	}
+	dec = math.Atan2(z, math.Sqrt(x*x+y*y)) * 180.0 / math.Pi
+	return ra, dec
+}
+
+// inRABox reports whether ra/dec falls inside the box. A box with raMin > raMax
+// wraps through RA 0°.
+func inRABox(ra, dec, raMin, raMax, decMin, decMax float64) bool {
+	if dec < decMin || dec > decMax {
+		return false
+	}
+	if raMin <= raMax {
+		return ra >= raMin && ra <= raMax
+	}
+	return ra >= raMin || ra <= raMax
+}
+
+// PassInSkyRegion returns the first window during the pass in which the
+// satellite lies within the J2000 RA/Dec box, sampled every second. RA bounds
+// are in degrees 0-360; a box with raMin > raMax wraps through RA 0°. found is false if the
+// satellite never enters the region.
+func PassInSkyRegion(pass *Pass, tle *TLE, observer *ObserverPosition, raMin, raMax, decMin, decMax float64) (start, end time.Time, found bool, err error) {
+	if tle == nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("TLE is nil")
+	}
+	if decMin > decMax {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("decMin must not exceed decMax")
+	}
+	for t := pass.AOS; !t.After(pass.LOS); t = t.Add(time.Second) {
+		pos, err := PropagateSatellite(tle, t)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("propagation failed at %v: %w", t, err)
+		}
+
+		ra, dec := TopocentricRADec(pos, observer)
+		if inRABox(ra, dec, raMin, raMax, decMin, decMax) {
+			if !found {
+				start, found = t, true
+			}
+			end = t
+		} else if found {
+			break
+		}
+	}
+
+	return start, end, found, nil
+}
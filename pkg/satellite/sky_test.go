@@ -0,0 +1,149 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAngularSeparation(t *testing.T) {
+	tests := []struct {
+		a, b ObservationAngles
+		want float64
+	}{
+		{ObservationAngles{Azimuth: 0, Elevation: 90}, ObservationAngles{Azimuth: 123, Elevation: 0}, 90},
+		{ObservationAngles{Azimuth: 359, Elevation: 0}, ObservationAngles{Azimuth: 1, Elevation: 0}, 2},
+		{ObservationAngles{Azimuth: 90, Elevation: 10}, ObservationAngles{Azimuth: 270, Elevation: 10}, 160},
+		{ObservationAngles{Azimuth: 45, Elevation: 30}, ObservationAngles{Azimuth: 45, Elevation: 30}, 0},
+	}
+	for _, tt := range tests {
+		assertNear(t, "AngularSeparation", AngularSeparation(&tt.a, &tt.b), tt.want, 1e-9)
+	}
+}
+
+func TestAngularSeparationRateSign(t *testing.T) {
+	// Two LEO satellites in the same plane at different mean motions, whose
+	// apparent separation from mid-latitudes both grows and shrinks over 3h
+	tleA := makeTLE(10001, testEpoch, 51.6, 200, 0.0005, 0, 0, 15.5)
+	tleB := makeTLE(10002, testEpoch, 51.6, 200, 0.0005, 0, 20, 15.0)
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+
+	approaching, separating := 0, 0
+	for step := 0; step < 180; step++ {
+		at := testEpoch.Add(time.Duration(step) * time.Minute)
+
+		rate, err := AngularSeparationRate(tleA, tleB, observer, at)
+		if err != nil {
+			t.Fatalf("AngularSeparationRate: %v", err)
+		}
+
+		// Compare against a coarser difference of the separation itself
+		before, err := separationAt(tleA, tleB, observer, at.Add(-10*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := separationAt(tleA, tleB, observer, at.Add(10*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		coarse := (after - before) / 20
+		if math.Abs(coarse) < 1e-3 {
+			continue // too close to a turning point to compare signs
+		}
+
+		if math.Signbit(rate) != math.Signbit(coarse) {
+			t.Errorf("at %v: rate %.5f°/s, separation changing by %.5f°/s", at, rate, coarse)
+		}
+		if rate < 0 {
+			approaching++
+		} else {
+			separating++
+		}
+	}
+
+	if approaching == 0 || separating == 0 {
+		t.Errorf("sampled %d approaching and %d separating instants, want both", approaching, separating)
+	}
+}
+
+func TestTopocentricRADecZenith(t *testing.T) {
+	// A point straight above the observer lies at the local sidereal time and
+	// a declination of the observer's latitude, give or take precession since
+	// J2000 and the geodetic-geocentric latitude difference
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105}
+	above := *observer
+	above.Altitude = 1000e3
+	x, y, z := observerECEF(&above)
+
+	ra, dec := TopocentricRADec(&SatellitePosition{Time: testEpoch, X: x, Y: y, Z: z}, observer)
+	lst := math.Mod(gmst(testEpoch)*180/math.Pi+observer.Longitude+360, 360)
+	assertNear(t, "zenith RA", NormalizeLongitude(ra-lst), 0, 0.5)
+	assertNear(t, "zenith declination", dec, observer.Latitude, 0.5)
+}
+
+func TestPassInSkyRegion(t *testing.T) {
+	tle := issTLE()
+	observer := &ObserverPosition{Latitude: 40, Longitude: -105, Altitude: 1600}
+	passes, err := PredictPasses(tle, observer, testEpoch, testEpoch.Add(24*time.Hour), 30*time.Second, 20)
+	if err != nil || len(passes) == 0 {
+		t.Fatalf("PredictPasses = %d passes, %v", len(passes), err)
+	}
+	pass := &passes[0]
+
+	// A 4° box centered on where the satellite culminates
+	pos, err := PropagateSatellite(tle, pass.MaxElevationTime.Truncate(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ra, dec := TopocentricRADec(pos, observer)
+	raMin, raMax := math.Mod(ra-2+360, 360), math.Mod(ra+2, 360)
+
+	start, end, found, err := PassInSkyRegion(pass, tle, observer, raMin, raMax, dec-2, dec+2)
+	if err != nil || !found {
+		t.Fatalf("PassInSkyRegion = found %v, %v; want the culmination region", found, err)
+	}
+	if pos.Time.Before(start) || pos.Time.After(end) || end.Sub(start) > 2*time.Minute {
+		t.Errorf("window %v-%v does not tightly contain culmination at %v", start, end, pos.Time)
+	}
+
+	// Every second in the window is inside the box, and a second either side is not
+	inside := func(at time.Time) bool {
+		p, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, d := TopocentricRADec(p, observer)
+		return inRABox(r, d, raMin, raMax, dec-2, dec+2)
+	}
+	for at := start; !at.After(end); at = at.Add(time.Second) {
+		if !inside(at) {
+			t.Fatalf("%v is in the window but outside the box", at)
+		}
+	}
+	if inside(start.Add(-time.Second)) || inside(end.Add(time.Second)) {
+		t.Errorf("window %v-%v stops short of the region's edges", start, end)
+	}
+
+	// The opposite side of the sky is never crossed
+	if _, _, found, err := PassInSkyRegion(pass, tle, observer, math.Mod(ra+178, 360), math.Mod(ra+182, 360), -dec-2, -dec+2); err != nil || found {
+		t.Errorf("region on the far side of the sky found %v, %v", found, err)
+	}
+	if _, _, _, err := PassInSkyRegion(pass, tle, observer, 0, 10, 20, 10); err == nil {
+		t.Error("PassInSkyRegion accepted decMin > decMax")
+	}
+}
+
+func TestInRABoxWrap(t *testing.T) {
+	tests := []struct {
+		ra   float64
+		want bool
+	}{{355, true}, {0, true}, {5, true}, {10, false}, {180, false}, {349, false}}
+	for _, tt := range tests {
+		if got := inRABox(tt.ra, 0, 350, 5, -10, 10); got != tt.want {
+			t.Errorf("RA %v in 350-5 box = %v, want %v", tt.ra, got, tt.want)
+		}
+	}
+	if inRABox(0, 11, 350, 5, -10, 10) {
+		t.Error("declination outside the box accepted")
+	}
+}
@@ -1,18 +1,34 @@
 package satellite
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Catalog file formats, which are also the catalog file extensions.
+const (
+	CatalogFormatJSON = "json" // interoperable default
+	CatalogFormatGob  = "gob"  // Go binary encoding; loads several times faster
 )
 
 // Storage handles persistence of catalog data
 type Storage struct {
-	dataDir string
+	dataDir      string
+	format       string
+	compress     bool
+	maxSnapshots int
 }
 
-// NewStorage creates a new storage instance
+// NewStorage creates a new storage instance that keeps the catalog as JSON
 func NewStorage(dataDir string) (*Storage, error) {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
@@ -20,48 +36,487 @@ func NewStorage(dataDir string) (*Storage, error) {
 
 	return &Storage{
 		dataDir: dataDir,
+		format:  CatalogFormatJSON,
 	}, nil
 }
 
-// catalogPath returns the path to the catalog file
-func (s *Storage) catalogPath() string {
-	return filepath.Join(s.dataDir, "catalog.json")
+// SetCatalogFormat selects the format the catalog is saved in, CatalogFormatJSON
+// or CatalogFormatGob. An empty format keeps JSON.
+func (s *Storage) SetCatalogFormat(format string) error {
+	switch format {
+	case "":
+		s.format = CatalogFormatJSON
+	case CatalogFormatJSON, CatalogFormatGob:
+		s.format = format
+	default:
+		return fmt.Errorf("unknown catalog format %q (expected %s or %s)", format, CatalogFormatJSON, CatalogFormatGob)
+	}
+	return nil
+}
+
+// SetCompress selects whether the catalog is saved gzip-compressed, as
+// catalog.json.gz or catalog.gob.gz. Load reads compressed and uncompressed
+// catalogs either way.
+func (s *Storage) SetCompress(compress bool) {
+	s.compress = compress
 }
 
-// Save persists the catalog to disk
+// SetMaxSnapshots sets how many catalog snapshots SaveSnapshot keeps; older
+// ones are deleted. 0 keeps them all.
+func (s *Storage) SetMaxSnapshots(n int) error {
+	if n < 0 {
+		return fmt.Errorf("max snapshots must not be negative: %d", n)
+	}
+	s.maxSnapshots = n
+	return nil
+}
+
+// CatalogPath returns the path the catalog is saved to
+func (s *Storage) CatalogPath() string {
+	return s.catalogPathFor(s.format, s.compress)
+}
+
+// catalogPathFor returns the path to the catalog file in the given format
+func (s *Storage) catalogPathFor(format string, compress bool) string {
+	return filepath.Join(s.dataDir, "catalog."+catalogExt(format, compress))
+}
+
+// catalogExt returns the file extension, without the leading dot, of a
+// catalog in the given format
+func catalogExt(format string, compress bool) string {
+	if compress {
+		return format + gzipExt
+	}
+	return format
+}
+
+// gzipExt is the extension added to gzip-compressed catalog files
+const gzipExt = ".gz"
+
+// Save persists the catalog to disk, then removes any catalog saved in
+// another format or compression so that an outdated copy is never loaded.
+// A catalog that fails Validate is not saved.
 func (s *Storage) Save(catalog *Catalog) error {
-	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err := catalog.Validate(); err != nil {
+		return err
+	}
+	path := s.CatalogPath()
+	if err := SaveCatalogFile(path, catalog); err != nil {
+		return err
+	}
+	for _, other := range s.catalogPaths() {
+		if other == path {
+			continue
+		}
+		if err := os.Remove(other); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old catalog file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Load reads the catalog from disk. If there is no catalog in the selected
+// format and compression, one saved in another is loaded instead, so
+// switching formats or turning compression on or off keeps the existing
+// catalog until the next save.
+func (s *Storage) Load() (*Catalog, error) {
+	for _, path := range append([]string{s.CatalogPath()}, s.catalogPaths()...) {
+		catalog, err := LoadCatalogFile(path)
+		if catalog != nil || err != nil {
+			return catalog, err
+		}
+	}
+	return nil, nil // No catalog exists yet
+}
+
+// GetByNorad loads the catalog and returns the satellite with the NORAD ID,
+// or nil if it is not in the catalog or no catalog is stored.
+func (s *Storage) GetByNorad(noradID int) (*Satellite, error) {
+	catalog, err := s.Load()
+	if err != nil || catalog == nil {
+		return nil, err
+	}
+	for _, sat := range catalog.Satellites {
+		if sat.NoradID == noradID {
+			return sat, nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchedAt returns when the catalog Load would return was fetched, or the
+// zero time if no catalog is stored. A JSON catalog's satellites are skipped
+// rather than decoded; a gob catalog is loaded whole.
+func (s *Storage) FetchedAt() (time.Time, error) {
+	for _, path := range append([]string{s.CatalogPath()}, s.catalogPaths()...) {
+		if isGobPath(path) {
+			catalog, err := LoadCatalogFile(path)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if catalog != nil {
+				return catalog.FetchedAt, nil
+			}
+			continue
+		}
+
+		data, err := readCatalogFile(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if data == nil {
+			continue
+		}
+		var header struct {
+			FetchedAt       time.Time `json:"fetchedAt"`
+			LegacyFetchedAt time.Time `json:"fetched_at"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return time.Time{}, fmt.Errorf("failed to unmarshal catalog: %w", err)
+		}
+		if header.FetchedAt.IsZero() {
+			return header.LegacyFetchedAt, nil
+		}
+		return header.FetchedAt, nil
+	}
+	return time.Time{}, nil // No catalog exists yet
+}
+
+// Query loads the catalog and returns the satellites matching the criteria,
+// as SearchSatellites. Returns an empty result if no catalog is stored.
+func (s *Storage) Query(criteria SearchCriteria) ([]*Satellite, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+	catalog, err := s.Load()
 	if err != nil {
-		return fmt.Errorf("failed to marshal catalog: %w", err)
+		return nil, err
+	}
+	if catalog == nil {
+		return []*Satellite{}, nil
 	}
+	return SearchSatellites(catalog.Satellites, criteria), nil
+}
 
-	if err := os.WriteFile(s.catalogPath(), data, 0644); err != nil {
-		return fmt.Errorf("failed to write catalog file: %w", err)
+// Exists checks if a catalog file exists in any format
+func (s *Storage) Exists() bool {
+	for _, path := range s.catalogPaths() {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogPaths returns the catalog file path in every format, compressed
+// and not
+func (s *Storage) catalogPaths() []string {
+	var paths []string
+	for _, format := range []string{CatalogFormatJSON, CatalogFormatGob} {
+		paths = append(paths, s.catalogPathFor(format, true), s.catalogPathFor(format, false))
+	}
+	return paths
+}
+
+// snapshotTime is the UTC timestamp format in snapshot file names
+const snapshotTime = "20060102T150405Z"
+
+// snapshotPath returns the path of the snapshot taken at t, saved like the catalog
+func (s *Storage) snapshotPath(t time.Time) string {
+	return filepath.Join(s.dataDir, "catalog-"+t.UTC().Format(snapshotTime)+"."+catalogExt(s.format, s.compress))
+}
+
+// SaveSnapshot saves the catalog as a snapshot named after its fetch time,
+// e.g. catalog-20240601T120000Z.json, alongside the current catalog, then
+// deletes the oldest snapshots beyond the SetMaxSnapshots limit. A catalog
+// without a fetch time is rejected, since it could not be ordered.
+func (s *Storage) SaveSnapshot(catalog *Catalog) error {
+	if catalog.FetchedAt.IsZero() {
+		return fmt.Errorf("cannot snapshot a catalog without a fetch time")
+	}
+	if err := SaveCatalogFile(s.snapshotPath(catalog.FetchedAt), catalog); err != nil {
+		return err
+	}
+	if s.maxSnapshots == 0 {
+		return nil
 	}
 
+	snapshots, err := s.snapshotFiles()
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > s.maxSnapshots {
+		if err := os.Remove(snapshots[0].path); err != nil {
+			return fmt.Errorf("failed to prune snapshot: %w", err)
+		}
+		snapshots = snapshots[1:]
+	}
 	return nil
 }
 
-// Load reads the catalog from disk
-func (s *Storage) Load() (*Catalog, error) {
-	data, err := os.ReadFile(s.catalogPath())
+// ListSnapshots returns the fetch times of the saved snapshots, oldest first.
+func (s *Storage) ListSnapshots() ([]time.Time, error) {
+	snapshots, err := s.snapshotFiles()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No catalog exists yet
+		return nil, err
+	}
+	times := make([]time.Time, len(snapshots))
+	for i, snap := range snapshots {
+		times[i] = snap.time
+	}
+	return times, nil
+}
+
+// LoadSnapshot loads the latest snapshot taken at or before t, which is the
+// catalog as it was at t. Returns nil without error if there is none.
+func (s *Storage) LoadSnapshot(t time.Time) (*Catalog, error) {
+	snapshots, err := s.snapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].time.After(t) {
+			return LoadCatalogFile(snapshots[i].path)
 		}
-		return nil, fmt.Errorf("failed to read catalog file: %w", err)
+	}
+	return nil, nil
+}
+
+// snapshotFile is a snapshot on disk
+type snapshotFile struct {
+	path string
+	time time.Time
+}
+
+// snapshotFiles returns the snapshots in the data directory in any format,
+// oldest first.
+func (s *Storage) snapshotFiles() ([]snapshotFile, error) {
+	entries, err := os.ReadDir(s.dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var snapshots []snapshotFile
+	for _, entry := range entries {
+		name := entry.Name()
+		base := strings.TrimSuffix(name, gzipExt)
+		ext := strings.ToLower(filepath.Ext(base))
+		if entry.IsDir() || (ext != "."+CatalogFormatJSON && ext != "."+CatalogFormatGob) {
+			continue
+		}
+		stamp, ok := strings.CutPrefix(strings.TrimSuffix(base, filepath.Ext(base)), "catalog-")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(snapshotTime, stamp)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshotFile{path: filepath.Join(s.dataDir, name), time: t})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].time.Before(snapshots[j].time)
+	})
+	return snapshots, nil
+}
+
+// SaveCatalogFile writes the catalog to path, as gob if the path ends in
+// ".gob" and as indented JSON otherwise, gzip-compressed if it also ends in
+// ".gz" (e.g. "catalog.json.gz"). The file is replaced atomically, so a
+// failed write leaves any existing catalog at path intact.
+func SaveCatalogFile(path string, catalog *Catalog) error {
+	var data []byte
+	if isGobPath(path) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(catalog); err != nil {
+			return fmt.Errorf("failed to encode catalog: %w", err)
+		}
+		data = buf.Bytes()
+	} else {
+		var err error
+		if data, err = json.MarshalIndent(catalog, "", "  "); err != nil {
+			return fmt.Errorf("failed to marshal catalog: %w", err)
+		}
+	}
+
+	if isGzipPath(path) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return fmt.Errorf("failed to compress catalog: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress catalog: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write catalog file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in path's directory, syncs
+// it, and renames it over path, so path holds either its old or its new
+// contents even if writing fails or the process crashes part way.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// Clean up after any failure; after the rename this is a no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadCatalogFile reads a catalog written by SaveCatalogFile, choosing the
+// decoder and decompression by extension the same way. Returns nil without
+// error if the file does not exist.
+func LoadCatalogFile(path string) (*Catalog, error) {
+	data, err := readCatalogFile(path)
+	if data == nil || err != nil {
+		return nil, err
 	}
 
 	var catalog Catalog
-	if err := json.Unmarshal(data, &catalog); err != nil {
+	if isGobPath(path) {
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&catalog); err != nil {
+			return nil, fmt.Errorf("failed to decode catalog: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &catalog); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal catalog: %w", err)
 	}
 
 	return &catalog, nil
 }
 
-// Exists checks if a catalog file exists
-func (s *Storage) Exists() bool {
-	_, err := os.Stat(s.catalogPath())
-	return err == nil
+// readCatalogFile reads a catalog file, decompressing it if its extension
+// says so. Returns nil without error if the file does not exist.
+func readCatalogFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read catalog file: %w", err)
+	}
+
+	if isGzipPath(path) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress catalog: %w", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("failed to decompress catalog: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// isGobPath reports whether a catalog path selects the gob format
+func isGobPath(path string) bool {
+	if isGzipPath(path) {
+		path = path[:len(path)-len(gzipExt)]
+	}
+	return strings.EqualFold(filepath.Ext(path), "."+CatalogFormatGob)
+}
+
+// isGzipPath reports whether a catalog path selects gzip compression
+func isGzipPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), gzipExt)
+}
+
+// annotationsPath returns the path to the user annotations file
+func (s *Storage) annotationsPath() string {
+	return filepath.Join(s.dataDir, "annotations.json")
+}
+
+// SaveAnnotations persists user annotations to disk
+func (s *Storage) SaveAnnotations(annotations Annotations) error {
+	data, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal annotations: %w", err)
+	}
+
+	if err := writeFileAtomic(s.annotationsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write annotations file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadAnnotations reads user annotations from disk.
+// Returns an empty set if none have been saved yet.
+func (s *Storage) LoadAnnotations() (Annotations, error) {
+	data, err := os.ReadFile(s.annotationsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Annotations{}, nil
+		}
+		return nil, fmt.Errorf("failed to read annotations file: %w", err)
+	}
+
+	annotations := Annotations{}
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
+	}
+
+	return annotations, nil
+}
+
+// validatorsPath returns the path to the feed cache validators file
+func (s *Storage) validatorsPath() string {
+	return filepath.Join(s.dataDir, "feed_validators.json")
+}
+
+// SaveValidators persists the cache validators of the fetched feeds, from
+// Client.Validators, for the next conditional fetch.
+func (s *Storage) SaveValidators(validators map[string]CacheValidators) error {
+	data, err := json.MarshalIndent(validators, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed validators: %w", err)
+	}
+
+	if err := writeFileAtomic(s.validatorsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write feed validators file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadValidators reads the saved feed cache validators.
+// Returns an empty set if none have been saved yet.
+func (s *Storage) LoadValidators() (map[string]CacheValidators, error) {
+	data, err := os.ReadFile(s.validatorsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]CacheValidators{}, nil
+		}
+		return nil, fmt.Errorf("failed to read feed validators file: %w", err)
+	}
+
+	validators := map[string]CacheValidators{}
+	if err := json.Unmarshal(data, &validators); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal feed validators: %w", err)
+	}
+
+	return validators, nil
 }
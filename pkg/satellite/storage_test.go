@@ -0,0 +1,371 @@
+package satellite
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// testCatalog returns a catalog of n synthetic satellites with SATCAT data,
+// TLE history and a dropped-TLE count, to exercise every persisted field.
+func testCatalog(n int) *Catalog {
+	sats := syntheticCatalog(n)
+	sats[0].TLEHistory = []TLE{*sats[0].TLE}
+	sats[1].MissingFromFeed = true
+	return &Catalog{Satellites: sats, FetchedAt: testEpoch, DroppedTLEs: 3}
+}
+
+// catalogJSON returns the catalog encoded as JSON, to compare catalogs.
+func catalogJSON(t testing.TB, catalog *Catalog) string {
+	t.Helper()
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCatalogFileRoundTrip(t *testing.T) {
+	catalog := testCatalog(200)
+	want := catalogJSON(t, catalog)
+	dir := t.TempDir()
+
+	for _, name := range []string{"catalog.json", "catalog.gob", "catalog.json.gz", "catalog.GOB.gz"} {
+		path := filepath.Join(dir, name)
+		if err := SaveCatalogFile(path, catalog); err != nil {
+			t.Fatalf("SaveCatalogFile(%s): %v", name, err)
+		}
+		loaded, err := LoadCatalogFile(path)
+		if err != nil {
+			t.Fatalf("LoadCatalogFile(%s): %v", name, err)
+		}
+		if got := catalogJSON(t, loaded); got != want {
+			t.Errorf("%s round trip changed the catalog", name)
+		}
+		if !loaded.FetchedAt.Equal(testEpoch) || loaded.DroppedTLEs != 3 || len(loaded.Satellites[0].TLEHistory) != 1 || !loaded.Satellites[1].MissingFromFeed {
+			t.Errorf("%s: loaded %v with %d dropped, want the saved catalog", name, loaded.FetchedAt, loaded.DroppedTLEs)
+		}
+	}
+
+	// The gob file is not JSON, and a JSON reader rejects it
+	data, err := os.ReadFile(filepath.Join(dir, "catalog.gob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid(data) {
+		t.Error("catalog.gob holds JSON")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "misnamed.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCatalogFile(filepath.Join(dir, "misnamed.json")); err == nil {
+		t.Error("LoadCatalogFile read a gob file named .json")
+	}
+
+	if catalog, err := LoadCatalogFile(filepath.Join(dir, "missing.gob")); catalog != nil || err != nil {
+		t.Errorf("LoadCatalogFile of a missing file = %v, %v; want nil, nil", catalog, err)
+	}
+}
+
+func TestStorageCatalogFormat(t *testing.T) {
+	storage, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetCatalogFormat("xml"); err == nil {
+		t.Error("SetCatalogFormat accepted xml")
+	}
+	if err := storage.SetCatalogFormat(CatalogFormatGob); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(storage.CatalogPath()) != "catalog.gob" {
+		t.Errorf("gob catalog path = %s", storage.CatalogPath())
+	}
+
+	catalog := testCatalog(20)
+	if err := storage.Save(catalog); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := storage.Load()
+	if err != nil || loaded == nil {
+		t.Fatalf("Load = %v, %v", loaded, err)
+	}
+	if catalogJSON(t, loaded) != catalogJSON(t, catalog) {
+		t.Error("gob storage round trip changed the catalog")
+	}
+}
+
+func TestStorageFetchedAt(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetchedAt, err := storage.FetchedAt(); !fetchedAt.IsZero() || err != nil {
+		t.Errorf("FetchedAt without a catalog = %v, %v; want the zero time", fetchedAt, err)
+	}
+
+	catalog := testCatalog(20)
+	for _, tt := range []struct {
+		format   string
+		compress bool
+	}{{CatalogFormatJSON, false}, {CatalogFormatJSON, true}, {CatalogFormatGob, false}, {CatalogFormatGob, true}} {
+		if err := storage.SetCatalogFormat(tt.format); err != nil {
+			t.Fatal(err)
+		}
+		storage.SetCompress(tt.compress)
+		catalog.FetchedAt = catalog.FetchedAt.Add(time.Hour)
+		if err := storage.Save(catalog); err != nil {
+			t.Fatal(err)
+		}
+		if fetchedAt, err := storage.FetchedAt(); !fetchedAt.Equal(catalog.FetchedAt) || err != nil {
+			t.Errorf("%s, compressed %v: FetchedAt = %v, %v; want %v", tt.format, tt.compress, fetchedAt, err, catalog.FetchedAt)
+		}
+	}
+
+	// A catalog written by an earlier version, in the format Load falls back to
+	if err := os.Remove(storage.CatalogPath()); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "catalog.json"), `{"satellites": [], "fetched_at": "2024-03-01T12:00:00Z"}`)
+	if fetchedAt, err := storage.FetchedAt(); !fetchedAt.Equal(testEpoch.Add(12*time.Hour)) || err != nil {
+		t.Errorf("FetchedAt of a legacy catalog = %v, %v; want %v", fetchedAt, err, testEpoch.Add(12*time.Hour))
+	}
+}
+
+func TestStorageCompressedCatalog(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	// A legacy uncompressed catalog is loaded with compression on
+	legacy := testCatalog(20)
+	if err := SaveCatalogFile(filepath.Join(dir, "catalog.json"), legacy); err != nil {
+		t.Fatal(err)
+	}
+	storage.SetCompress(true)
+	if filepath.Base(storage.CatalogPath()) != "catalog.json.gz" {
+		t.Errorf("compressed catalog path = %s", storage.CatalogPath())
+	}
+	loaded, err := storage.Load()
+	if err != nil || loaded == nil || catalogJSON(t, loaded) != catalogJSON(t, legacy) {
+		t.Fatalf("Load of the legacy catalog = %v, %v", loaded, err)
+	}
+
+	// Saving compresses it and removes the legacy file
+	catalog := testCatalog(30)
+	if err := storage.Save(catalog); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if exists("catalog.json") || !exists("catalog.json.gz") {
+		t.Errorf("after a compressed save, catalog.json exists: %v, catalog.json.gz: %v; want only the latter",
+			exists("catalog.json"), exists("catalog.json.gz"))
+	}
+	data, err := os.ReadFile(storage.CatalogPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Error("catalog.json.gz is not gzip-compressed")
+	}
+	if loaded, err := storage.Load(); err != nil || loaded == nil || catalogJSON(t, loaded) != catalogJSON(t, catalog) {
+		t.Errorf("compressed round trip = %v, %v; want the saved catalog", loaded, err)
+	}
+
+	// Switching format and compression replaces the catalog again
+	storage.SetCompress(false)
+	if err := storage.SetCatalogFormat(CatalogFormatGob); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := storage.Load(); err != nil || loaded == nil || catalogJSON(t, loaded) != catalogJSON(t, catalog) {
+		t.Fatalf("Load of the compressed JSON catalog as gob storage = %v, %v", loaded, err)
+	}
+	if err := storage.Save(legacy); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"catalog.json", "catalog.json.gz", "catalog.gob.gz"} {
+		if exists(name) {
+			t.Errorf("%s left after saving catalog.gob", name)
+		}
+	}
+	if loaded, err := storage.Load(); err != nil || loaded == nil || catalogJSON(t, loaded) != catalogJSON(t, legacy) {
+		t.Errorf("gob round trip = %v, %v; want the saved catalog", loaded, err)
+	}
+	if !storage.Exists() {
+		t.Error("Exists is false with catalog.gob saved")
+	}
+}
+
+func TestSaveCatalogFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.json")
+	catalog := testCatalog(20)
+	want := catalogJSON(t, catalog)
+	if err := SaveCatalogFile(path, catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	// loadable checks that the original catalog is still at path and that
+	// no temporary file other than those listed is left behind
+	loadable := func(what string, leftovers ...string) {
+		t.Helper()
+		loaded, err := LoadCatalogFile(path)
+		if err != nil || loaded == nil || catalogJSON(t, loaded) != want {
+			t.Errorf("%s: catalog no longer loads as saved (%v)", what, err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if name := entry.Name(); name != "catalog.json" && name != "catalog.json.gz" && !slices.Contains(leftovers, name) {
+				t.Errorf("%s: left %s behind", what, name)
+			}
+		}
+	}
+
+	// A catalog that fails to encode is never written
+	bad := testCatalog(20)
+	bad.Satellites[3].Period = math.NaN()
+	if err := SaveCatalogFile(path, bad); err == nil {
+		t.Fatal("SaveCatalogFile encoded a NaN period")
+	}
+	loadable("failed encode")
+
+	// A crash part way through writing leaves a torn temporary file, which
+	// is not the catalog
+	full, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, ".catalog.json.tmp123"), string(full[:len(full)/2]))
+	loadable("torn write", ".catalog.json.tmp123")
+
+	// A failure to replace the file removes the temporary one
+	blocked := filepath.Join(dir, "catalog.json.gz")
+	if err := os.Mkdir(blocked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(blocked, "keep"), "")
+	if err := SaveCatalogFile(blocked, catalog); err == nil {
+		t.Error("SaveCatalogFile replaced a directory")
+	}
+	loadable("failed rename", ".catalog.json.tmp123")
+}
+
+func TestStorageSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetMaxSnapshots(-1); err == nil {
+		t.Error("SetMaxSnapshots accepted -1")
+	}
+	if err := storage.SetMaxSnapshots(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// Snapshots taken out of order and in both formats list oldest first
+	times := []time.Time{testEpoch.Add(2 * time.Hour), testEpoch, testEpoch.Add(time.Hour)}
+	for i, fetched := range times {
+		if i == 1 {
+			if err := storage.SetCatalogFormat(CatalogFormatGob); err != nil {
+				t.Fatal(err)
+			}
+		}
+		catalog := testCatalog(10)
+		catalog.FetchedAt = fetched
+		if err := storage.SaveSnapshot(catalog); err != nil {
+			t.Fatalf("SaveSnapshot(%v): %v", fetched, err)
+		}
+	}
+	for _, name := range []string{"catalog-20240301T000000Z.gob", "catalog-20240301T010000Z.gob", "catalog-20240301T020000Z.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("snapshot %s not created: %v", name, err)
+		}
+	}
+	listed, err := storage.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{testEpoch, testEpoch.Add(time.Hour), testEpoch.Add(2 * time.Hour)}; !slices.EqualFunc(listed, want, time.Time.Equal) {
+		t.Errorf("ListSnapshots = %v, want %v", listed, want)
+	}
+
+	// The current catalog and unrelated files are not snapshots
+	writeFile(t, filepath.Join(dir, "catalog.json"), "{}")
+	writeFile(t, filepath.Join(dir, "catalog-notes.json"), "{}")
+
+	// A fourth snapshot prunes the oldest
+	catalog := testCatalog(10)
+	catalog.FetchedAt = testEpoch.Add(3 * time.Hour)
+	if err := storage.SaveSnapshot(catalog); err != nil {
+		t.Fatal(err)
+	}
+	listed, _ = storage.ListSnapshots()
+	if want := []time.Time{testEpoch.Add(time.Hour), testEpoch.Add(2 * time.Hour), testEpoch.Add(3 * time.Hour)}; !slices.EqualFunc(listed, want, time.Time.Equal) {
+		t.Errorf("snapshots after pruning = %v, want %v", listed, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "catalog-20240301T000000Z.gob")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("oldest snapshot not pruned: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "catalog-notes.json")); err != nil {
+		t.Errorf("pruning removed an unrelated file: %v", err)
+	}
+
+	// LoadSnapshot returns the catalog as it was at a time
+	for _, tt := range []struct {
+		at   time.Time
+		want time.Time
+	}{
+		{testEpoch.Add(90 * time.Minute), testEpoch.Add(time.Hour)},
+		{testEpoch.Add(2 * time.Hour), testEpoch.Add(2 * time.Hour)},
+		{testEpoch.Add(24 * time.Hour), testEpoch.Add(3 * time.Hour)},
+	} {
+		snapshot, err := storage.LoadSnapshot(tt.at)
+		if err != nil || snapshot == nil || !snapshot.FetchedAt.Equal(tt.want) {
+			t.Errorf("LoadSnapshot(%v) = %v, %v; want the snapshot from %v", tt.at, snapshot, err, tt.want)
+		}
+	}
+	if snapshot, err := storage.LoadSnapshot(testEpoch.Add(30 * time.Minute)); snapshot != nil || err != nil {
+		t.Errorf("LoadSnapshot before the oldest = %v, %v; want nil, nil", snapshot, err)
+	}
+
+	// A catalog without a fetch time has no name to be saved under
+	if err := storage.SaveSnapshot(&Catalog{}); err == nil {
+		t.Error("SaveSnapshot accepted a catalog without a fetch time")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "catalog-00010101T000000Z.gob")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("snapshot of a zero fetch time created: %v", err)
+	}
+}
+
+func BenchmarkLoadCatalogFile(b *testing.B) {
+	catalog := testCatalog(20000)
+	dir := b.TempDir()
+	for _, name := range []string{"catalog.json", "catalog.gob"} {
+		path := filepath.Join(dir, name)
+		if err := SaveCatalogFile(path, catalog); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := LoadCatalogFile(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	earthRadiusKm = 6378.137      // WGS84 equatorial radius in km
+	auKm          = 149597870.700 // astronomical unit in km
+	sunRadiusKm   = 696000.0      // solar radius in km
+)
+
+// IlluminationState describes how much of the Sun a satellite can see.
+type IlluminationState string
+
+const (
+	IlluminationSunlit   IlluminationState = "sunlit"   // full solar disk visible
+	IlluminationPenumbra IlluminationState = "penumbra" // Earth partially covers the Sun
+	IlluminationUmbra    IlluminationState = "umbra"    // Earth fully covers the Sun
+)
+
+// sunPositionECI returns the Sun's position in km in an Earth-centered inertial
+// frame (mean equator and equinox of date) using the low-precision solar
+// ephemeris from the Astronomical Almanac (accurate to ~0.01°).
+func sunPositionECI(t time.Time) (x, y, z float64) {
+	n := julianDate(t) - 2451545.0
+
+	meanLon := math.Mod(280.460+0.9856474*n, 360.0)
+	meanAnomaly := math.Mod(357.528+0.9856003*n, 360.0) * math.Pi / 180.0
+
+	eclipticLon := (meanLon + 1.915*math.Sin(meanAnomaly) + 0.020*math.Sin(2*meanAnomaly)) * math.Pi / 180.0
+	obliquity := (23.439 - 0.0000004*n) * math.Pi / 180.0
+	distance := (1.00014 - 0.01671*math.Cos(meanAnomaly) - 0.00014*math.Cos(2*meanAnomaly)) * auKm
+
+	x = distance * math.Cos(eclipticLon)
+	y = distance * math.Cos(obliquity) * math.Sin(eclipticLon)
+	z = distance * math.Sin(obliquity) * math.Sin(eclipticLon)
+	return x, y, z
+}
+
+// sunPositionECEF returns the Sun's position in km in the Earth-fixed frame,
+// rotating the inertial position by Greenwich Mean Sidereal Time.
+func sunPositionECEF(t time.Time) (x, y, z float64) {
+	return rot3(gmst(t)).apply(sunPositionECI(t))
+}
+
+// SunObservationAngles returns the azimuth, elevation, and range of the Sun's
+// center as seen by the observer at t, without refraction.
+func SunObservationAngles(observer *ObserverPosition, t time.Time) *ObservationAngles {
+	x, y, z := sunPositionECEF(t)
+	return CalculateObservationAngles(&SatellitePosition{Time: t, X: x, Y: y, Z: z}, observer)
+}
+
+// SunElevation returns the elevation of the Sun's center in degrees as seen by
+// the observer at t, without refraction.
+func SunElevation(observer *ObserverPosition, t time.Time) float64 {
+	return SunObservationAngles(observer, t).Elevation
+}
+
+// Illumination returns the illumination state of an ECEF satellite position at
+// pos.Time using a conical shadow model: the apparent disks of the Sun and the
+// Earth as seen from the satellite are compared, so the penumbra is included.
+func Illumination(pos *SatellitePosition) IlluminationState {
+	sx, sy, sz := sunPositionECEF(pos.Time)
+	return illumination(pos, sx, sy, sz)
+}
+
+// illumination is Illumination with the Sun position given in the same frame
+// as pos. It is the one shadow model used throughout the package.
+func illumination(pos *SatellitePosition, sunX, sunY, sunZ float64) IlluminationState {
+	// Vectors from the satellite to the Sun and to the Earth's center
+	tsx, tsy, tsz := sunX-pos.X, sunY-pos.Y, sunZ-pos.Z
+	sunDist := math.Sqrt(tsx*tsx + tsy*tsy + tsz*tsz)
+	earthDist := math.Sqrt(pos.X*pos.X + pos.Y*pos.Y + pos.Z*pos.Z)
+
+	sunRadius := math.Asin(math.Min(1, sunRadiusKm/sunDist))
+	earthRadius := math.Asin(math.Min(1, earthRadiusKm/earthDist))
+
+	cosSep := -(tsx*pos.X + tsy*pos.Y + tsz*pos.Z) / (sunDist * earthDist)
+	separation := math.Acos(math.Max(-1, math.Min(1, cosSep)))
+
+	switch {
+	case separation >= sunRadius+earthRadius:
+		return IlluminationSunlit
+	case separation <= earthRadius-sunRadius:
+		return IlluminationUmbra
+	default:
+		return IlluminationPenumbra
+	}
+}
+
+// IsSunlit reports whether an ECEF satellite position receives any direct
+// sunlight at t, that is, it is not in the Earth's umbra.
+func IsSunlit(pos *SatellitePosition, t time.Time) bool {
+	sx, sy, sz := sunPositionECEF(t)
+	return illumination(pos, sx, sy, sz) != IlluminationUmbra
+}
+
+// EclipseFraction returns the fraction (0-1) of one orbital period, starting at t,
+// that the satellite spends in the Earth's umbra, where IsSunlit is false.
+// The period is derived from the TLE mean motion.
+func EclipseFraction(tle *TLE, t time.Time) (float64, error) {
+	if tle == nil {
+		return 0, fmt.Errorf("TLE is nil")
+	}
+
+	el, err := tle.elements()
+	if err != nil {
+		return 0, err
+	}
+	if el.MeanMotion <= 0 {
+		return 0, fmt.Errorf("invalid mean motion: %f", el.MeanMotion)
+	}
+
+	period := time.Duration(1440.0 / el.MeanMotion * float64(time.Minute))
+
+	const samples = 720
+	step := period / samples
+
+	shadowed := 0
+	for i := 0; i < samples; i++ {
+		st := t.Add(time.Duration(i) * step)
+		pos, err := propagateTEME(tle, st)
+		if err != nil {
+			return 0, fmt.Errorf("propagation failed at %v: %w", st, err)
+		}
+
+		sx, sy, sz := sunPositionECI(st)
+		if illumination(pos, sx, sy, sz) == IlluminationUmbra {
+			shadowed++
+		}
+	}
+
+	return float64(shadowed) / samples, nil
+}
@@ -0,0 +1,214 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// sunRightAscension returns the Sun's right ascension in degrees at testEpoch.
+func sunRightAscension() float64 {
+	x, y, _ := sunPositionECI(testEpoch)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+func TestEclipseFractionBetaAngle(t *testing.T) {
+	sunRA := sunRightAscension()
+
+	// A polar orbit whose plane contains the Sun direction (beta ~0°) crosses
+	// the shadow on every revolution, for over a third of a ~700 km orbit
+	lowBeta := makeTLE(10001, testEpoch, 90, sunRA, 0.0001, 0, 0, 14.5)
+	fraction, err := EclipseFraction(lowBeta, testEpoch)
+	if err != nil {
+		t.Fatalf("EclipseFraction(low beta): %v", err)
+	}
+	if fraction < 0.3 || fraction > 0.45 {
+		t.Errorf("low-beta eclipse fraction = %.3f, want 0.3-0.45", fraction)
+	}
+
+	// Turned 90° it rides the terminator (beta ~80°) and never enters shadow
+	highBeta := makeTLE(10002, testEpoch, 90, math.Mod(sunRA+90, 360), 0.0001, 0, 0, 14.5)
+	fraction, err = EclipseFraction(highBeta, testEpoch)
+	if err != nil {
+		t.Fatalf("EclipseFraction(high beta): %v", err)
+	}
+	if fraction != 0 {
+		t.Errorf("high-beta eclipse fraction = %.3f, want 0", fraction)
+	}
+
+	if _, err := EclipseFraction(nil, testEpoch); err == nil {
+		t.Error("EclipseFraction(nil) returned no error")
+	}
+}
+
+// geoLocalTimes returns the instants within a day of epoch when the Sun is
+// nearest to and farthest from the GEO satellite's meridian: local noon and
+// local midnight at the satellite.
+func geoLocalTimes(t *testing.T, tle *TLE, epoch time.Time) (noon, midnight time.Time) {
+	t.Helper()
+	best, worst := -2.0, 2.0
+	for minutes := 0; minutes < 24*60; minutes++ {
+		at := epoch.Add(time.Duration(minutes) * time.Minute)
+		pos, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sx, sy, _ := sunPositionECEF(at)
+		cos := (pos.X*sx + pos.Y*sy) / (math.Hypot(pos.X, pos.Y) * math.Hypot(sx, sy))
+		if cos > best {
+			best, noon = cos, at
+		}
+		if cos < worst {
+			worst, midnight = cos, at
+		}
+	}
+	return noon, midnight
+}
+
+func TestGEOIlluminationNoonAndMidnight(t *testing.T) {
+	tests := []struct {
+		name     string
+		epoch    time.Time
+		midnight IlluminationState
+	}{
+		// Near the equinox the Sun is in the equatorial plane and the Earth's
+		// shadow falls across the geostationary belt at local midnight
+		{"equinox", time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC), IlluminationUmbra},
+		// At the solstice the shadow passes 16,000 km south of it
+		{"solstice", time.Date(2024, time.June, 20, 0, 0, 0, 0, time.UTC), IlluminationSunlit},
+	}
+	for _, tt := range tests {
+		tle := makeTLE(40000, tt.epoch, 0.02, 0, 0.0001, 0, 0, 1.00273791)
+		noon, midnight := geoLocalTimes(t, tle, tt.epoch)
+		if d := midnight.Sub(noon).Abs(); d < 11*time.Hour || d > 13*time.Hour {
+			t.Fatalf("%s: local noon %v and midnight %v are not half a day apart", tt.name, noon, midnight)
+		}
+
+		for _, c := range []struct {
+			what string
+			at   time.Time
+			want IlluminationState
+		}{{"noon", noon, IlluminationSunlit}, {"midnight", midnight, tt.midnight}} {
+			pos, err := PropagateSatellite(tle, c.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Illumination(pos); got != c.want {
+				t.Errorf("%s %s: Illumination = %s, want %s", tt.name, c.what, got, c.want)
+			}
+			if IsSunlit(pos, c.at) != (c.want != IlluminationUmbra) {
+				t.Errorf("%s %s: IsSunlit = %v with illumination %s", tt.name, c.what, IsSunlit(pos, c.at), c.want)
+			}
+		}
+	}
+}
+
+func TestGEOEclipsePassesThroughPenumbra(t *testing.T) {
+	epoch := time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC)
+	tle := makeTLE(40000, epoch, 0.02, 0, 0.0001, 0, 0, 1.00273791)
+	_, midnight := geoLocalTimes(t, tle, epoch)
+
+	// Entering the shadow the satellite goes sunlit, penumbra, umbra, and
+	// EclipseFraction counts only the umbra, as IsSunlit does
+	var states []IlluminationState
+	umbra := 0
+	for at := midnight.Add(-2 * time.Hour); !at.After(midnight); at = at.Add(10 * time.Second) {
+		pos, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := Illumination(pos)
+		if len(states) == 0 || states[len(states)-1] != state {
+			states = append(states, state)
+		}
+	}
+	want := []IlluminationState{IlluminationSunlit, IlluminationPenumbra, IlluminationUmbra}
+	if len(states) != len(want) || states[0] != want[0] || states[1] != want[1] || states[2] != want[2] {
+		t.Errorf("illumination into eclipse went %v, want %v", states, want)
+	}
+
+	for at := epoch; at.Before(epoch.Add(24 * time.Hour)); at = at.Add(time.Minute) {
+		pos, err := PropagateSatellite(tle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsSunlit(pos, at) {
+			umbra++
+		}
+	}
+	fraction, err := EclipseFraction(tle, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Equinox GEO eclipses last up to ~70 minutes a day
+	assertNear(t, "eclipse fraction", fraction, float64(umbra)/(24*60), 0.005)
+	if fraction < 0.03 || fraction > 0.05 {
+		t.Errorf("equinox GEO eclipse fraction = %.3f, want about 70 min a day", fraction)
+	}
+}
+
+func TestSunElevationKnownSites(t *testing.T) {
+	tromso := &ObserverPosition{Latitude: 69.65, Longitude: 18.96}
+	greenwich := &ObserverPosition{Latitude: 51.48, Longitude: 0}
+	quito := &ObserverPosition{Latitude: -0.18, Longitude: -78.47, Altitude: 2850}
+
+	// At local solar noon and midnight the Sun is on the meridian, at an
+	// elevation of 90 - |lat - dec| and lat + dec - 90 respectively
+	tests := []struct {
+		name     string
+		observer *ObserverPosition
+		t        time.Time
+		want     float64
+		dark     bool // below civil twilight
+	}{
+		{"Tromsø midnight sun", tromso, time.Date(2024, 6, 20, 22, 46, 0, 0, time.UTC), 69.65 + 23.44 - 90, false},
+		{"Tromsø polar night noon", tromso, time.Date(2024, 12, 21, 10, 46, 0, 0, time.UTC), 90 - 69.65 - 23.44, false},
+		{"Greenwich winter midnight", greenwich, time.Date(2024, 12, 21, 0, 2, 0, 0, time.UTC), 51.48 - 23.44 - 90, true},
+		{"Greenwich summer noon", greenwich, time.Date(2024, 6, 20, 12, 2, 0, 0, time.UTC), 90 - 51.48 + 23.44, false},
+		{"Quito equinox noon", quito, time.Date(2024, 3, 20, 17, 21, 0, 0, time.UTC), 90 - 0.18, false},
+	}
+	for _, tt := range tests {
+		assertNear(t, tt.name+" Sun elevation", SunElevation(tt.observer, tt.t), tt.want, 0.5)
+		if dark := IsDark(tt.observer, tt.t, darkSunElevation); dark != tt.dark {
+			t.Errorf("%s: IsDark = %v, want %v", tt.name, dark, tt.dark)
+		}
+	}
+
+	// The polar night noon Sun is below the horizon but within civil twilight
+	noon := tests[1]
+	if !IsDark(noon.observer, noon.t, 0) || IsDark(noon.observer, noon.t, -6) {
+		t.Error("Tromsø polar night noon not between the horizon and civil twilight")
+	}
+}
+
+func TestSunObservationAnglesKnownSites(t *testing.T) {
+	greenwich := &ObserverPosition{Latitude: 51.48, Longitude: 0}
+	sydney := &ObserverPosition{Latitude: -33.87, Longitude: 151.21}
+
+	tests := []struct {
+		name      string
+		observer  *ObserverPosition
+		t         time.Time
+		azimuth   float64
+		elevation float64
+	}{
+		// On the meridian the Sun is due south of northern sites at noon and
+		// due north at midnight, and due north of Sydney at its summer noon
+		{"Greenwich summer noon", greenwich, time.Date(2024, 6, 20, 12, 2, 0, 0, time.UTC), 180, 90 - 51.48 + 23.44},
+		{"Greenwich winter midnight", greenwich, time.Date(2024, 12, 20, 23, 58, 0, 0, time.UTC), 0, 51.48 - 23.44 - 90},
+		{"Sydney summer noon", sydney, time.Date(2024, 12, 21, 1, 53, 0, 0, time.UTC), 0, 90 - 33.87 + 23.44},
+		// At the equinox its center rises due east and sets due west, a few
+		// minutes inside the published times, which allow for refraction
+		{"Greenwich equinox sunrise", greenwich, time.Date(2024, 3, 20, 6, 7, 0, 0, time.UTC), 90, 0},
+		{"Greenwich equinox sunset", greenwich, time.Date(2024, 3, 20, 18, 8, 0, 0, time.UTC), 270, 0},
+	}
+	for _, tt := range tests {
+		sun := SunObservationAngles(tt.observer, tt.t)
+		azError := math.Mod(sun.Azimuth-tt.azimuth+540, 360) - 180
+		assertNear(t, tt.name+" Sun azimuth error", azError, 0, 1)
+		assertNear(t, tt.name+" Sun elevation", sun.Elevation, tt.elevation, 0.5)
+		assertNear(t, tt.name+" Sun elevation vs SunElevation", sun.Elevation, SunElevation(tt.observer, tt.t), 1e-9)
+		// About an astronomical unit away
+		assertNear(t, tt.name+" Sun range (AU)", sun.Range/149597870.7, 1, 0.02)
+	}
+}
@@ -0,0 +1,271 @@
+package satellite
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrackExportOptions configures ExportTracks.
+type TrackExportOptions struct {
+	// Start, End, and Step set the track window of a new export. An export
+	// that resumes keeps the window it was started with.
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+
+	// Restart discards any partial export instead of resuming it.
+	Restart bool
+
+	// Progress, if non-nil, is called as satellites are exported.
+	Progress ProgressFunc
+}
+
+// TrackExportResult summarizes a run of ExportTracks.
+type TrackExportResult struct {
+	Resumed  bool          // a partial export was resumed
+	Start    time.Time     // track window actually used
+	End      time.Time     //
+	Step     time.Duration //
+	Skipped  int           // satellites already exported by an earlier run
+	Exported int           // satellites exported by this run
+	Failed   int           // satellites that failed to propagate, recorded as done
+}
+
+// TrackExportProgressPath returns the path of the sidecar file that records
+// the progress of an export to path.
+func TrackExportProgressPath(path string) string {
+	return path + ".progress"
+}
+
+// trackExportProgress is the sidecar file that lets an interrupted export
+// resume. The first line records the export window; each following line
+// records a completed satellite and the size of the output file after its
+// rows, so a partially written satellite can be truncated away on resume.
+type trackExportProgress struct {
+	file   *os.File
+	start  time.Time
+	end    time.Time
+	step   time.Duration
+	done   map[int]bool
+	offset int64
+}
+
+// loadTrackExportProgress reads the sidecar at path. Returns nil without
+// error if there is none.
+func loadTrackExportProgress(path string) (*trackExportProgress, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only newline-terminated lines are complete; a torn last line from a
+	// crash is dropped and its satellite redone
+	lines := strings.Split(string(data), "\n")
+	lines = lines[:len(lines)-1]
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%s: missing export window", path)
+	}
+
+	progress := &trackExportProgress{done: make(map[int]bool)}
+	fields := strings.Fields(lines[0])
+	if len(fields) != 4 || fields[0] != "window" {
+		return nil, fmt.Errorf("%s: malformed export window: %q", path, lines[0])
+	}
+	if progress.start, err = time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if progress.end, err = time.Parse(time.RFC3339Nano, fields[2]); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if progress.step, err = time.ParseDuration(fields[3]); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, line := range lines[1:] {
+		var id int
+		var offset int64
+		if _, err := fmt.Sscanf(line, "%d %d", &id, &offset); err != nil {
+			return nil, fmt.Errorf("%s: malformed progress line: %q", path, line)
+		}
+		progress.done[id] = true
+		progress.offset = offset
+	}
+
+	return progress, nil
+}
+
+// markDone records that a satellite's rows are complete and the output is
+// offset bytes long.
+func (p *trackExportProgress) markDone(noradID int, offset int64) error {
+	if _, err := fmt.Fprintf(p.file, "%d %d\n", noradID, offset); err != nil {
+		return err
+	}
+	p.done[noradID] = true
+	p.offset = offset
+	return p.file.Sync()
+}
+
+// openTrackExport opens the output and sidecar for an export to path,
+// resuming a partial export if one is recorded.
+func openTrackExport(path string, opts TrackExportOptions) (*os.File, *trackExportProgress, bool, error) {
+	progressPath := TrackExportProgressPath(path)
+	if opts.Restart {
+		if err := os.Remove(progressPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, false, err
+		}
+	}
+
+	progress, err := loadTrackExportProgress(progressPath)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to read export progress: %w", err)
+	}
+
+	if progress != nil {
+		// Resume: drop any rows written after the last completed satellite
+		output, err := os.OpenFile(path, os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if err := output.Truncate(progress.offset); err != nil {
+			output.Close()
+			return nil, nil, false, err
+		}
+		if _, err := output.Seek(progress.offset, io.SeekStart); err != nil {
+			output.Close()
+			return nil, nil, false, err
+		}
+		if progress.file, err = os.OpenFile(progressPath, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+			output.Close()
+			return nil, nil, false, err
+		}
+		return output, progress, true, nil
+	}
+
+	progress = &trackExportProgress{
+		start: opts.Start,
+		end:   opts.End,
+		step:  opts.Step,
+		done:  make(map[int]bool),
+	}
+
+	output, err := os.Create(path)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	w := csv.NewWriter(output)
+	if err := w.Write(TrackCSVHeader); err != nil {
+		output.Close()
+		return nil, nil, false, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		output.Close()
+		return nil, nil, false, err
+	}
+	if progress.offset, err = output.Seek(0, io.SeekCurrent); err != nil {
+		output.Close()
+		return nil, nil, false, err
+	}
+
+	if progress.file, err = os.Create(progressPath); err != nil {
+		output.Close()
+		return nil, nil, false, err
+	}
+	if _, err := fmt.Fprintf(progress.file, "window %s %s %s\n",
+		progress.start.Format(time.RFC3339Nano), progress.end.Format(time.RFC3339Nano), progress.step); err != nil {
+		output.Close()
+		progress.file.Close()
+		return nil, nil, false, err
+	}
+	return output, progress, false, nil
+}
+
+// ExportTracks writes the track of every satellite with a TLE to a CSV file
+// at path, as WriteTrackCSV, in order of NORAD ID. Progress is recorded in a
+// sidecar file (see TrackExportProgressPath) after each satellite, so that if
+// the export fails or ctx is canceled, calling ExportTracks again for the same
+// path resumes where it stopped, using the original window. The sidecar is
+// removed once the export completes. Satellites that fail to propagate are
+// counted in Failed and not retried.
+//
+// On cancellation the result so far is returned along with ctx.Err().
+func ExportTracks(ctx context.Context, path string, satellites []*Satellite, opts TrackExportOptions) (*TrackExportResult, error) {
+	if opts.Step <= 0 {
+		return nil, fmt.Errorf("step must be positive: %v", opts.Step)
+	}
+	if opts.End.Before(opts.Start) {
+		return nil, fmt.Errorf("end time must not be before start time")
+	}
+
+	output, progress, resumed, err := openTrackExport(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Close()
+	defer progress.file.Close()
+
+	result := &TrackExportResult{
+		Resumed: resumed,
+		Start:   progress.start,
+		End:     progress.end,
+		Step:    progress.step,
+		Skipped: len(progress.done),
+	}
+
+	var pending []*Satellite
+	for _, sat := range satellites {
+		if sat.TLE != nil && !progress.done[sat.NoradID] {
+			pending = append(pending, sat)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].NoradID < pending[j].NoradID })
+
+	counter := newProgressCounter(len(pending), opts.Progress)
+	w := csv.NewWriter(output)
+
+	for _, sat := range pending {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := WriteTrackCSV(w, sat, progress.start, progress.end, progress.step); err != nil {
+			// Propagation failures are permanent; record the satellite as done
+			result.Failed++
+		} else {
+			result.Exported++
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return result, err
+		}
+		if err := output.Sync(); err != nil {
+			return result, err
+		}
+		offset, err := output.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return result, err
+		}
+		if err := progress.markDone(sat.NoradID, offset); err != nil {
+			return result, fmt.Errorf("failed to record progress: %w", err)
+		}
+		counter.step()
+	}
+	counter.finish()
+
+	progress.file.Close()
+	if err := os.Remove(TrackExportProgressPath(path)); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportTracksResume(t *testing.T) {
+	satellites := []*Satellite{
+		testSatellite("GEO", geoTLE()),
+		testSatellite("ISS", issTLE()),
+		testSatellite("LEO A", makeTLE(30000, testEpoch, 98, 10, 0.001, 0, 0, 14.5)),
+		testSatellite("LEO B", makeTLE(30001, testEpoch, 53, 20, 0.001, 0, 90, 15.1)),
+		testSatellite("DECAYED", makeTLE(30002, testEpoch, 53, 20, 0.15, 0, 0, 14)),
+		{NoradID: 30003, Name: "NO TLE"},
+	}
+	opts := TrackExportOptions{Start: testEpoch, End: testEpoch.Add(10 * time.Minute), Step: time.Minute}
+	dir := t.TempDir()
+
+	// An uninterrupted export for reference
+	want := filepath.Join(dir, "want.csv")
+	result, err := ExportTracks(context.Background(), want, satellites, opts)
+	if err != nil {
+		t.Fatalf("ExportTracks: %v", err)
+	}
+	if result.Exported != 4 || result.Failed != 1 || result.Resumed {
+		t.Errorf("result = %+v, want 4 exported and 1 failed", result)
+	}
+	if _, err := os.Stat(TrackExportProgressPath(want)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("progress file left after a complete export: %v", err)
+	}
+
+	// Interrupt after two satellites
+	got := filepath.Join(dir, "got.csv")
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := opts
+	interrupted.Progress = func(fraction float64) {
+		if fraction >= 0.4 {
+			cancel()
+		}
+	}
+	result, err = ExportTracks(ctx, got, satellites, interrupted)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted ExportTracks = %v, want context.Canceled", err)
+	}
+	if result.Exported != 2 {
+		t.Errorf("exported %d satellites before the interruption, want 2", result.Exported)
+	}
+
+	// A crash mid-satellite leaves torn rows and a torn progress line
+	appendFile(t, got, "30001,2024-03-01T00:00:00Z,12")
+	appendFile(t, TrackExportProgressPath(got), "30001 99")
+
+	// The resumed export keeps the original window even if asked for another
+	resumed := opts
+	resumed.Start = opts.Start.Add(time.Hour)
+	resumed.End = opts.End.Add(time.Hour)
+	result, err = ExportTracks(context.Background(), got, satellites, resumed)
+	if err != nil {
+		t.Fatalf("resumed ExportTracks: %v", err)
+	}
+	if !result.Resumed || result.Skipped != 2 || result.Exported != 2 || result.Failed != 1 {
+		t.Errorf("resumed result = %+v, want 2 skipped, 2 exported and 1 failed", result)
+	}
+	if !result.Start.Equal(opts.Start) || !result.End.Equal(opts.End) || result.Step != opts.Step {
+		t.Errorf("resumed window %v-%v/%v, want the original %v-%v/%v",
+			result.Start, result.End, result.Step, opts.Start, opts.End, opts.Step)
+	}
+
+	if a, b := readFile(t, got), readFile(t, want); a != b {
+		t.Errorf("resumed export differs from an uninterrupted one:\n%s\nwant:\n%s", a, b)
+	}
+	if _, err := os.Stat(TrackExportProgressPath(got)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("progress file left after the resumed export completed: %v", err)
+	}
+}
+
+func TestExportTracksRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracks.csv")
+	satellites := []*Satellite{testSatellite("ISS", issTLE())}
+	opts := TrackExportOptions{Start: testEpoch, End: testEpoch, Step: time.Minute}
+
+	// A stale progress file from another export is discarded with Restart
+	writeFile(t, TrackExportProgressPath(path), "window 2000-01-01T00:00:00Z 2000-01-01T00:00:00Z 1m0s\n25544 10\n")
+	writeFile(t, path, "junk")
+	opts.Restart = true
+	result, err := ExportTracks(context.Background(), path, satellites, opts)
+	if err != nil {
+		t.Fatalf("ExportTracks: %v", err)
+	}
+	if result.Resumed || result.Exported != 1 || !result.Start.Equal(testEpoch) {
+		t.Errorf("restarted result = %+v, want a fresh export of 1 satellite", result)
+	}
+
+	writeFile(t, TrackExportProgressPath(path), "garbage\n")
+	opts.Restart = false
+	if _, err := ExportTracks(context.Background(), path, satellites, opts); err == nil {
+		t.Error("ExportTracks resumed from a malformed progress file")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,9 @@
 package satellite
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -11,31 +14,311 @@ import (
 
 // TLE represents a Two-Line Element set entry (two lines of text)
 type TLE struct {
+	Name  string `json:"name,omitempty"` // from the name line of 3LE input; empty for 2LE
 	Line1 string `json:"line1"`
 	Line2 string `json:"line2"`
+
+	// el and elErr cache the result of parsing Line2 once parsed is set by
+	// cacheElements; Line2 must not change afterwards
+	el     *tleElements
+	elErr  error
+	parsed bool
 }
 
-// GetNoradID extracts the NORAD catalog number from the TLE
+// GetNoradID extracts the NORAD catalog number from the TLE.
+// Returns 0 if the catalog number cannot be parsed; use GetNoradIDErr to see why.
 func (t *TLE) GetNoradID() int {
-	// NORAD catalog number is in columns 3-7 of line 1 (after "1 ")
-	if len(t.Line1) < 7 {
+	noradID, err := t.GetNoradIDErr()
+	if err != nil {
 		return 0
 	}
+	return noradID
+}
 
-	// Extract the catalog number (typically "1 00005U" -> "00005")
-	parts := strings.Fields(t.Line1)
-	if len(parts) < 2 {
-		return 0
+// GetNoradIDErr extracts the NORAD catalog number from columns 3-7 of line 1.
+// Supports the Alpha-5 scheme for catalog numbers above 99999, where the first
+// column is a letter (A=10 ... Z=33, skipping I and O), e.g. "A0001" = 100001.
+func (t *TLE) GetNoradIDErr() (int, error) {
+	line := t.Line1
+	if len(line) < 7 {
+		return 0, fmt.Errorf("TLE line 1 too short for catalog number: %q", line)
+	}
+	if !strings.HasPrefix(line, "1 ") {
+		return 0, fmt.Errorf("TLE line 1 must start with \"1 \": %q", line)
+	}
+
+	field := strings.TrimSpace(line[2:7])
+	if field == "" {
+		return 0, fmt.Errorf("TLE catalog number is blank")
+	}
+
+	// Alpha-5: leading letter encodes the ten-thousands digit(s)
+	prefix := 0
+	if c := field[0]; c >= 'A' && c <= 'Z' {
+		if c == 'I' || c == 'O' {
+			return 0, fmt.Errorf("invalid Alpha-5 catalog number %q", field)
+		}
+		prefix = int(c-'A') + 10
+		if c > 'I' {
+			prefix--
+		}
+		if c > 'O' {
+			prefix--
+		}
+		if len(field) != 5 {
+			return 0, fmt.Errorf("invalid Alpha-5 catalog number %q", field)
+		}
+		field = field[1:]
 	}
 
-	// Remove trailing 'U' or 'C' classification
-	numStr := strings.TrimRight(parts[1], "UC")
-	noradID, err := strconv.Atoi(numStr)
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid catalog number %q", strings.TrimSpace(line[2:7]))
+	}
+
+	return prefix*10000 + n, nil
+}
+
+// GetEpoch decodes the TLE epoch from columns 19-32 of line 1 (two-digit year
+// plus fractional day of year) into a UTC time, rounded to the microsecond.
+// Years 57-99 are 1957-1999 and 00-56 are 2000-2056. Errors wrap ErrTLEParse.
+func (t *TLE) GetEpoch() (time.Time, error) {
+	if len(t.Line1) < 32 || !strings.HasPrefix(t.Line1, "1 ") {
+		return time.Time{}, fmt.Errorf("%w: malformed TLE line 1: %q", ErrTLEParse, t.Line1)
+	}
+
+	yearStr := strings.TrimSpace(t.Line1[18:20])
+	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		return 0
+		return time.Time{}, fmt.Errorf("%w: invalid epoch year %q", ErrTLEParse, yearStr)
+	}
+	// Conventional TLE pivot: 57-99 => 1900s, 00-56 => 2000s
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
 	}
 
-	return noradID
+	dayStr := strings.TrimSpace(t.Line1[20:32])
+	day, err := strconv.ParseFloat(dayStr, 64)
+	if err != nil || day < 1 || day >= 367 {
+		return time.Time{}, fmt.Errorf("%w: invalid epoch day %q", ErrTLEParse, dayStr)
+	}
+
+	offset := time.Duration((day - 1) * 24 * float64(time.Hour)).Round(time.Microsecond)
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Add(offset), nil
+}
+
+// TLEAge returns how long ago a TLE epoch was, relative to the current time.
+// Negative values mean the epoch is in the future.
+func TLEAge(epoch time.Time) time.Duration {
+	return time.Since(epoch)
+}
+
+// tleElements holds the mean orbital elements encoded in TLE line 2
+type tleElements struct {
+	Inclination  float64 // degrees
+	RAAN         float64 // degrees
+	Eccentricity float64 // dimensionless
+	ArgPerigee   float64 // degrees
+	MeanAnomaly  float64 // degrees
+	MeanMotion   float64 // revolutions per day
+}
+
+// parseTLEField parses a fixed-width TLE field spanning the given 1-indexed columns (inclusive).
+func parseTLEField(line string, startCol, endCol int) (float64, error) {
+	if len(line) < endCol {
+		return 0, fmt.Errorf("line too short for columns %d-%d", startCol, endCol)
+	}
+	field := strings.TrimSpace(line[startCol-1 : endCol])
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in columns %d-%d", field, startCol, endCol)
+	}
+	return v, nil
+}
+
+// elements returns the mean orbital elements from line 2, from the cache if
+// cacheElements has filled it.
+func (t *TLE) elements() (*tleElements, error) {
+	if t.parsed {
+		return t.el, t.elErr
+	}
+	return t.parseElements()
+}
+
+// cacheElements parses line 2 once so later calls to elements reuse the
+// result. It writes to t, so call it before sharing t between goroutines.
+func (t *TLE) cacheElements() {
+	t.el, t.elErr = t.parseElements()
+	t.parsed = true
+}
+
+// parseElements parses the mean orbital elements from the fixed columns of line 2.
+func (t *TLE) parseElements() (*tleElements, error) {
+	line := t.Line2
+	if len(line) < 63 || !strings.HasPrefix(line, "2 ") {
+		return nil, fmt.Errorf("malformed TLE line 2: %q", line)
+	}
+
+	var el tleElements
+	var err error
+	if el.Inclination, err = parseTLEField(line, 9, 16); err != nil {
+		return nil, fmt.Errorf("inclination: %w", err)
+	}
+	if el.RAAN, err = parseTLEField(line, 18, 25); err != nil {
+		return nil, fmt.Errorf("RAAN: %w", err)
+	}
+
+	// Eccentricity has an implied leading decimal point
+	eccStr := strings.TrimSpace(line[26:33])
+	ecc, err := strconv.ParseFloat("0."+eccStr, 64)
+	if err != nil || eccStr == "" {
+		return nil, fmt.Errorf("eccentricity: invalid value %q in columns 27-33", eccStr)
+	}
+	el.Eccentricity = ecc
+
+	if el.ArgPerigee, err = parseTLEField(line, 35, 42); err != nil {
+		return nil, fmt.Errorf("argument of perigee: %w", err)
+	}
+	if el.MeanAnomaly, err = parseTLEField(line, 44, 51); err != nil {
+		return nil, fmt.Errorf("mean anomaly: %w", err)
+	}
+	if el.MeanMotion, err = parseTLEField(line, 53, 63); err != nil {
+		return nil, fmt.Errorf("mean motion: %w", err)
+	}
+
+	return &el, nil
+}
+
+// parsedElements parses line 2 and wraps any failure in ErrTLEParse
+func (t *TLE) parsedElements() (*tleElements, error) {
+	el, err := t.elements()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTLEParse, err)
+	}
+	return el, nil
+}
+
+// GetInclination returns the inclination in degrees from columns 9-16 of line 2.
+func (t *TLE) GetInclination() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.Inclination, nil
+}
+
+// GetRAAN returns the right ascension of the ascending node in degrees from
+// columns 18-25 of line 2.
+func (t *TLE) GetRAAN() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.RAAN, nil
+}
+
+// GetEccentricity returns the eccentricity from columns 27-33 of line 2,
+// applying the implied leading decimal point.
+func (t *TLE) GetEccentricity() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.Eccentricity, nil
+}
+
+// GetArgPerigee returns the argument of perigee in degrees from columns 35-42 of line 2.
+func (t *TLE) GetArgPerigee() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.ArgPerigee, nil
+}
+
+// GetMeanAnomaly returns the mean anomaly in degrees from columns 44-51 of line 2.
+func (t *TLE) GetMeanAnomaly() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.MeanAnomaly, nil
+}
+
+// GetMeanMotion returns the mean motion in revolutions per day from columns 53-63 of line 2.
+func (t *TLE) GetMeanMotion() (float64, error) {
+	el, err := t.parsedElements()
+	if err != nil {
+		return 0, err
+	}
+	return el.MeanMotion, nil
+}
+
+// checkTLELine checks that a trimmed TLE line is present, starts with its line
+// number and a space, and has at least minLen characters.
+func checkTLELine(line string, number, minLen int) error {
+	switch {
+	case line == "":
+		return fmt.Errorf("TLE line %d is empty", number)
+	case !strings.HasPrefix(line, strconv.Itoa(number)+" "):
+		return fmt.Errorf("TLE line %d must start with \"%d \": %q", number, number, line)
+	case len(line) < minLen:
+		return fmt.Errorf("TLE line %d is too short (%d characters, need at least %d): %q", number, len(line), minLen, line)
+	}
+	return nil
+}
+
+// sgp4Lines checks every field the SGP4 library parses, using the same column
+// slices, and returns lines that are safe to hand to it. The library exits the
+// process on malformed numbers rather than returning an error.
+// Surrounding whitespace, such as a trailing carriage return, is removed first.
+// Alpha-5 catalog numbers, which the library cannot parse, are replaced with
+// zeros; the catalog number does not affect propagation.
+func (t *TLE) sgp4Lines() (string, string, error) {
+	line1 := strings.TrimSpace(t.Line1)
+	line2 := strings.TrimSpace(t.Line2)
+	if err := checkTLELine(line1, 1, 61); err != nil {
+		return "", "", err
+	}
+	if err := checkTLELine(line2, 2, 63); err != nil {
+		return "", "", err
+	}
+
+	if _, err := strconv.ParseInt(strings.TrimSpace(line1[2:7]), 10, 0); err != nil {
+		if _, err := t.GetNoradIDErr(); err != nil {
+			return "", "", err
+		}
+		line1 = line1[:2] + "00000" + line1[7:]
+	}
+	if _, err := strconv.ParseInt(line1[18:20], 10, 0); err != nil {
+		return "", "", fmt.Errorf("invalid epoch year %q", line1[18:20])
+	}
+
+	squeeze := func(s string) string { return strings.Replace(s, " ", "", 2) }
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"epoch day", line1[20:32]},
+		{"first derivative of mean motion", squeeze(line1[33:43])},
+		{"second derivative of mean motion", squeeze(line1[44:45] + "." + line1[45:50] + "e" + line1[50:52])},
+		{"BSTAR", squeeze(line1[53:54] + "." + line1[54:59] + "e" + line1[59:61])},
+		{"inclination", squeeze(line2[8:16])},
+		{"RAAN", squeeze(line2[17:25])},
+		{"eccentricity", "." + line2[26:33]},
+		{"argument of perigee", squeeze(line2[34:42])},
+		{"mean anomaly", squeeze(line2[43:51])},
+		{"mean motion", squeeze(line2[52:63])},
+	}
+	for _, f := range fields {
+		if _, err := strconv.ParseFloat(f.value, 64); err != nil {
+			return "", "", fmt.Errorf("invalid %s %q", f.name, f.value)
+		}
+	}
+
+	return line1, line2, nil
 }
 
 // SATCAT represents a Satellite Catalog entry
@@ -56,28 +339,176 @@ type SATCAT struct {
 	RCSSize     string  `json:"rcsSize"`
 }
 
+// satcatDateLayouts lists the date formats emitted in SATCAT date fields,
+// tried in order by ParseSATCATDate.
+var satcatDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01",
+	"2006",
+}
+
+// ParseSATCATDate parses a SATCAT date field (e.g. LaunchDate, DecayDate) into a UTC time.
+// Returns ok=false for blank or unrecognized values.
+func ParseSATCATDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range satcatDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
 // Catalog represents the stored satellite catalog data
 type Catalog struct {
-	Satellites []*Satellite `json:"satellites"`
-	FetchedAt  time.Time    `json:"fetched_at"`
+	Satellites  []*Satellite `json:"satellites"`
+	FetchedAt   time.Time    `json:"fetchedAt"`
+	DroppedTLEs int          `json:"droppedTles,omitempty"` // TLEs dropped during merge for an unparseable NORAD ID
+}
+
+// UnmarshalJSON decodes a catalog, also accepting the "fetched_at" key written
+// by earlier versions.
+func (c *Catalog) UnmarshalJSON(data []byte) error {
+	type catalog Catalog
+	aux := struct {
+		*catalog
+		LegacyFetchedAt time.Time `json:"fetched_at"`
+	}{catalog: (*catalog)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if c.FetchedAt.IsZero() {
+		c.FetchedAt = aux.LegacyFetchedAt
+	}
+	return nil
+}
+
+// Validate checks that no two satellites share a NORAD ID, which catalog
+// stores look satellites up by.
+func (c *Catalog) Validate() error {
+	seen := make(map[int]bool, len(c.Satellites))
+	for _, sat := range c.Satellites {
+		if seen[sat.NoradID] {
+			return fmt.Errorf("catalog has more than one satellite with NORAD ID %d", sat.NoradID)
+		}
+		seen[sat.NoradID] = true
+	}
+	return nil
 }
 
 // Satellite represents a merged view of TLE and SATCAT data
 type Satellite struct {
-	NoradID     int     `json:"noradId"`
-	Name        string  `json:"name"`
-	IntlID      string  `json:"intlId"`
-	ObjectType  string  `json:"objectType"`
-	Owner       string  `json:"owner"`
-	LaunchDate  string  `json:"launchDate"`
-	DecayDate   string  `json:"decayDate"`
-	LaunchSite  string  `json:"launchSite"`
-	Period      float64 `json:"period"`
-	Inclination float64 `json:"inclination"`
-	Apogee      float64 `json:"apogee"`
-	Perigee     float64 `json:"perigee"`
-	RCSSize     string  `json:"rcsSize"`
-	OrbitRegime string  `json:"orbitRegime"` // LEO, MEO, GEO, HEO, or UNKNOWN
-	TLE         *TLE    `json:"tle"`
-	SATCAT      *SATCAT `json:"satcat"`
+	NoradID     int       `json:"noradId"`
+	Name        string    `json:"name"`
+	IntlID      string    `json:"intlId"`
+	ObjectType  string    `json:"objectType"`
+	Owner       string    `json:"owner"`
+	LaunchDate  string    `json:"launchDate"`
+	DecayDate   string    `json:"decayDate"`
+	LaunchSite  string    `json:"launchSite"`
+	Period      float64   `json:"period"`
+	Inclination float64   `json:"inclination"`
+	Apogee      float64   `json:"apogee"`
+	Perigee     float64   `json:"perigee"`
+	RCSSize     string    `json:"rcsSize"`
+	OrbitRegime string    `json:"orbitRegime"` // LEO, MEO, GEO, HEO, or UNKNOWN
+	TLEEpoch    time.Time `json:"tleEpoch"`    // epoch of the TLE element set
+	FetchedAt   time.Time `json:"fetchedAt"`   // when this satellite's data was fetched
+	TLE         *TLE      `json:"tle"`
+	SATCAT      *SATCAT   `json:"satcat"`
+
+	TLEHistory []TLE `json:"tleHistory,omitempty"` // every TLE in the feed, oldest first (history mode only)
+
+	TLEUpdatedAt    time.Time `json:"tleUpdatedAt"`              // when this satellite's TLE was last fetched
+	MissingFromFeed bool      `json:"missingFromFeed,omitempty"` // absent from the last TLE-only update (see UpdateTLEs)
+}
+
+// TLEAt returns the TLE from the satellite's history with the latest epoch
+// not after t, or the oldest one if all are after t. Without a history it
+// returns the satellite's TLE.
+func (s *Satellite) TLEAt(t time.Time) *TLE {
+	if len(s.TLEHistory) == 0 {
+		return s.TLE
+	}
+	best := &s.TLEHistory[0]
+	for i := 1; i < len(s.TLEHistory); i++ {
+		epoch, err := s.TLEHistory[i].GetEpoch()
+		if err != nil || epoch.After(t) {
+			break
+		}
+		best = &s.TLEHistory[i]
+	}
+	return best
+}
+
+// OrbitalPeriod returns the satellite's orbital period in minutes.
+// Uses the SATCAT period when available, otherwise derives it from the TLE mean motion.
+// Returns 0 if neither source is available.
+func (s *Satellite) OrbitalPeriod() float64 {
+	if s.Period > 0 {
+		return s.Period
+	}
+	if s.TLE == nil {
+		return 0
+	}
+	el, err := s.TLE.elements()
+	if err != nil || el.MeanMotion <= 0 {
+		return 0
+	}
+	return 1440.0 / el.MeanMotion
+}
+
+// orbitSemiMajorAxis returns the semi-major axis in km of an orbit with the
+// given apogee and perigee altitudes (km), measured from the mean Earth radius
+// as SATCAT does.
+func orbitSemiMajorAxis(apogee, perigee float64) float64 {
+	return (apogee+perigee)/2.0 + meanEarthRadiusKm
+}
+
+// orbitEccentricity returns the eccentricity of an orbit with the given
+// apogee and perigee altitudes (km).
+func orbitEccentricity(apogee, perigee float64) float64 {
+	return (apogee - perigee) / (apogee + perigee + 2*meanEarthRadiusKm)
+}
+
+// SemiMajorAxis returns the satellite's semi-major axis in km, from its SATCAT
+// apogee and perigee or, without SATCAT data, the TLE mean motion.
+// Returns NaN if neither source is available.
+func (s *Satellite) SemiMajorAxis() float64 {
+	apogee, perigee, _, _, ok := satelliteOrbit(s)
+	if !ok {
+		return math.NaN()
+	}
+	return orbitSemiMajorAxis(apogee, perigee)
+}
+
+// Eccentricity returns the satellite's orbital eccentricity, from its SATCAT
+// apogee and perigee or, without SATCAT data, the TLE.
+// Returns NaN if neither source is available, so a missing orbit is not
+// mistaken for a circular one.
+func (s *Satellite) Eccentricity() float64 {
+	apogee, perigee, _, _, ok := satelliteOrbit(s)
+	if !ok {
+		return math.NaN()
+	}
+	return orbitEccentricity(apogee, perigee)
+}
+
+// MeanAltitude returns the satellite's mean altitude in km, the semi-major
+// axis less the mean Earth radius. Returns NaN if no orbit data is available.
+func (s *Satellite) MeanAltitude() float64 {
+	apogee, perigee, _, _, ok := satelliteOrbit(s)
+	if !ok {
+		return math.NaN()
+	}
+	return (apogee + perigee) / 2.0
 }
@@ -0,0 +1,235 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseSATCATDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"1998-11-20", time.Date(1998, 11, 20, 0, 0, 0, 0, time.UTC)},
+		{"2021-05-04T12:30:45Z", time.Date(2021, 5, 4, 12, 30, 45, 0, time.UTC)},
+		{"2021-05-04T12:30:45.25+02:00", time.Date(2021, 5, 4, 10, 30, 45, 250000000, time.UTC)},
+		{"2021-05-04T12:30:45", time.Date(2021, 5, 4, 12, 30, 45, 0, time.UTC)},
+		{"2021-05-04 12:30:45", time.Date(2021, 5, 4, 12, 30, 45, 0, time.UTC)},
+		{"2021-05", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"1957", time.Date(1957, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"  1998-11-20  ", time.Date(1998, 11, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSATCATDate(tt.in)
+		if !ok || !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("ParseSATCATDate(%q) = %v, %v; want %v, true", tt.in, got, ok, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "   ", "20/11/1998", "not a date", "1998-13-01"} {
+		if got, ok := ParseSATCATDate(in); ok {
+			t.Errorf("ParseSATCATDate(%q) = %v, true; want false", in, got)
+		}
+	}
+}
+
+func TestGetNoradIDErr(t *testing.T) {
+	valid := []struct {
+		line1 string
+		want  int
+	}{
+		{"1 25544U 98067A   08264.51782528", 25544},
+		{"1 00005U 58002B   24061.00000000", 5},
+		{"1 00000U 24001A   24061.00000000", 0},
+		{"1     5U 58002B   24061.00000000", 5},
+		{"1 A0001U 24001A   24061.00000000", 100001},
+		{"1 H9999U 24001A   24061.00000000", 179999},
+		{"1 J0000U 24001A   24061.00000000", 180000},
+		{"1 P0000U 24001A   24061.00000000", 230000},
+		{"1 Z9999U 24001A   24061.00000000", 339999},
+	}
+	for _, tt := range valid {
+		tle := TLE{Line1: tt.line1}
+		got, err := tle.GetNoradIDErr()
+		if err != nil || got != tt.want {
+			t.Errorf("GetNoradIDErr(%q) = %d, %v; want %d", tt.line1, got, err, tt.want)
+		}
+		if got := tle.GetNoradID(); got != tt.want {
+			t.Errorf("GetNoradID(%q) = %d, want %d", tt.line1, got, tt.want)
+		}
+	}
+
+	malformed := []struct {
+		name  string
+		line1 string
+	}{
+		{"empty", ""},
+		{"short", "1 255"},
+		{"wrong line number", "2 25544U 98067A   08264.51782528"},
+		{"blank", "1      U 98067A   08264.51782528"},
+		{"non-numeric", "1 25X44U 98067A   08264.51782528"},
+		{"negative", "1 -2554U 98067A   08264.51782528"},
+		{"Alpha-5 I", "1 I0001U 24001A   24061.00000000"},
+		{"Alpha-5 O", "1 O0001U 24001A   24061.00000000"},
+		{"Alpha-5 short", "1  A001U 24001A   24061.00000000"},
+		{"Alpha-5 lowercase", "1 a0001U 24001A   24061.00000000"},
+	}
+	for _, tt := range malformed {
+		tle := TLE{Line1: tt.line1}
+		if got, err := tle.GetNoradIDErr(); err == nil {
+			t.Errorf("%s: GetNoradIDErr(%q) = %d, want an error", tt.name, tt.line1, got)
+		}
+		if got := tle.GetNoradID(); got != 0 {
+			t.Errorf("%s: GetNoradID(%q) = %d, want 0", tt.name, tt.line1, got)
+		}
+	}
+}
+
+func TestMergeReportsDroppedTLEs(t *testing.T) {
+	bad := *issTLE()
+	bad.Line1 = "1 25X44" + bad.Line1[7:]
+	tles := []TLE{*issTLE(), bad, *geoTLE()}
+
+	sats, report := MergeSatelliteDataReport(tles, nil)
+	if len(sats) != 2 {
+		t.Errorf("merged %d satellites, want the 2 with valid IDs", len(sats))
+	}
+	if report.DroppedTLEs != 1 || len(report.Errors) != 1 {
+		t.Errorf("report = %d dropped, %v; want 1 dropped with its error", report.DroppedTLEs, report.Errors)
+	}
+}
+
+func TestSatelliteOrbitShape(t *testing.T) {
+	// Vanguard 1 (00005): a 34° orbit of about 650 x 3830 km, e = 0.1859667
+	const vanguardEcc = 0.1859667
+	vanguard := makeTLE(5, testEpoch, 34.25, 120, vanguardEcc, 200, 170, 10.85)
+	n := 10.85 * 2 * math.Pi / 86400
+	vanguardAxis := math.Cbrt(earthMu / (n * n))
+
+	// From the TLE alone the eccentricity is the TLE's own
+	sat := testSatellite("VANGUARD 1", vanguard)
+	assertNear(t, "TLE eccentricity", sat.Eccentricity(), vanguardEcc, 1e-9)
+	assertNear(t, "TLE semi-major axis", sat.SemiMajorAxis(), vanguardAxis, 1e-6)
+	assertNear(t, "TLE mean altitude", sat.MeanAltitude(), vanguardAxis-meanEarthRadiusKm, 1e-6)
+
+	// SATCAT apogee and perigee, rounded to the km, take precedence
+	satcat := &Satellite{NoradID: 5, Name: "VANGUARD 1", Period: 132.7, Apogee: 3834, Perigee: 654, TLE: vanguard}
+	assertNear(t, "SATCAT eccentricity", satcat.Eccentricity(), vanguardEcc, 0.002)
+	assertNear(t, "SATCAT semi-major axis", satcat.SemiMajorAxis(), (3834+654)/2.0+meanEarthRadiusKm, 1e-9)
+	assertNear(t, "SATCAT mean altitude", satcat.MeanAltitude(), (3834+654)/2.0, 1e-9)
+
+	// A circular orbit is distinguishable from a missing one
+	circular := testSatellite("CIRCULAR", makeTLE(30000, testEpoch, 98, 0, 0, 0, 0, 14.5))
+	if ecc := circular.Eccentricity(); math.Abs(ecc) > 1e-12 {
+		t.Errorf("circular orbit eccentricity = %v, want 0", ecc)
+	}
+	none := &Satellite{NoradID: 5, Name: "VANGUARD 1"}
+	for what, got := range map[string]float64{
+		"eccentricity":    none.Eccentricity(),
+		"semi-major axis": none.SemiMajorAxis(),
+		"mean altitude":   none.MeanAltitude(),
+	} {
+		if !math.IsNaN(got) {
+			t.Errorf("%s without orbit data = %v, want NaN", what, got)
+		}
+	}
+}
+
+func TestTLEElementGetters(t *testing.T) {
+	// A published ISS element set
+	tle := &TLE{
+		Line1: "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		Line2: "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+	}
+
+	getters := []struct {
+		name string
+		get  func() (float64, error)
+		want float64
+	}{
+		{"inclination", tle.GetInclination, 51.6416},
+		{"RAAN", tle.GetRAAN, 247.4627},
+		{"eccentricity", tle.GetEccentricity, 0.0006703},
+		{"argument of perigee", tle.GetArgPerigee, 130.5360},
+		{"mean anomaly", tle.GetMeanAnomaly, 325.0288},
+		{"mean motion", tle.GetMeanMotion, 15.72125391},
+	}
+	for _, g := range getters {
+		got, err := g.get()
+		if err != nil {
+			t.Errorf("%s: %v", g.name, err)
+			continue
+		}
+		assertNear(t, g.name, got, g.want, 1e-12)
+	}
+
+	malformed := map[string]string{
+		"short":              "2 25544  51.6416 247.4627",
+		"line 1 given":       tle.Line1,
+		"bad inclination":    "2 25544  51.64X6 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+		"blank eccentricity": "2 25544  51.6416 247.4627         130.5360 325.0288 15.72125391563537",
+		"bad mean motion":    "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.7212539X563537",
+	}
+	// Line 2 is parsed as a whole, so one bad field fails every getter
+	for name, line2 := range malformed {
+		bad := &TLE{Line1: tle.Line1, Line2: line2}
+		for _, get := range []func() (float64, error){bad.GetInclination, bad.GetEccentricity, bad.GetMeanMotion} {
+			if got, err := get(); !errors.Is(err, ErrTLEParse) {
+				t.Errorf("%s: getter = %v, %v; want ErrTLEParse", name, got, err)
+			}
+		}
+	}
+}
+
+func TestGetEpoch(t *testing.T) {
+	line1 := func(epoch string) string {
+		return "1 25544U 98067A   " + epoch + " -.00002182  00000-0 -11606-4 0  2927"
+	}
+
+	tests := []struct {
+		epoch string
+		want  time.Time
+	}{
+		{"57001.00000000", time.Date(1957, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"99365.50000000", time.Date(1999, 12, 31, 12, 0, 0, 0, time.UTC)},
+		{"00001.00000000", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"56366.99999999", time.Date(2056, 12, 31, 23, 59, 59, 999136000, time.UTC)},
+		{"24060.25000000", time.Date(2024, 2, 29, 6, 0, 0, 0, time.UTC)},
+		{"24366.75000000", time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)},
+		{"23365.99999999", time.Date(2023, 12, 31, 23, 59, 59, 999136000, time.UTC)},
+		// 0.00000001 day is 864 µs; rounding keeps it exact
+		{"08264.00000001", time.Date(2008, 9, 20, 0, 0, 0, 864000, time.UTC)},
+		{"08264.51782528", time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)},
+	}
+	for _, tt := range tests {
+		tle := &TLE{Line1: line1(tt.epoch)}
+		got, err := tle.GetEpoch()
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("GetEpoch(%s) = %v, %v; want %v", tt.epoch, got, err, tt.want)
+		}
+		if got.Nanosecond()%1000 != 0 {
+			t.Errorf("GetEpoch(%s) = %v, not rounded to the microsecond", tt.epoch, got)
+		}
+	}
+
+	for _, epoch := range []string{"24000.50000000", "24367.00000000", "2X001.00000000", "24abc.00000000"} {
+		tle := &TLE{Line1: line1(epoch)}
+		if got, err := tle.GetEpoch(); !errors.Is(err, ErrTLEParse) {
+			t.Errorf("GetEpoch(%s) = %v, %v; want ErrTLEParse", epoch, got, err)
+		}
+	}
+	if _, err := (&TLE{Line1: "1 25544U"}).GetEpoch(); !errors.Is(err, ErrTLEParse) {
+		t.Errorf("GetEpoch of a short line = %v, want ErrTLEParse", err)
+	}
+}
+
+func TestTLEAge(t *testing.T) {
+	if age := TLEAge(time.Now().Add(-36 * time.Hour)); age < 36*time.Hour || age > 37*time.Hour {
+		t.Errorf("TLEAge of an epoch 36h ago = %v", age)
+	}
+	if age := TLEAge(time.Now().Add(time.Hour)); age >= 0 {
+		t.Errorf("TLEAge of a future epoch = %v, want negative", age)
+	}
+}